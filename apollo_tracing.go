@@ -1,10 +1,25 @@
 package yarql
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 )
 
+// ApolloTracer is a Tracer that creates apollo tracing v1 traces
+// https://github.com/apollographql/apollo-tracing
+//
+// The trace is added to the extensions of the response under the tracing key.
+// ResolveOptions.Tracing uses an ApolloTracer that is reused for every request of the schema.
+// Note that apollo has deprecated this format, prefer exporting the spans of a custom Tracer.
+//
+// An ApolloTracer can only trace one operation at a time
+type ApolloTracer struct {
+	trace       tracer
+	stageStart  time.Time
+	fieldStarts []time.Time // stack of the start times of the fields that are being resolved
+}
+
 type tracer struct {
 	Version     uint8                  `json:"version"`
 	GoStartTime time.Time              `json:"-"`
@@ -34,29 +49,97 @@ type tracerResolver struct {
 	Duration    int64           `json:"duration"`
 }
 
-func newTracer() *tracer {
-	return &tracer{
-		Version:     1,
-		GoStartTime: time.Now(),
-		Execution: tracerExecution{
-			Resolvers: []tracerResolver{},
+// NewApolloTracer returns a new ApolloTracer
+func NewApolloTracer() *ApolloTracer {
+	return &ApolloTracer{
+		trace: tracer{
+			Version:     1,
+			GoStartTime: time.Now(),
+			Execution: tracerExecution{
+				Resolvers: []tracerResolver{},
+			},
 		},
 	}
 }
 
-func (t *tracer) reset() {
-	*t = tracer{
+func (t *ApolloTracer) offset(from time.Time) int64 {
+	return from.Sub(t.trace.GoStartTime).Nanoseconds()
+}
+
+// OperationStart implements Tracer
+func (t *ApolloTracer) OperationStart(ctx context.Context) context.Context {
+	t.trace = tracer{
 		Version:     1,
 		GoStartTime: time.Now(),
 		Execution: tracerExecution{
-			Resolvers: t.Execution.Resolvers[:0],
+			Resolvers: t.trace.Execution.Resolvers[:0],
 		},
 	}
+	t.fieldStarts = t.fieldStarts[:0]
+	return ctx
 }
 
-func (t *tracer) finish() {
-	t.StartTime = t.GoStartTime.Format(time.RFC3339Nano)
+// OperationEnd implements Tracer
+func (t *ApolloTracer) OperationEnd(ctx context.Context, operation OperationInfo) {
+	t.trace.StartTime = t.trace.GoStartTime.Format(time.RFC3339Nano)
 	now := time.Now()
-	t.EndTime = now.Format(time.RFC3339Nano)
-	t.Duration = now.Sub(t.GoStartTime).Nanoseconds()
+	t.trace.EndTime = now.Format(time.RFC3339Nano)
+	t.trace.Duration = now.Sub(t.trace.GoStartTime).Nanoseconds()
+}
+
+// ParseStart implements Tracer
+func (t *ApolloTracer) ParseStart(ctx context.Context) {
+	t.stageStart = time.Now()
+}
+
+// ParseEnd implements Tracer
+func (t *ApolloTracer) ParseEnd(ctx context.Context, errs []error) {
+	t.trace.Parsing = tracerStartAndDuration{
+		StartOffset: t.offset(t.stageStart),
+		Duration:    time.Since(t.stageStart).Nanoseconds(),
+	}
+}
+
+// ValidationStart implements Tracer
+func (t *ApolloTracer) ValidationStart(ctx context.Context) {
+	t.stageStart = time.Now()
+}
+
+// ValidationEnd implements Tracer
+// The validation is done while resolving so only the start offset is reported
+func (t *ApolloTracer) ValidationEnd(ctx context.Context, errs []error) {
+	t.trace.Validation.StartOffset = t.offset(t.stageStart)
+}
+
+// FieldStart implements Tracer
+func (t *ApolloTracer) FieldStart(ctx context.Context, field FieldInfo) context.Context {
+	t.fieldStarts = append(t.fieldStarts, time.Now())
+	return ctx
+}
+
+// FieldEnd implements Tracer
+func (t *ApolloTracer) FieldEnd(ctx context.Context, field FieldInfo) {
+	if len(t.fieldStarts) == 0 {
+		return
+	}
+	start := t.fieldStarts[len(t.fieldStarts)-1]
+	t.fieldStarts = t.fieldStarts[:len(t.fieldStarts)-1]
+
+	t.trace.Execution.Resolvers = append(t.trace.Execution.Resolvers, tracerResolver{
+		Path:        json.RawMessage(field.Path),
+		ParentType:  field.ParentType,
+		FieldName:   field.FieldName,
+		ReturnType:  field.ReturnType,
+		StartOffset: t.offset(start),
+		Duration:    time.Since(start).Nanoseconds(),
+	})
+}
+
+// Extension implements TracerExtension
+func (t *ApolloTracer) Extension() (string, json.RawMessage) {
+	tracingJSON, err := json.Marshal(t.trace)
+	if err != nil {
+		return "tracing", nil
+	}
+	return "tracing", tracingJSON
 }
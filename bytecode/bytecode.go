@@ -24,7 +24,9 @@ type ParserCtx struct {
 	TargetIdx            int // -1 = no matching target was found, >= 0 = res index of target
 	Hasher               hash.Hash32
 	cache                *cache.BytecodeCache
-	CacheableQueryMinLen int // Default = 300
+	CacheableQueryMinLen int  // Default = 300
+	CacheLookup          bool // the last query was long enough to be looked up in the cache
+	CacheHit             bool // the bytecode of the last query was read from the cache
 }
 
 // NewParserCtx returns a new instance of ParserCtx
@@ -58,8 +60,10 @@ func (ctx *ParserCtx) ParseQueryToBytecode(target *string) {
 
 	cacheableQuery := len(ctx.Query) > ctx.CacheableQueryMinLen
 	if cacheableQuery {
+		ctx.CacheLookup = true
 		res, fragmentLocations, targetIdx := ctx.cache.GetEntry(ctx.Query, target)
 		if res != nil {
+			ctx.CacheHit = true
 			ctx.Res = append(ctx.Res, res...)
 			ctx.FragmentLocations = append(ctx.FragmentLocations, fragmentLocations...)
 			ctx.TargetIdx = targetIdx
@@ -1269,8 +1273,18 @@ func (e ErrorWLocation) Error() string {
 }
 
 func (ctx *ParserCtx) err(err string) bool {
-	line := uint(1)
-	column := uint(0)
+	line, column := ctx.location()
+	ctx.Errors = append(ctx.Errors, ErrorWLocation{
+		errors.New(err),
+		line,
+		column,
+	})
+	return true
+}
+
+// location returns the line and column of the current char
+func (ctx *ParserCtx) location() (line uint, column uint) {
+	line = 1
 	for idx, char := range ctx.Query {
 		if idx == ctx.charNr {
 			break
@@ -1291,13 +1305,7 @@ func (ctx *ParserCtx) err(err string) bool {
 			column++
 		}
 	}
-
-	ctx.Errors = append(ctx.Errors, ErrorWLocation{
-		errors.New(err),
-		line,
-		uint(column),
-	})
-	return true
+	return line, column
 }
 
 func (ctx *ParserCtx) unexpectedEOF() bool {
@@ -0,0 +1,1034 @@
+package bytecode
+
+import (
+	"strconv"
+	"strings"
+)
+
+//
+// Type system documents (SDL)
+// https://spec.graphql.org/October2021/#sec-Type-System
+//
+// Unlike queries a type system document is not converted into bytecode as it's not used on the hot path,
+// instead it's parsed into the tree below using the same lexer helpers as the query parser
+//
+
+// TypeSystemDocument is a parsed GraphQL type system document
+type TypeSystemDocument struct {
+	Schema     *SchemaDefinition // nil if the document has no schema definition
+	Types      []TypeDefinition
+	Directives []DirectiveDefinition
+}
+
+// Location is the location of a definition inside the document
+type Location struct {
+	Line   uint
+	Column uint
+}
+
+// SchemaDefinition represents:
+//   schema { query: Query mutation: Mutation }
+type SchemaDefinition struct {
+	Description  string
+	Query        string
+	Mutation     string
+	Subscription string
+	Directives   []Directive
+	Location     Location
+}
+
+// TypeDefinitionKind defines the kind of a type definition
+type TypeDefinitionKind uint8
+
+// All possible type definition kinds
+const (
+	TypeDefinitionScalar TypeDefinitionKind = iota
+	TypeDefinitionObject
+	TypeDefinitionInterface
+	TypeDefinitionUnion
+	TypeDefinitionEnum
+	TypeDefinitionInputObject
+)
+
+// String returns the keyword used to define the kind
+func (k TypeDefinitionKind) String() string {
+	switch k {
+	case TypeDefinitionScalar:
+		return "scalar"
+	case TypeDefinitionObject:
+		return "type"
+	case TypeDefinitionInterface:
+		return "interface"
+	case TypeDefinitionUnion:
+		return "union"
+	case TypeDefinitionEnum:
+		return "enum"
+	case TypeDefinitionInputObject:
+		return "input"
+	default:
+		return "unknown"
+	}
+}
+
+// TypeDefinition represents a scalar, type, interface, union, enum or input definition
+type TypeDefinition struct {
+	Kind        TypeDefinitionKind
+	Name        string
+	Description string
+	Directives  []Directive
+	Location    Location
+
+	// Kind == TypeDefinitionObject || TypeDefinitionInterface
+	Interfaces []string
+	Fields     []FieldDefinition
+
+	// Kind == TypeDefinitionUnion
+	Types []string
+
+	// Kind == TypeDefinitionEnum
+	EnumValues []EnumValueDefinition
+
+	// Kind == TypeDefinitionInputObject
+	InputFields []InputValueDefinition
+}
+
+// FieldDefinition represents a field of a type or interface
+type FieldDefinition struct {
+	Name        string
+	Description string
+	Arguments   []InputValueDefinition
+	Type        TypeReference
+	Directives  []Directive
+	Location    Location
+}
+
+// InputValueDefinition represents an argument or a field of an input
+type InputValueDefinition struct {
+	Name         string
+	Description  string
+	Type         TypeReference
+	DefaultValue *Value // nil if there is no default value
+	Directives   []Directive
+	Location     Location
+}
+
+// EnumValueDefinition represents a value of an enum
+type EnumValueDefinition struct {
+	Name        string
+	Description string
+	Directives  []Directive
+	Location    Location
+}
+
+// DirectiveDefinition represents:
+//   directive @name(arguments) repeatable on LOCATION | LOCATION
+type DirectiveDefinition struct {
+	Name        string
+	Description string
+	Arguments   []InputValueDefinition
+	Repeatable  bool
+	Locations   []string
+	Location    Location
+}
+
+// Directive represents a directive applied to a definition, like @deprecated(reason: "foo")
+type Directive struct {
+	Name      string
+	Arguments []ObjectField
+	Location  Location
+}
+
+// Argument returns the value of the argument with name or nil if the argument is not set
+func (d Directive) Argument(name string) *Value {
+	for _, arg := range d.Arguments {
+		if arg.Name == name {
+			return &arg.Value
+		}
+	}
+	return nil
+}
+
+// FindDirective returns the directive with name from directives or nil if it's not found
+func FindDirective(directives []Directive, name string) *Directive {
+	for idx := range directives {
+		if directives[idx].Name == name {
+			return &directives[idx]
+		}
+	}
+	return nil
+}
+
+// TypeReference is a reference to a type like String, [Int!] or Foo!
+type TypeReference struct {
+	Name    string         // Set if this is a named type
+	List    *TypeReference // Set if this is a list type
+	NonNull bool
+}
+
+// String returns the type reference as written in graphql
+func (t TypeReference) String() string {
+	res := t.Name
+	if t.List != nil {
+		res = "[" + t.List.String() + "]"
+	}
+	if t.NonNull {
+		res += "!"
+	}
+	return res
+}
+
+// NamedType returns the name of the inner most type
+func (t TypeReference) NamedType() string {
+	for t.List != nil {
+		t = *t.List
+	}
+	return t.Name
+}
+
+// Value is a parsed input value
+type Value struct {
+	Kind ValueKind
+
+	// The contents of a ValueInt, ValueFloat, ValueString, ValueEnum and ValueVariable
+	// For ValueBoolean this is "true" or "false"
+	Value string
+
+	// Kind == ValueList
+	List []Value
+
+	// Kind == ValueObject
+	Fields []ObjectField
+}
+
+// ObjectField is a field of an object value or an argument of a directive
+type ObjectField struct {
+	Name  string
+	Value Value
+}
+
+// String returns the value as written in graphql
+func (v Value) String() string {
+	switch v.Kind {
+	case ValueVariable:
+		return "$" + v.Value
+	case ValueString:
+		return QuoteString(v.Value)
+	case ValueNull:
+		return "null"
+	case ValueList:
+		items := make([]string, len(v.List))
+		for idx, item := range v.List {
+			items[idx] = item.String()
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case ValueObject:
+		fields := make([]string, len(v.Fields))
+		for idx, field := range v.Fields {
+			fields[idx] = field.Name + ": " + field.Value.String()
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return v.Value
+	}
+}
+
+// QuoteString returns s as a graphql string value
+func QuoteString(s string) string {
+	res := strings.Builder{}
+	res.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"':
+			res.WriteString(`\"`)
+		case '\\':
+			res.WriteString(`\\`)
+		case '\b':
+			res.WriteString(`\b`)
+		case '\f':
+			res.WriteString(`\f`)
+		case '\n':
+			res.WriteString(`\n`)
+		case '\r':
+			res.WriteString(`\r`)
+		case '\t':
+			res.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				hex := strconv.FormatInt(int64(c), 16)
+				res.WriteString(`\u` + strings.Repeat("0", 4-len(hex)) + hex)
+			} else {
+				res.WriteRune(c)
+			}
+		}
+	}
+	res.WriteByte('"')
+	return res.String()
+}
+
+type sdlParser struct {
+	ctx *ParserCtx
+
+	// used to incrementally calculate the location
+	locationCharNr int
+	location       Location
+}
+
+// ParseSDL parses a GraphQL type system document
+//
+// Type system extensions (extend type ..) are not supported
+func ParseSDL(sdl []byte) (*TypeSystemDocument, []error) {
+	p := sdlParser{
+		ctx: &ParserCtx{
+			Res:    make([]byte, 0, 256),
+			Query:  sdl,
+			Errors: []error{},
+		},
+		location: Location{Line: 1},
+	}
+
+	doc := &TypeSystemDocument{
+		Types:      []TypeDefinition{},
+		Directives: []DirectiveDefinition{},
+	}
+	for {
+		stop := p.parseDefinition(doc)
+		if stop {
+			break
+		}
+	}
+
+	if len(p.ctx.Errors) > 0 {
+		return nil, p.ctx.Errors
+	}
+	return doc, nil
+}
+
+// ParseValue parses a single constant input value like 10, "foo" or {bar: [BAZ]}
+func ParseValue(value []byte) (*Value, error) {
+	// Add a trailing space as the number parser expects the query to continue after a number
+	query := make([]byte, len(value)+1)
+	copy(query, value)
+	query[len(value)] = ' '
+
+	p := sdlParser{
+		ctx: &ParserCtx{
+			Res:    make([]byte, 0, 64),
+			Query:  query,
+			Errors: []error{},
+		},
+		location: Location{Line: 1},
+	}
+
+	res, criticalErr := p.parseValue()
+	if !criticalErr {
+		_, eof := p.skipIgnored()
+		if !eof {
+			p.ctx.err("unexpected character after value: \"" + string(p.ctx.currentC()) + "\"")
+		}
+	}
+	if len(p.ctx.Errors) > 0 {
+		return nil, p.ctx.Errors[0]
+	}
+	return &res, nil
+}
+
+// skipIgnored skips all ignored tokens including commas
+func (p *sdlParser) skipIgnored() (nextC byte, eof bool) {
+	for {
+		c, eof := p.ctx.mightIgnoreNextTokens()
+		if eof || c != ',' {
+			return c, eof
+		}
+		p.ctx.charNr++
+	}
+}
+
+func (p *sdlParser) currentLocation() Location {
+	query := p.ctx.Query
+	for idx := p.locationCharNr; idx < p.ctx.charNr && idx < len(query); idx++ {
+		switch query[idx] {
+		case '\n':
+			if p.location.Column == 0 && idx > 0 && query[idx-1] == '\r' {
+				// don't count \r\n as 2 lines
+				continue
+			}
+			p.location.Line++
+			p.location.Column = 0
+		case '\r':
+			p.location.Line++
+			p.location.Column = 0
+		default:
+			p.location.Column++
+		}
+	}
+	p.locationCharNr = p.ctx.charNr
+	return p.location
+}
+
+// expect skips the ignored tokens and checks if the next char is c
+func (p *sdlParser) expect(c byte) bool {
+	next, eof := p.skipIgnored()
+	if eof {
+		return p.ctx.unexpectedEOF()
+	}
+	if next != c {
+		return p.ctx.err("expected \"" + string(c) + "\" but got \"" + string(next) + "\"")
+	}
+	p.ctx.charNr++
+	return false
+}
+
+// parseName skips the ignored tokens and parses a name
+func (p *sdlParser) parseName(of string) (string, bool) {
+	_, eof := p.skipIgnored()
+	if eof {
+		return "", p.ctx.unexpectedEOF()
+	}
+
+	start := p.ctx.charNr
+	for {
+		c, eof := p.ctx.checkC(p.ctx.charNr)
+		if eof {
+			break
+		}
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || (p.ctx.charNr > start && c >= '0' && c <= '9') {
+			p.ctx.charNr++
+			continue
+		}
+		break
+	}
+
+	if start == p.ctx.charNr {
+		return "", p.ctx.err("expected " + of + " name but got \"" + string(p.ctx.currentC()) + "\"")
+	}
+	return string(p.ctx.Query[start:p.ctx.charNr]), false
+}
+
+// parseString parses a string or block string, expects the current char to be a "
+func (p *sdlParser) parseString() (string, bool) {
+	p.ctx.Res = p.ctx.Res[:0]
+	criticalErr := p.ctx.parseStringInputValue()
+	if criticalErr {
+		return "", criticalErr
+	}
+	// Skip over the value instruction written by instructionNewValueString
+	return string(p.ctx.Res[7:]), false
+}
+
+// parseDescription parses the optional description in front of a definition
+func (p *sdlParser) parseDescription() (string, bool) {
+	c, eof := p.skipIgnored()
+	if eof || c != '"' {
+		return "", false
+	}
+	return p.parseString()
+}
+
+func (p *sdlParser) parseDefinition(doc *TypeSystemDocument) (stop bool) {
+	_, eof := p.skipIgnored()
+	if eof {
+		return true
+	}
+
+	description, criticalErr := p.parseDescription()
+	if criticalErr {
+		return criticalErr
+	}
+
+	_, eof = p.skipIgnored()
+	if eof {
+		return p.ctx.unexpectedEOF()
+	}
+
+	location := p.currentLocation()
+	keyword := p.ctx.matchesWord("schema", "scalar", "type", "interface", "union", "enum", "input", "directive", "extend")
+	switch keyword {
+	case 0:
+		if doc.Schema != nil {
+			return p.ctx.err("a document can only contain one schema definition")
+		}
+		schema, criticalErr := p.parseSchemaDefinition()
+		if criticalErr {
+			return criticalErr
+		}
+		schema.Description = description
+		schema.Location = location
+		doc.Schema = &schema
+		return false
+	case 7:
+		directive, criticalErr := p.parseDirectiveDefinition()
+		if criticalErr {
+			return criticalErr
+		}
+		directive.Description = description
+		directive.Location = location
+		doc.Directives = append(doc.Directives, directive)
+		return false
+	case 8:
+		return p.ctx.err("type system extensions are not supported")
+	case -1:
+		if p.ctx.currentC() == '{' {
+			return p.ctx.err("executable definitions are not allowed in a type system document")
+		}
+		return p.ctx.err("expected a type system definition")
+	}
+
+	kinds := []TypeDefinitionKind{
+		1: TypeDefinitionScalar,
+		2: TypeDefinitionObject,
+		3: TypeDefinitionInterface,
+		4: TypeDefinitionUnion,
+		5: TypeDefinitionEnum,
+		6: TypeDefinitionInputObject,
+	}
+	definition := TypeDefinition{
+		Kind:        kinds[keyword],
+		Description: description,
+		Location:    location,
+	}
+	definition.Name, criticalErr = p.parseName(definition.Kind.String())
+	if criticalErr {
+		return criticalErr
+	}
+
+	switch definition.Kind {
+	case TypeDefinitionObject, TypeDefinitionInterface:
+		criticalErr = p.parseObjectDefinition(&definition)
+	case TypeDefinitionUnion:
+		criticalErr = p.parseUnionDefinition(&definition)
+	case TypeDefinitionEnum:
+		criticalErr = p.parseEnumDefinition(&definition)
+	case TypeDefinitionInputObject:
+		criticalErr = p.parseInputObjectDefinition(&definition)
+	default:
+		definition.Directives, criticalErr = p.parseDirectives()
+	}
+	if criticalErr {
+		return criticalErr
+	}
+
+	doc.Types = append(doc.Types, definition)
+	return false
+}
+
+func (p *sdlParser) parseSchemaDefinition() (res SchemaDefinition, criticalErr bool) {
+	res.Directives, criticalErr = p.parseDirectives()
+	if criticalErr {
+		return
+	}
+
+	criticalErr = p.expect('{')
+	if criticalErr {
+		return
+	}
+
+	for {
+		c, eof := p.skipIgnored()
+		if eof {
+			return res, p.ctx.unexpectedEOF()
+		}
+		if c == '}' {
+			p.ctx.charNr++
+			break
+		}
+
+		operation := p.ctx.matchesWord("query", "mutation", "subscription")
+		if operation == -1 {
+			return res, p.ctx.err("expected query, mutation or subscription")
+		}
+
+		criticalErr = p.expect(':')
+		if criticalErr {
+			return
+		}
+
+		name, criticalErr := p.parseName("type")
+		if criticalErr {
+			return res, criticalErr
+		}
+
+		switch operation {
+		case 0:
+			res.Query = name
+		case 1:
+			res.Mutation = name
+		case 2:
+			res.Subscription = name
+		}
+	}
+
+	if res.Query == "" {
+		return res, p.ctx.err("schema definition must define a query type")
+	}
+	return res, false
+}
+
+func (p *sdlParser) parseDirectiveDefinition() (res DirectiveDefinition, criticalErr bool) {
+	criticalErr = p.expect('@')
+	if criticalErr {
+		return
+	}
+
+	// The directive name must directly follow the @
+	c, eof := p.ctx.checkC(p.ctx.charNr)
+	if eof {
+		return res, p.ctx.unexpectedEOF()
+	}
+	if p.ctx.isIgnoredToken(c) {
+		return res, p.ctx.err("directive name must directly follow the @")
+	}
+
+	res.Name, criticalErr = p.parseName("directive")
+	if criticalErr {
+		return
+	}
+
+	res.Arguments, criticalErr = p.parseArgumentsDefinition()
+	if criticalErr {
+		return
+	}
+
+	_, eof = p.skipIgnored()
+	if eof {
+		return res, p.ctx.unexpectedEOF()
+	}
+	if p.ctx.matchesWord("repeatable") == 0 {
+		res.Repeatable = true
+		_, eof = p.skipIgnored()
+		if eof {
+			return res, p.ctx.unexpectedEOF()
+		}
+	}
+
+	if p.ctx.matchesWord("on") != 0 {
+		return res, p.ctx.err("expected \"on\" followed by the directive locations")
+	}
+
+	res.Locations, criticalErr = p.parseNameList('|', "directive location")
+	return
+}
+
+// parseNameList parses a list of names separated by separator, the list might start with the separator
+// Used for union members (A | B), directive locations (A | B) and interfaces (A & B)
+func (p *sdlParser) parseNameList(separator byte, of string) ([]string, bool) {
+	res := []string{}
+
+	c, eof := p.skipIgnored()
+	if !eof && c == separator {
+		p.ctx.charNr++
+	}
+
+	for {
+		name, criticalErr := p.parseName(of)
+		if criticalErr {
+			return nil, criticalErr
+		}
+		res = append(res, name)
+
+		c, eof := p.skipIgnored()
+		if eof || c != separator {
+			return res, false
+		}
+		p.ctx.charNr++
+	}
+}
+
+func (p *sdlParser) parseObjectDefinition(res *TypeDefinition) (criticalErr bool) {
+	res.Interfaces = []string{}
+	res.Fields = []FieldDefinition{}
+
+	_, eof := p.skipIgnored()
+	if eof {
+		return false
+	}
+	if p.ctx.matchesWord("implements") == 0 {
+		res.Interfaces, criticalErr = p.parseNameList('&', "interface")
+		if criticalErr {
+			return criticalErr
+		}
+	}
+
+	res.Directives, criticalErr = p.parseDirectives()
+	if criticalErr {
+		return criticalErr
+	}
+
+	c, eof := p.skipIgnored()
+	if eof || c != '{' {
+		// A type without fields
+		return false
+	}
+	p.ctx.charNr++
+
+	for {
+		c, eof := p.skipIgnored()
+		if eof {
+			return p.ctx.unexpectedEOF()
+		}
+		if c == '}' {
+			p.ctx.charNr++
+			return false
+		}
+
+		field := FieldDefinition{}
+		field.Description, criticalErr = p.parseDescription()
+		if criticalErr {
+			return criticalErr
+		}
+		_, eof = p.skipIgnored()
+		if eof {
+			return p.ctx.unexpectedEOF()
+		}
+		field.Location = p.currentLocation()
+
+		field.Name, criticalErr = p.parseName("field")
+		if criticalErr {
+			return criticalErr
+		}
+
+		field.Arguments, criticalErr = p.parseArgumentsDefinition()
+		if criticalErr {
+			return criticalErr
+		}
+
+		criticalErr = p.expect(':')
+		if criticalErr {
+			return criticalErr
+		}
+
+		field.Type, criticalErr = p.parseTypeReference()
+		if criticalErr {
+			return criticalErr
+		}
+
+		field.Directives, criticalErr = p.parseDirectives()
+		if criticalErr {
+			return criticalErr
+		}
+
+		res.Fields = append(res.Fields, field)
+	}
+}
+
+func (p *sdlParser) parseUnionDefinition(res *TypeDefinition) (criticalErr bool) {
+	res.Directives, criticalErr = p.parseDirectives()
+	if criticalErr {
+		return criticalErr
+	}
+
+	c, eof := p.skipIgnored()
+	if eof || c != '=' {
+		res.Types = []string{}
+		return false
+	}
+	p.ctx.charNr++
+
+	res.Types, criticalErr = p.parseNameList('|', "union member")
+	return criticalErr
+}
+
+func (p *sdlParser) parseEnumDefinition(res *TypeDefinition) (criticalErr bool) {
+	res.EnumValues = []EnumValueDefinition{}
+
+	res.Directives, criticalErr = p.parseDirectives()
+	if criticalErr {
+		return criticalErr
+	}
+
+	c, eof := p.skipIgnored()
+	if eof || c != '{' {
+		return false
+	}
+	p.ctx.charNr++
+
+	for {
+		c, eof := p.skipIgnored()
+		if eof {
+			return p.ctx.unexpectedEOF()
+		}
+		if c == '}' {
+			p.ctx.charNr++
+			return false
+		}
+
+		value := EnumValueDefinition{}
+		value.Description, criticalErr = p.parseDescription()
+		if criticalErr {
+			return criticalErr
+		}
+		_, eof = p.skipIgnored()
+		if eof {
+			return p.ctx.unexpectedEOF()
+		}
+		value.Location = p.currentLocation()
+
+		if p.ctx.matchesWord("true", "false", "null") != -1 {
+			return p.ctx.err("enum values cannot be named true, false or null")
+		}
+
+		value.Name, criticalErr = p.parseName("enum value")
+		if criticalErr {
+			return criticalErr
+		}
+
+		value.Directives, criticalErr = p.parseDirectives()
+		if criticalErr {
+			return criticalErr
+		}
+
+		res.EnumValues = append(res.EnumValues, value)
+	}
+}
+
+func (p *sdlParser) parseInputObjectDefinition(res *TypeDefinition) (criticalErr bool) {
+	res.InputFields = []InputValueDefinition{}
+
+	res.Directives, criticalErr = p.parseDirectives()
+	if criticalErr {
+		return criticalErr
+	}
+
+	c, eof := p.skipIgnored()
+	if eof || c != '{' {
+		return false
+	}
+	p.ctx.charNr++
+
+	res.InputFields, criticalErr = p.parseInputValueDefinitions('}')
+	return criticalErr
+}
+
+// parseArgumentsDefinition parses the optional (a: String = "b", c: Int) of a field or directive
+func (p *sdlParser) parseArgumentsDefinition() ([]InputValueDefinition, bool) {
+	c, eof := p.skipIgnored()
+	if eof || c != '(' {
+		return []InputValueDefinition{}, false
+	}
+	p.ctx.charNr++
+
+	return p.parseInputValueDefinitions(')')
+}
+
+func (p *sdlParser) parseInputValueDefinitions(closure byte) ([]InputValueDefinition, bool) {
+	res := []InputValueDefinition{}
+	for {
+		c, eof := p.skipIgnored()
+		if eof {
+			return nil, p.ctx.unexpectedEOF()
+		}
+		if c == closure {
+			p.ctx.charNr++
+			return res, false
+		}
+
+		value, criticalErr := p.parseInputValueDefinition()
+		if criticalErr {
+			return nil, criticalErr
+		}
+		res = append(res, value)
+	}
+}
+
+func (p *sdlParser) parseInputValueDefinition() (res InputValueDefinition, criticalErr bool) {
+	res.Description, criticalErr = p.parseDescription()
+	if criticalErr {
+		return
+	}
+	_, eof := p.skipIgnored()
+	if eof {
+		return res, p.ctx.unexpectedEOF()
+	}
+	res.Location = p.currentLocation()
+
+	res.Name, criticalErr = p.parseName("input value")
+	if criticalErr {
+		return
+	}
+
+	criticalErr = p.expect(':')
+	if criticalErr {
+		return
+	}
+
+	res.Type, criticalErr = p.parseTypeReference()
+	if criticalErr {
+		return
+	}
+
+	c, eof := p.skipIgnored()
+	if eof {
+		return res, p.ctx.unexpectedEOF()
+	}
+	if c == '=' {
+		p.ctx.charNr++
+		value, criticalErr := p.parseValue()
+		if criticalErr {
+			return res, criticalErr
+		}
+		res.DefaultValue = &value
+	}
+
+	res.Directives, criticalErr = p.parseDirectives()
+	return
+}
+
+func (p *sdlParser) parseTypeReference() (res TypeReference, criticalErr bool) {
+	c, eof := p.skipIgnored()
+	if eof {
+		return res, p.ctx.unexpectedEOF()
+	}
+
+	if c == '[' {
+		p.ctx.charNr++
+		inner, criticalErr := p.parseTypeReference()
+		if criticalErr {
+			return res, criticalErr
+		}
+		res.List = &inner
+
+		criticalErr = p.expect(']')
+		if criticalErr {
+			return res, criticalErr
+		}
+	} else {
+		res.Name, criticalErr = p.parseName("type")
+		if criticalErr {
+			return
+		}
+	}
+
+	c, eof = p.skipIgnored()
+	if !eof && c == '!' {
+		p.ctx.charNr++
+		res.NonNull = true
+	}
+	return res, false
+}
+
+// parseDirectives parses the optional directives applied to a definition
+func (p *sdlParser) parseDirectives() ([]Directive, bool) {
+	res := []Directive{}
+	for {
+		c, eof := p.skipIgnored()
+		if eof || c != '@' {
+			return res, false
+		}
+
+		directive := Directive{Location: p.currentLocation()}
+		p.ctx.charNr++
+
+		var criticalErr bool
+		directive.Name, criticalErr = p.parseName("directive")
+		if criticalErr {
+			return nil, criticalErr
+		}
+
+		directive.Arguments = []ObjectField{}
+		c, eof = p.skipIgnored()
+		if !eof && c == '(' {
+			p.ctx.charNr++
+			directive.Arguments, criticalErr = p.parseObjectFields(')')
+			if criticalErr {
+				return nil, criticalErr
+			}
+		}
+
+		res = append(res, directive)
+	}
+}
+
+// parseObjectFields parses the key value pairs of an object value or the arguments of a directive
+func (p *sdlParser) parseObjectFields(closure byte) ([]ObjectField, bool) {
+	res := []ObjectField{}
+	for {
+		c, eof := p.skipIgnored()
+		if eof {
+			return nil, p.ctx.unexpectedEOF()
+		}
+		if c == closure {
+			p.ctx.charNr++
+			return res, false
+		}
+
+		name, criticalErr := p.parseName("field")
+		if criticalErr {
+			return nil, criticalErr
+		}
+
+		criticalErr = p.expect(':')
+		if criticalErr {
+			return nil, criticalErr
+		}
+
+		value, criticalErr := p.parseValue()
+		if criticalErr {
+			return nil, criticalErr
+		}
+
+		res = append(res, ObjectField{Name: name, Value: value})
+	}
+}
+
+// parseValue parses a constant value, variables are not allowed in type system documents
+func (p *sdlParser) parseValue() (res Value, criticalErr bool) {
+	c, eof := p.skipIgnored()
+	if eof {
+		return res, p.ctx.unexpectedEOF()
+	}
+
+	switch {
+	case c == '$':
+		return res, p.ctx.err("variables are not allowed here")
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		p.ctx.Res = p.ctx.Res[:0]
+		criticalErr = p.ctx.parseNumberInputValue()
+		if criticalErr {
+			return
+		}
+		// The value kind is written by the number parser at index 2 and the number itself starts at index 7
+		res.Kind = p.ctx.Res[2]
+		res.Value = string(p.ctx.Res[7:])
+	case c == '"':
+		res.Kind = ValueString
+		res.Value, criticalErr = p.parseString()
+	case c == '[':
+		p.ctx.charNr++
+		res.Kind = ValueList
+		res.List = []Value{}
+		for {
+			c, eof := p.skipIgnored()
+			if eof {
+				return res, p.ctx.unexpectedEOF()
+			}
+			if c == ']' {
+				p.ctx.charNr++
+				return res, false
+			}
+
+			item, criticalErr := p.parseValue()
+			if criticalErr {
+				return res, criticalErr
+			}
+			res.List = append(res.List, item)
+		}
+	case c == '{':
+		p.ctx.charNr++
+		res.Kind = ValueObject
+		res.Fields, criticalErr = p.parseObjectFields('}')
+	default:
+		switch p.ctx.matchesWord("true", "false", "null") {
+		case 0:
+			res.Kind = ValueBoolean
+			res.Value = "true"
+		case 1:
+			res.Kind = ValueBoolean
+			res.Value = "false"
+		case 2:
+			res.Kind = ValueNull
+		default:
+			res.Kind = ValueEnum
+			res.Value, criticalErr = p.parseName("enum value")
+		}
+	}
+	return
+}
@@ -0,0 +1,186 @@
+package bytecode
+
+import (
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+func parseSDLAndExpectNoErrs(t *testing.T, sdl string) *TypeSystemDocument {
+	doc, errs := ParseSDL([]byte(sdl))
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func parseSDLAndExpectErr(t *testing.T, sdl, expectedErr string) {
+	_, errs := ParseSDL([]byte(sdl))
+	if len(errs) == 0 {
+		a.Fail(t, "exected sdl to fail with error: "+expectedErr, sdl)
+	}
+	a.Equal(t, expectedErr, errs[0].Error())
+}
+
+func TestParseSDLEmpty(t *testing.T) {
+	doc := parseSDLAndExpectNoErrs(t, "")
+	a.Nil(t, doc.Schema)
+	a.Equal(t, 0, len(doc.Types))
+	a.Equal(t, 0, len(doc.Directives))
+
+	doc = parseSDLAndExpectNoErrs(t, "  # just a comment\n")
+	a.Equal(t, 0, len(doc.Types))
+}
+
+func TestParseSDLSchemaDefinition(t *testing.T) {
+	doc := parseSDLAndExpectNoErrs(t, `schema { query: QueryRoot mutation: MethodRoot }`)
+	a.NotNil(t, doc.Schema)
+	a.Equal(t, "QueryRoot", doc.Schema.Query)
+	a.Equal(t, "MethodRoot", doc.Schema.Mutation)
+	a.Equal(t, "", doc.Schema.Subscription)
+
+	parseSDLAndExpectErr(t, `schema { mutation: M }`, "schema definition must define a query type")
+	parseSDLAndExpectErr(t, `schema { query: Q } schema { query: Q }`, "a document can only contain one schema definition")
+}
+
+func TestParseSDLObject(t *testing.T) {
+	doc := parseSDLAndExpectNoErrs(t, `
+		"A post"
+		type Post implements Node & Entity @key(fields: "id") {
+			id: ID!
+			"""
+			The title of the post
+			"""
+			title(uppercase: Boolean = false, limit: Int): String @deprecated(reason: "use name")
+			tags: [String!]!
+		}
+	`)
+	a.Equal(t, 1, len(doc.Types))
+
+	post := doc.Types[0]
+	a.Equal(t, TypeDefinitionObject, post.Kind)
+	a.Equal(t, "Post", post.Name)
+	a.Equal(t, "A post", post.Description)
+	a.Equal(t, []string{"Node", "Entity"}, post.Interfaces)
+	a.Equal(t, 1, len(post.Directives))
+	a.Equal(t, "key", post.Directives[0].Name)
+	a.Equal(t, `"id"`, post.Directives[0].Argument("fields").String())
+	a.Equal(t, uint(3), post.Location.Line)
+
+	a.Equal(t, 3, len(post.Fields))
+	a.Equal(t, "id", post.Fields[0].Name)
+	a.Equal(t, "ID!", post.Fields[0].Type.String())
+	a.Equal(t, uint(4), post.Fields[0].Location.Line)
+
+	title := post.Fields[1]
+	a.Equal(t, "The title of the post", title.Description)
+	a.Equal(t, "String", title.Type.String())
+	a.Equal(t, 2, len(title.Arguments))
+	a.Equal(t, "uppercase", title.Arguments[0].Name)
+	a.NotNil(t, title.Arguments[0].DefaultValue)
+	a.Equal(t, "false", title.Arguments[0].DefaultValue.String())
+	a.Nil(t, title.Arguments[1].DefaultValue)
+	deprecated := FindDirective(title.Directives, "deprecated")
+	a.NotNil(t, deprecated)
+	a.Equal(t, "use name", deprecated.Argument("reason").Value)
+
+	a.Equal(t, "[String!]!", post.Fields[2].Type.String())
+	a.Equal(t, "String", post.Fields[2].Type.NamedType())
+}
+
+func TestParseSDLOtherDefinitions(t *testing.T) {
+	doc := parseSDLAndExpectNoErrs(t, `
+		scalar Time @specifiedBy(url: "https://en.wikipedia.org/wiki/ISO_8601")
+		interface Node { id: ID! }
+		union SearchResult = | Post | User
+		enum Fruit {
+			"Red or green"
+			APPLE
+			PEER @deprecated
+		}
+		input PostInput {
+			title: String! = "untitled"
+			fruits: [Fruit!] = [APPLE]
+			meta: Meta = {a: 1, b: [1.5, -2e3]}
+		}
+		directive @cache(maxAge: Int) repeatable on FIELD_DEFINITION | OBJECT
+	`)
+	a.Equal(t, 5, len(doc.Types))
+
+	a.Equal(t, TypeDefinitionScalar, doc.Types[0].Kind)
+	a.Equal(t, "Time", doc.Types[0].Name)
+	a.Equal(t, "specifiedBy", doc.Types[0].Directives[0].Name)
+
+	a.Equal(t, TypeDefinitionInterface, doc.Types[1].Kind)
+	a.Equal(t, 1, len(doc.Types[1].Fields))
+
+	a.Equal(t, TypeDefinitionUnion, doc.Types[2].Kind)
+	a.Equal(t, []string{"Post", "User"}, doc.Types[2].Types)
+
+	fruit := doc.Types[3]
+	a.Equal(t, TypeDefinitionEnum, fruit.Kind)
+	a.Equal(t, 2, len(fruit.EnumValues))
+	a.Equal(t, "Red or green", fruit.EnumValues[0].Description)
+	a.Equal(t, "PEER", fruit.EnumValues[1].Name)
+	a.NotNil(t, FindDirective(fruit.EnumValues[1].Directives, "deprecated"))
+
+	input := doc.Types[4]
+	a.Equal(t, TypeDefinitionInputObject, input.Kind)
+	a.Equal(t, 3, len(input.InputFields))
+	a.Equal(t, `"untitled"`, input.InputFields[0].DefaultValue.String())
+	a.Equal(t, `[APPLE]`, input.InputFields[1].DefaultValue.String())
+	a.Equal(t, `{a: 1, b: [1.5, -2E3]}`, input.InputFields[2].DefaultValue.String())
+
+	a.Equal(t, 1, len(doc.Directives))
+	directive := doc.Directives[0]
+	a.Equal(t, "cache", directive.Name)
+	a.True(t, directive.Repeatable)
+	a.Equal(t, []string{"FIELD_DEFINITION", "OBJECT"}, directive.Locations)
+	a.Equal(t, 1, len(directive.Arguments))
+}
+
+func TestParseSDLErrors(t *testing.T) {
+	parseSDLAndExpectErr(t, `{ foo }`, "executable definitions are not allowed in a type system document")
+	parseSDLAndExpectErr(t, `query { foo }`, "expected a type system definition")
+	parseSDLAndExpectErr(t, `extend type Foo { bar: String }`, "type system extensions are not supported")
+	parseSDLAndExpectErr(t, `type Foo { bar String }`, `expected ":" but got "S"`)
+	parseSDLAndExpectErr(t, `type Foo { bar: String`, "unexpected EOF")
+	parseSDLAndExpectErr(t, `type Foo { bar(a: Int = $a): String }`, "variables are not allowed here")
+	parseSDLAndExpectErr(t, `enum Foo { true }`, "enum values cannot be named true, false or null")
+	parseSDLAndExpectErr(t, `directive @foo FIELD`, `expected "on" followed by the directive locations`)
+
+	_, errs := ParseSDL([]byte("type Foo {\n  bar: [String\n}"))
+	a.Equal(t, 1, len(errs))
+	err, ok := errs[0].(ErrorWLocation)
+	a.True(t, ok)
+	a.Equal(t, uint(3), err.Line)
+}
+
+func TestParseValue(t *testing.T) {
+	options := []struct {
+		value    string
+		kind     ValueKind
+		expected string
+	}{
+		{`10`, ValueInt, `10`},
+		{`-1.5`, ValueFloat, `-1.5`},
+		{`"a \"quoted\"\nstring"`, ValueString, `"a \"quoted\"\nstring"`},
+		{`true`, ValueBoolean, `true`},
+		{`null`, ValueNull, `null`},
+		{`FOO`, ValueEnum, `FOO`},
+		{`[1, 2,3]`, ValueList, `[1, 2, 3]`},
+		{`{a: "b" c: {d: null}}`, ValueObject, `{a: "b", c: {d: null}}`},
+	}
+
+	for _, option := range options {
+		value, err := ParseValue([]byte(option.value))
+		a.NoError(t, err, option.value)
+		a.Equal(t, option.kind, value.Kind, option.value)
+		a.Equal(t, option.expected, value.String(), option.value)
+	}
+
+	_, err := ParseValue([]byte(`$foo`))
+	a.Error(t, err)
+	_, err = ParseValue([]byte(`1 2`))
+	a.Error(t, err)
+}
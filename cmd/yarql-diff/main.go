@@ -0,0 +1,73 @@
+// yarql-diff compares two versions of a GraphQL schema and reports the changes between them
+//
+// Usage:
+//   yarql-diff [flags] old.graphql new.graphql
+//
+// The schemas can be written in the schema definition language or be the JSON result of a introspection query.
+// The exit code is 1 if there are breaking changes, 2 if the schemas could not be read and 0 otherwise.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/mjarkk/yarql/bytecode"
+	"github.com/mjarkk/yarql/diff"
+)
+
+func main() {
+	onlyBreaking := flag.Bool("breaking", false, "only print breaking changes")
+	failOnDangerous := flag.Bool("fail-on-dangerous", false, "also exit with code 1 on dangerous changes")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: yarql-diff [flags] old.graphql new.graphql")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	oldSchema, err := load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	newSchema, err := load(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	changes := diff.Compare(oldSchema, newSchema)
+	if *onlyBreaking {
+		changes = changes.Filter(diff.Breaking)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+	}
+	for _, change := range changes {
+		fmt.Printf("%-10s %s\n", change.Criticality.String(), change.Message)
+	}
+
+	if changes.Breaking() || (*failOnDangerous && len(changes.Filter(diff.Dangerous)) > 0) {
+		os.Exit(1)
+	}
+}
+
+func load(filename string) (*bytecode.TypeSystemDocument, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := diff.Load(contents)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err.Error())
+	}
+	return doc, nil
+}
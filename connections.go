@@ -0,0 +1,158 @@
+package yarql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Relay connections are structs that embed relay.Connection followed by a Node field with the node type:
+//   type UserConnection struct {
+//     relay.Connection
+//     Node User
+//   }
+// The connection and edge types are generated from the graphql type of the node, for example:
+//   User   -> UserConnection  with edges: [UserEdge!], pageInfo: PageInfo! and totalCount: Int
+//             UserEdge        with cursor: String! and node: User!
+// The Node field is only used for its type, the nodes are taken from the edges of relay.Connection
+//
+// The relay package imports this package in its tests so relay.Connection is matched on its package path and name
+
+const relayPkgPath = "github.com/mjarkk/yarql/relay"
+
+// isRelayConnection returns true if the struct type t embeds relay.Connection
+func isRelayConnection(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.PkgPath() == relayPkgPath && field.Type.Name() == "Connection" {
+			return true
+		}
+	}
+	return false
+}
+
+// connectionEdgeGoType returns the struct type used to resolve the edges of a connection with the node type t
+func connectionEdgeGoType(t reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Cursor", Type: reflect.TypeOf("")},
+		{Name: "Node", Type: t},
+	})
+}
+
+func (c *parseCtx) checkConnection(t reflect.Type, hasIDTag bool) (*obj, error) {
+	if hasIDTag {
+		return nil, errors.New("connections cannot have ID attribute")
+	}
+	if t.NumField() != 2 || !t.Field(0).Anonymous || t.Field(1).Name != "Node" {
+		return nil, fmt.Errorf("connection %s must only contain the embedded relay.Connection followed by a Node field", t.String())
+	}
+	connectionType := t.Field(0).Type
+	nodeType := t.Field(1).Type
+
+	node, err := c.check(nodeType, false)
+	if err != nil {
+		return nil, err
+	}
+	node.qlFieldName = []byte("node")
+	node.goFieldName = "Node"
+	node.structFieldIdx = 1
+
+	baseName := qlTypeBaseName(wrapQLTypeInNonNull(c.schema.objToQLType(node)))
+	name := baseName + "Connection"
+	edgeName := baseName + "Edge"
+	edgeType := connectionEdgeGoType(nodeType)
+
+	// Connections of the same node type share their generated types
+	existing, ok := c.schema.types.Get(name)
+	if ok {
+		if existing.edgeType != edgeType {
+			return nil, fmt.Errorf("cannot generate connection type %s for %s, a type with the same name already exists", name, t.String())
+		}
+		res := existing.getRef()
+		return &res, nil
+	}
+	if _, ok = c.schema.types.Get(edgeName); ok {
+		return nil, fmt.Errorf("cannot generate edge type %s for %s, a type with the same name already exists", edgeName, t.String())
+	}
+
+	cursor, err := c.check(reflect.TypeOf(""), false)
+	if err != nil {
+		return nil, err
+	}
+	cursor.qlFieldName = []byte("cursor")
+	cursor.goFieldName = "Cursor"
+	cursor.structFieldIdx = 0
+
+	edge := c.schema.types.Add(obj{
+		valueType:     valueTypeObj,
+		typeName:      edgeName,
+		typeNameBytes: []byte(edgeName),
+		goTypeName:    edgeName,
+		objContents: map[uint32]*obj{
+			getObjKey(cursor.qlFieldName): cursor,
+			getObjKey(node.qlFieldName):   node,
+		},
+		edgeType: edgeType,
+	})
+
+	edgesField, _ := connectionType.FieldByName("Edges")
+	edges := &obj{
+		valueType:      valueTypeArray,
+		dataValueType:  reflect.Slice,
+		qlFieldName:    []byte("edges"),
+		goFieldName:    "Edges",
+		structFieldIdx: edgesField.Index[0],
+		embeddedPath:   []int{0},
+		innerContent:   &edge,
+		edgeType:       edgeType,
+	}
+
+	contents := map[uint32]*obj{
+		getObjKey(edges.qlFieldName): edges,
+	}
+	for _, fieldName := range []string{"PageInfo", "TotalCount"} {
+		field, _ := connectionType.FieldByName(fieldName)
+		fieldObj, err := c.check(field.Type, false)
+		if err != nil {
+			return nil, err
+		}
+		fieldObj.qlFieldName = []byte(formatGoNameToQL(fieldName))
+		fieldObj.goFieldName = fieldName
+		fieldObj.structFieldIdx = field.Index[0]
+		fieldObj.embeddedPath = []int{0}
+		contents[getObjKey(fieldObj.qlFieldName)] = fieldObj
+	}
+
+	res := c.schema.types.Add(obj{
+		valueType:     valueTypeObj,
+		typeName:      name,
+		typeNameBytes: []byte(name),
+		goTypeName:    name,
+		objContents:   contents,
+		edgeType:      edgeType,
+	})
+	return &res, nil
+}
+
+// connectionEdges converts a slice of relay.Edge to a slice of the generated edge structs with the node type of the connection
+func connectionEdges(goValue reflect.Value, edgeType reflect.Type) (reflect.Value, error) {
+	nodeType := edgeType.Field(1).Type
+	edges := reflect.MakeSlice(reflect.SliceOf(edgeType), goValue.Len(), goValue.Len())
+	for i := 0; i < goValue.Len(); i++ {
+		// The fields of relay.Edge are Cursor and Node in that order
+		edge := goValue.Index(i)
+		res := edges.Index(i)
+		res.Field(0).Set(edge.Field(0))
+
+		node := edge.Field(1)
+		if node.IsNil() {
+			continue
+		}
+		node = node.Elem()
+		if !node.Type().AssignableTo(nodeType) {
+			return edges, fmt.Errorf("cannot use node of type %s as %s", node.Type().String(), nodeType.String())
+		}
+		res.Field(1).Set(node)
+	}
+	return edges, nil
+}
@@ -0,0 +1,73 @@
+package yarql
+
+import (
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+	"github.com/mjarkk/yarql/relay"
+)
+
+type TestConnectionNode struct {
+	Name string
+}
+
+type TestConnectionNodeConnection struct {
+	relay.Connection
+	Node TestConnectionNode
+}
+
+type TestConnectionNodePtrConnection struct {
+	relay.Connection
+	Node *TestConnectionNode
+}
+
+type TestConnectionData struct {
+	Nodes     TestConnectionNodeConnection
+	MoreNodes TestConnectionNodeConnection
+}
+
+func TestConnection(t *testing.T) {
+	nodes := TestConnectionNodeConnection{Connection: relay.Connection{
+		Edges:    []relay.Edge{{Cursor: "a", Node: TestConnectionNode{Name: "a"}}},
+		PageInfo: relay.PageInfo{HasNextPage: true},
+	}}
+	res := bytecodeParseAndExpectNoErrs(t, `{nodes {edges {cursor node {name}} pageInfo {hasNextPage} totalCount} moreNodes {edges {cursor}}}`, TestConnectionData{Nodes: nodes}, M{})
+	a.Equal(t, `{"nodes":{"edges":[{"cursor":"a","node":{"name":"a"}}],"pageInfo":{"hasNextPage":true},"totalCount":null},"moreNodes":{"edges":null}}`, res)
+}
+
+type TestConnectionInvalidNodeData struct {
+	Nodes TestConnectionNodeConnection
+}
+
+func TestConnectionInvalidNode(t *testing.T) {
+	nodes := TestConnectionNodeConnection{Connection: relay.Connection{
+		Edges: []relay.Edge{{Cursor: "a", Node: "not a node"}},
+	}}
+	res, errs := bytecodeParseAndExpectErrs(t, `{nodes {edges {node {name}}}}`, TestConnectionInvalidNodeData{Nodes: nodes}, M{})
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "cannot use node of type string as yarql.TestConnectionNode", errs[0].Error())
+	a.Equal(t, `{"nodes":{"edges":null}}`, res)
+}
+
+type TestConnectionNameCollisionData struct {
+	Nodes    TestConnectionNodeConnection
+	PtrNodes TestConnectionNodePtrConnection
+}
+
+type TestConnectionExtraFieldConnection struct {
+	relay.Connection
+	Node  TestConnectionNode
+	Extra string
+}
+
+type TestConnectionExtraFieldData struct {
+	Nodes TestConnectionExtraFieldConnection
+}
+
+func TestConnectionInvalid(t *testing.T) {
+	err := NewSchema().Parse(TestConnectionNameCollisionData{}, M{}, nil)
+	a.Error(t, err)
+
+	err = NewSchema().Parse(TestConnectionExtraFieldData{}, M{}, nil)
+	a.Error(t, err)
+}
@@ -46,6 +46,16 @@ func (s *Schema) Copy() *Schema {
 		MaxDepth:          s.MaxDepth,
 		definedEnums:      enums,
 		definedDirectives: directives,
+		registry:          s.registry,
+		definedEntities:   s.definedEntities,
+		federation:        s.federation,
+		usesJSON:          s.usesJSON,
+		definedScalars:    s.definedScalars,
+		timeLayout:        s.timeLayout,
+		strictInt:         s.strictInt,
+		int64AsLong:       s.int64AsLong,
+		longScalarName:    s.longScalarName,
+		resolveType:       s.resolveType,
 
 		Result:           make([]byte, len(s.Result)),
 		graphqlTypesMap:  nil,
@@ -55,6 +65,13 @@ func (s *Schema) Copy() *Schema {
 
 	res.ctx = s.ctx.copy(res)
 
+	// The __schema value reads the types of the schema it was created for, bind it to the copy
+	schemaField, ok := res.rootQuery.objContents[getObjKey([]byte("__schema"))]
+	if ok && schemaField.hidden {
+		contents := reflect.ValueOf(res.getQLSchema())
+		schemaField.customObjValue = &contents
+	}
+
 	return res
 }
 
@@ -68,9 +85,7 @@ func (ctx *Ctx) copy(schema *Schema) *Ctx {
 		getFormFile:              ctx.getFormFile,
 		operatorHasArguments:     ctx.operatorHasArguments,
 		operatorArgumentsStartAt: ctx.operatorArgumentsStartAt,
-		tracingEnabled:           ctx.tracingEnabled,
-		tracing:                  ctx.tracing,
-		prefRecordingStartTime:   ctx.prefRecordingStartTime,
+		apolloTracer:             NewApolloTracer(),
 		rawVariables:             ctx.rawVariables,
 		variablesParsed:          false,
 		variablesJSONParser:      &fastjson.Parser{},
@@ -101,11 +116,14 @@ func (m *Directive) copy() *Directive {
 
 func (m *enum) copy() *enum {
 	res := &enum{
-		contentType: m.contentType,
-		contentKind: m.contentKind,
-		typeName:    m.typeName,
-		entries:     []enumEntry{},
-		qlType:      *m.qlType.copy(),
+		contentType:   m.contentType,
+		contentKind:   m.contentKind,
+		typeName:      m.typeName,
+		entries:       []enumEntry{},
+		qlType:        *m.qlType.copy(),
+		entryByKey:    m.entryByKey,
+		entryByString: m.entryByString,
+		entryByNumber: m.entryByNumber,
 	}
 	for _, entry := range m.entries {
 		res.entries = append(res.entries, enumEntry{
@@ -133,8 +151,7 @@ func (m *qlType) copy() *qlType {
 		*res.Name = *m.Name
 	}
 	if m.Description != nil {
-		res.Name = helpers.StrPtr("")
-		*res.Name = *m.Name
+		res.Description = helpers.StrPtr(*m.Description)
 	}
 	if m.Interfaces != nil {
 		res.Interfaces = make([]qlType, len(m.Interfaces))
@@ -170,15 +187,34 @@ func (o *obj) copy() *obj {
 	res := obj{
 		valueType:      o.valueType,
 		typeName:       o.typeName,
+		description:    o.description,
 		typeNameBytes:  o.typeNameBytes[:],
 		goTypeName:     o.goTypeName,
 		goPkgPath:      o.goPkgPath,
 		qlFieldName:    o.qlFieldName[:],
 		customObjValue: o.customObjValue, // maybe TODO
 		structFieldIdx: o.structFieldIdx,
+		goFieldName:    o.goFieldName,
+		embeddedPath:   o.embeddedPath,
 		dataValueType:  o.dataValueType,
+		scalarName:     o.scalarName,
+		timeLayout:     o.timeLayout,
+		timeout:        o.timeout,
 		isID:           o.isID,
+		isLong:         o.isLong,
+		hidden:         o.hidden,
+		isServiceSDL:   o.isServiceSDL,
+		sdlType:        o.sdlType,
 		enumTypeIndex:  o.enumTypeIndex,
+		isUnion:        o.isUnion,
+		mapEntryType:   o.mapEntryType,
+		edgeType:       o.edgeType,
+
+		appliedDirectives: o.appliedDirectives,
+	}
+
+	if o.deprecationReason != nil {
+		res.deprecationReason = helpers.StrPtr(*o.deprecationReason)
 	}
 
 	if o.innerContent != nil {
@@ -202,17 +238,23 @@ func (o *obj) copy() *obj {
 		}
 	}
 
+	for _, parent := range o.interfaces {
+		res.interfaces = append(res.interfaces, parent.copy())
+	}
+
 	return &res
 }
 
 func (m *objMethod) copy() *objMethod {
 	res := objMethod{
-		isTypeMethod:   m.isTypeMethod,
-		goFunctionName: m.goFunctionName,
-		goType:         m.goType,
-		checkedIns:     m.checkedIns,
-		outNr:          m.outNr,
-		outType:        *m.outType.copy(),
+		isTypeMethod:    m.isTypeMethod,
+		goFunctionName:  m.goFunctionName,
+		goType:          m.goType,
+		pointerReceiver: m.pointerReceiver,
+		checkedIns:      m.checkedIns,
+		outNr:           m.outNr,
+		outType:         *m.outType.copy(),
+		hasDefaults:     m.hasDefaults,
 	}
 	if m.errorOutNr != nil {
 		errOutNr := 0
@@ -260,6 +302,12 @@ func (m *input) copy() *input {
 		elem = m.elem.copy()
 	}
 
+	var defaultJSON *fastjson.Value
+	if m.defaultJSON != nil {
+		// Copy the default value as the fastjson value is not safe to use from multiple schemas at the same time
+		defaultJSON = fastjson.MustParse(m.defaultJSON.String())
+	}
+
 	return &input{
 		kind:             m.kind,
 		isEnum:           m.isEnum,
@@ -267,18 +315,35 @@ func (m *input) copy() *input {
 		isID:             m.isID,
 		isFile:           m.isFile,
 		isTime:           m.isTime,
+		isLong:           m.isLong,
+		isAny:            m.isAny,
+		isJSON:           m.isJSON,
+		isText:           m.isText,
+		isBase64:         m.isBase64,
+		isOptional:       m.isOptional,
+		scalarName:       m.scalarName,
+		timeLayout:       m.timeLayout,
 		goFieldIdx:       m.goFieldIdx,
 		gqFieldName:      m.gqFieldName,
+		embeddedPath:     m.embeddedPath,
 		elem:             elem,
 		isStructPointers: m.isStructPointers,
+		isOneOf:          m.isOneOf,
 		structName:       m.structName,
 		structContent:    structContent,
+		hasDefaults:      m.hasDefaults,
+		description:      m.description,
+		defaultValue:     m.defaultValue,
+		defaultJSON:      defaultJSON,
+		goType:           m.goType,
+		sdlType:          m.sdlType,
 	}
 }
 
 func (m *baseInput) copy() *baseInput {
 	res := &baseInput{
-		isCtx: m.isCtx,
+		isCtx:     m.isCtx,
+		isContext: m.isContext,
 	}
 	if m.goType != nil {
 		reflectType := reflect.TypeOf(0)
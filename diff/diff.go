@@ -0,0 +1,528 @@
+// Package diff compares two versions of a GraphQL schema and reports the changes between them
+//
+// Every change is classified so it can be used in CI to block breaking changes:
+//   Breaking  - existing queries can fail, like removing a field or making a argument required
+//   Dangerous - existing queries keep working but clients might behave different, like adding a enum value
+//   Safe      - existing queries and clients are unaffected, like adding a field
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mjarkk/yarql/bytecode"
+)
+
+// Criticality defines how a change affects existing clients
+type Criticality uint8
+
+// All possible criticalities
+const (
+	Safe Criticality = iota
+	Dangerous
+	Breaking
+)
+
+// String returns the criticality in upper case, used as prefix in the output of yarql-diff
+func (c Criticality) String() string {
+	switch c {
+	case Safe:
+		return "SAFE"
+	case Dangerous:
+		return "DANGEROUS"
+	case Breaking:
+		return "BREAKING"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Change is a single difference between two schemas
+type Change struct {
+	Criticality Criticality
+	// Path is the schema coordinate of the changed element, like User.email, Query.users(limit:) or @skip(if:)
+	Path    string
+	Message string
+}
+
+func (c Change) String() string {
+	return c.Criticality.String() + " " + c.Message
+}
+
+// Changes is a list of changes sorted by path
+type Changes []Change
+
+// Breaking returns true if one of the changes is breaking
+func (c Changes) Breaking() bool {
+	for _, change := range c {
+		if change.Criticality == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the changes with the criticality
+func (c Changes) Filter(criticality Criticality) Changes {
+	res := Changes{}
+	for _, change := range c {
+		if change.Criticality == criticality {
+			res = append(res, change)
+		}
+	}
+	return res
+}
+
+// builtinScalars are the scalars defined by the graphql spec, they are ignored when comparing schemas
+var builtinScalars = map[string]bool{
+	"Boolean": true,
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"ID":      true,
+}
+
+// builtinDirectives are the directives defined by the graphql spec, they are ignored when comparing schemas
+var builtinDirectives = map[string]bool{
+	"skip":        true,
+	"include":     true,
+	"deprecated":  true,
+	"specifiedBy": true,
+}
+
+func isIntrospectionType(name string) bool {
+	return strings.HasPrefix(name, "__")
+}
+
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) add(criticality Criticality, path string, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Criticality: criticality,
+		Path:        path,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+// Compare returns the changes needed to go from the oldSchema to the newSchema
+// The result is sorted by path so it's stable between runs
+func Compare(oldSchema, newSchema *bytecode.TypeSystemDocument) Changes {
+	d := &differ{changes: Changes{}}
+
+	oldTypes := typesByName(oldSchema)
+	newTypes := typesByName(newSchema)
+
+	d.compareRoots(rootTypes(oldSchema, oldTypes), rootTypes(newSchema, newTypes))
+
+	for _, name := range sortedTypeNames(oldTypes) {
+		oldType := oldTypes[name]
+		newType, ok := newTypes[name]
+		if !ok {
+			d.add(Breaking, name, "%s %s was removed", kindName(oldType.Kind), name)
+			continue
+		}
+		d.compareType(oldType, newType)
+	}
+	for _, name := range sortedTypeNames(newTypes) {
+		if _, ok := oldTypes[name]; !ok {
+			d.add(Safe, name, "%s %s was added", kindName(newTypes[name].Kind), name)
+		}
+	}
+
+	oldDirectives := directivesByName(oldSchema)
+	newDirectives := directivesByName(newSchema)
+	for _, name := range sortedDirectiveNames(oldDirectives) {
+		newDirective, ok := newDirectives[name]
+		if !ok {
+			d.add(Breaking, "@"+name, "Directive @%s was removed", name)
+			continue
+		}
+		d.compareDirective(oldDirectives[name], newDirective)
+	}
+	for _, name := range sortedDirectiveNames(newDirectives) {
+		if _, ok := oldDirectives[name]; !ok {
+			d.add(Safe, "@"+name, "Directive @%s was added", name)
+		}
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Path < d.changes[j].Path
+	})
+	return d.changes
+}
+
+func typesByName(doc *bytecode.TypeSystemDocument) map[string]*bytecode.TypeDefinition {
+	res := map[string]*bytecode.TypeDefinition{}
+	for idx := range doc.Types {
+		definition := &doc.Types[idx]
+		if isIntrospectionType(definition.Name) || (definition.Kind == bytecode.TypeDefinitionScalar && builtinScalars[definition.Name]) {
+			continue
+		}
+		res[definition.Name] = definition
+	}
+	return res
+}
+
+func directivesByName(doc *bytecode.TypeSystemDocument) map[string]*bytecode.DirectiveDefinition {
+	res := map[string]*bytecode.DirectiveDefinition{}
+	for idx := range doc.Directives {
+		definition := &doc.Directives[idx]
+		if builtinDirectives[definition.Name] {
+			continue
+		}
+		res[definition.Name] = definition
+	}
+	return res
+}
+
+func sortedTypeNames(types map[string]*bytecode.TypeDefinition) []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedDirectiveNames(directives map[string]*bytecode.DirectiveDefinition) []string {
+	names := make([]string, 0, len(directives))
+	for name := range directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rootTypes returns the query, mutation and subscription type names
+// If the document has no schema definition the default names are used
+func rootTypes(doc *bytecode.TypeSystemDocument, types map[string]*bytecode.TypeDefinition) [3]string {
+	if doc.Schema != nil {
+		return [3]string{doc.Schema.Query, doc.Schema.Mutation, doc.Schema.Subscription}
+	}
+
+	res := [3]string{}
+	for idx, name := range []string{"Query", "Mutation", "Subscription"} {
+		if _, ok := types[name]; ok {
+			res[idx] = name
+		}
+	}
+	return res
+}
+
+func (d *differ) compareRoots(oldRoots, newRoots [3]string) {
+	for idx, operation := range []string{"query", "mutation", "subscription"} {
+		oldRoot := oldRoots[idx]
+		newRoot := newRoots[idx]
+		switch {
+		case oldRoot == newRoot:
+		case oldRoot == "":
+			d.add(Safe, "schema."+operation, "Schema %s root type %s was added", operation, newRoot)
+		case newRoot == "":
+			d.add(Breaking, "schema."+operation, "Schema %s root type %s was removed", operation, oldRoot)
+		default:
+			d.add(Breaking, "schema."+operation, "Schema %s root type changed from %s to %s", operation, oldRoot, newRoot)
+		}
+	}
+}
+
+func kindName(kind bytecode.TypeDefinitionKind) string {
+	switch kind {
+	case bytecode.TypeDefinitionScalar:
+		return "Scalar"
+	case bytecode.TypeDefinitionObject:
+		return "Type"
+	case bytecode.TypeDefinitionInterface:
+		return "Interface"
+	case bytecode.TypeDefinitionUnion:
+		return "Union"
+	case bytecode.TypeDefinitionEnum:
+		return "Enum"
+	case bytecode.TypeDefinitionInputObject:
+		return "Input"
+	default:
+		return "Unknown"
+	}
+}
+
+func (d *differ) compareType(oldType, newType *bytecode.TypeDefinition) {
+	name := oldType.Name
+	if oldType.Kind != newType.Kind {
+		d.add(Breaking, name, "%s changed from %s to %s", name, oldType.Kind.String(), newType.Kind.String())
+		return
+	}
+
+	if oldType.Description != newType.Description {
+		d.add(Safe, name, "Description of %s changed", name)
+	}
+
+	switch oldType.Kind {
+	case bytecode.TypeDefinitionObject, bytecode.TypeDefinitionInterface:
+		d.compareNameSet(name, oldType.Interfaces, newType.Interfaces, "Interface", "implemented by")
+		d.compareFields(oldType, newType)
+	case bytecode.TypeDefinitionUnion:
+		d.compareNameSet(name, oldType.Types, newType.Types, "Type", "member of union")
+	case bytecode.TypeDefinitionEnum:
+		d.compareEnumValues(oldType, newType)
+	case bytecode.TypeDefinitionInputObject:
+		d.compareInputValues(oldType.InputFields, newType.InputFields, "Input field", func(field string) string {
+			return name + "." + field
+		})
+	}
+}
+
+// compareNameSet compares the interfaces of a object or the members of a union
+// Removing a entry is breaking as fragments on it will fail,
+// adding one is dangerous as clients might not handle the new type
+func (d *differ) compareNameSet(name string, oldNames, newNames []string, entryKind string, relation string) {
+	oldSet := map[string]bool{}
+	for _, entry := range oldNames {
+		oldSet[entry] = true
+	}
+	newSet := map[string]bool{}
+	for _, entry := range newNames {
+		newSet[entry] = true
+	}
+
+	for _, entry := range oldNames {
+		if !newSet[entry] {
+			d.add(Breaking, name, "%s %s is no longer %s %s", entryKind, entry, relation, name)
+		}
+	}
+	for _, entry := range newNames {
+		if !oldSet[entry] {
+			d.add(Dangerous, name, "%s %s was added as %s %s", entryKind, entry, relation, name)
+		}
+	}
+}
+
+func (d *differ) compareFields(oldType, newType *bytecode.TypeDefinition) {
+	newFields := map[string]*bytecode.FieldDefinition{}
+	for idx := range newType.Fields {
+		newFields[newType.Fields[idx].Name] = &newType.Fields[idx]
+	}
+	oldFields := map[string]bool{}
+
+	for idx := range oldType.Fields {
+		oldField := &oldType.Fields[idx]
+		oldFields[oldField.Name] = true
+		path := oldType.Name + "." + oldField.Name
+
+		newField, ok := newFields[oldField.Name]
+		if !ok {
+			if bytecode.FindDirective(oldField.Directives, "deprecated") != nil {
+				d.add(Breaking, path, "Field %s was removed (was deprecated)", path)
+			} else {
+				d.add(Breaking, path, "Field %s was removed", path)
+			}
+			continue
+		}
+
+		if !safeOutputChange(oldField.Type, newField.Type) {
+			d.add(Breaking, path, "Field %s changed type from %s to %s", path, oldField.Type.String(), newField.Type.String())
+		} else if oldField.Type.String() != newField.Type.String() {
+			d.add(Safe, path, "Field %s changed type from %s to %s", path, oldField.Type.String(), newField.Type.String())
+		}
+
+		if oldField.Description != newField.Description {
+			d.add(Safe, path, "Description of field %s changed", path)
+		}
+		d.compareDeprecation("Field", path, oldField.Directives, newField.Directives)
+
+		d.compareInputValues(oldField.Arguments, newField.Arguments, "Argument", func(arg string) string {
+			return path + "(" + arg + ":)"
+		})
+	}
+
+	for _, field := range newType.Fields {
+		if !oldFields[field.Name] {
+			path := newType.Name + "." + field.Name
+			d.add(Safe, path, "Field %s was added", path)
+		}
+	}
+}
+
+func (d *differ) compareEnumValues(oldType, newType *bytecode.TypeDefinition) {
+	newValues := map[string]*bytecode.EnumValueDefinition{}
+	for idx := range newType.EnumValues {
+		newValues[newType.EnumValues[idx].Name] = &newType.EnumValues[idx]
+	}
+	oldValues := map[string]bool{}
+
+	for idx := range oldType.EnumValues {
+		oldValue := &oldType.EnumValues[idx]
+		oldValues[oldValue.Name] = true
+		path := oldType.Name + "." + oldValue.Name
+
+		newValue, ok := newValues[oldValue.Name]
+		if !ok {
+			d.add(Breaking, path, "Enum value %s was removed", path)
+			continue
+		}
+
+		if oldValue.Description != newValue.Description {
+			d.add(Safe, path, "Description of enum value %s changed", path)
+		}
+		d.compareDeprecation("Enum value", path, oldValue.Directives, newValue.Directives)
+	}
+
+	for _, value := range newType.EnumValues {
+		if !oldValues[value.Name] {
+			path := newType.Name + "." + value.Name
+			d.add(Dangerous, path, "Enum value %s was added", path)
+		}
+	}
+}
+
+// compareInputValues compares the arguments of a field or directive or the fields of a input
+func (d *differ) compareInputValues(oldValues, newValues []bytecode.InputValueDefinition, valueKind string, pathOf func(name string) string) {
+	newByName := map[string]*bytecode.InputValueDefinition{}
+	for idx := range newValues {
+		newByName[newValues[idx].Name] = &newValues[idx]
+	}
+	oldNames := map[string]bool{}
+
+	for idx := range oldValues {
+		oldValue := &oldValues[idx]
+		oldNames[oldValue.Name] = true
+		path := pathOf(oldValue.Name)
+
+		newValue, ok := newByName[oldValue.Name]
+		if !ok {
+			d.add(Breaking, path, "%s %s was removed", valueKind, path)
+			continue
+		}
+
+		if !safeInputChange(oldValue.Type, newValue.Type) {
+			d.add(Breaking, path, "%s %s changed type from %s to %s", valueKind, path, oldValue.Type.String(), newValue.Type.String())
+		} else if oldValue.Type.String() != newValue.Type.String() {
+			d.add(Safe, path, "%s %s changed type from %s to %s", valueKind, path, oldValue.Type.String(), newValue.Type.String())
+		}
+
+		oldDefault := defaultValueString(oldValue.DefaultValue)
+		newDefault := defaultValueString(newValue.DefaultValue)
+		if oldDefault != newDefault {
+			switch {
+			case oldValue.DefaultValue == nil:
+				d.add(Dangerous, path, "%s %s has a new default value %s", valueKind, path, newDefault)
+			case newValue.DefaultValue == nil:
+				d.add(Dangerous, path, "%s %s no longer has a default value, it was %s", valueKind, path, oldDefault)
+			default:
+				d.add(Dangerous, path, "%s %s default value changed from %s to %s", valueKind, path, oldDefault, newDefault)
+			}
+		}
+
+		if oldValue.Description != newValue.Description {
+			d.add(Safe, path, "Description of %s %s changed", strings.ToLower(valueKind), path)
+		}
+	}
+
+	for idx := range newValues {
+		newValue := &newValues[idx]
+		if oldNames[newValue.Name] {
+			continue
+		}
+
+		path := pathOf(newValue.Name)
+		if newValue.Type.NonNull && newValue.DefaultValue == nil {
+			d.add(Breaking, path, "Required %s %s was added", strings.ToLower(valueKind), path)
+		} else {
+			d.add(Dangerous, path, "Optional %s %s was added", strings.ToLower(valueKind), path)
+		}
+	}
+}
+
+func (d *differ) compareDeprecation(valueKind string, path string, oldDirectives, newDirectives []bytecode.Directive) {
+	oldDeprecated := bytecode.FindDirective(oldDirectives, "deprecated")
+	newDeprecated := bytecode.FindDirective(newDirectives, "deprecated")
+	switch {
+	case oldDeprecated == nil && newDeprecated != nil:
+		d.add(Safe, path, "%s %s was deprecated", valueKind, path)
+	case oldDeprecated != nil && newDeprecated == nil:
+		d.add(Safe, path, "%s %s is no longer deprecated", valueKind, path)
+	case oldDeprecated != nil && deprecationReason(oldDeprecated) != deprecationReason(newDeprecated):
+		d.add(Safe, path, "Deprecation reason of %s %s changed", strings.ToLower(valueKind), path)
+	}
+}
+
+func deprecationReason(directive *bytecode.Directive) string {
+	reason := directive.Argument("reason")
+	if reason == nil {
+		return ""
+	}
+	return reason.Value
+}
+
+func defaultValueString(value *bytecode.Value) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+func (d *differ) compareDirective(oldDirective, newDirective *bytecode.DirectiveDefinition) {
+	path := "@" + oldDirective.Name
+
+	if oldDirective.Repeatable && !newDirective.Repeatable {
+		d.add(Breaking, path, "Directive %s is no longer repeatable", path)
+	} else if !oldDirective.Repeatable && newDirective.Repeatable {
+		d.add(Safe, path, "Directive %s is now repeatable", path)
+	}
+
+	newLocations := map[string]bool{}
+	for _, location := range newDirective.Locations {
+		newLocations[location] = true
+	}
+	oldLocations := map[string]bool{}
+	for _, location := range oldDirective.Locations {
+		oldLocations[location] = true
+		if !newLocations[location] {
+			d.add(Breaking, path, "Location %s was removed from directive %s", location, path)
+		}
+	}
+	for _, location := range newDirective.Locations {
+		if !oldLocations[location] {
+			d.add(Safe, path, "Location %s was added to directive %s", location, path)
+		}
+	}
+
+	if oldDirective.Description != newDirective.Description {
+		d.add(Safe, path, "Description of directive %s changed", path)
+	}
+
+	d.compareInputValues(oldDirective.Arguments, newDirective.Arguments, "Argument", func(arg string) string {
+		return path + "(" + arg + ":)"
+	})
+}
+
+// safeOutputChange returns true if a client that expects the oldType can handle the newType
+// Making a output stricter (adding !) is safe, making it nullable is not
+func safeOutputChange(oldType, newType bytecode.TypeReference) bool {
+	if oldType.NonNull && !newType.NonNull {
+		return false
+	}
+	if (oldType.List == nil) != (newType.List == nil) {
+		return false
+	}
+	if oldType.List != nil {
+		return safeOutputChange(*oldType.List, *newType.List)
+	}
+	return oldType.Name == newType.Name
+}
+
+// safeInputChange returns true if every value a client could send as the oldType is still valid as the newType
+// Making a input less strict (removing !) is safe, making it required is not
+func safeInputChange(oldType, newType bytecode.TypeReference) bool {
+	if !oldType.NonNull && newType.NonNull {
+		return false
+	}
+	if (oldType.List == nil) != (newType.List == nil) {
+		return false
+	}
+	if oldType.List != nil {
+		return safeInputChange(*oldType.List, *newType.List)
+	}
+	return oldType.Name == newType.Name
+}
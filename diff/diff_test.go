@@ -0,0 +1,344 @@
+package diff
+
+import (
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+const diffTestSchema = `
+schema {
+  query: Query
+  mutation: Mutation
+}
+
+directive @cached(ttl: Int) on FIELD | QUERY
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  name: String
+  email: String!
+  age: Int @deprecated(reason: "Use birthday")
+  friends(first: Int = 10): [User!]!
+}
+
+type Post implements Node {
+  id: ID!
+  title: String!
+}
+
+union SearchResult = User | Post
+
+enum Role {
+  ADMIN
+  USER
+}
+
+input UserFilter {
+  name: String
+  role: Role = USER
+}
+
+type Query {
+  users(filter: UserFilter, limit: Int): [User!]!
+  search(query: String!): [SearchResult!]!
+}
+
+type Mutation {
+  deleteUser(id: ID!): Boolean!
+}
+`
+
+type diffTestExpectation struct {
+	criticality Criticality
+	path        string
+	message     string
+}
+
+func compareWith(t *testing.T, oldSDL, newSDL string) Changes {
+	oldSchema, err := FromSDL([]byte(oldSDL))
+	a.NoError(t, err)
+	newSchema, err := FromSDL([]byte(newSDL))
+	a.NoError(t, err)
+	return Compare(oldSchema, newSchema)
+}
+
+func TestCompareEqual(t *testing.T) {
+	changes := compareWith(t, diffTestSchema, diffTestSchema)
+	a.Equal(t, 0, len(changes))
+	a.False(t, changes.Breaking())
+}
+
+func TestCompare(t *testing.T) {
+	options := []struct {
+		name     string
+		replace  [2]string
+		expected []diffTestExpectation
+	}{
+		// Types
+		{
+			"remove type",
+			[2]string{"type Post implements Node {\n  id: ID!\n  title: String!\n}", ""},
+			[]diffTestExpectation{{Breaking, "Post", "Type Post was removed"}},
+		},
+		{
+			"remove union member",
+			[2]string{"union SearchResult = User | Post", "union SearchResult = User"},
+			[]diffTestExpectation{{Breaking, "SearchResult", "Type Post is no longer member of union SearchResult"}},
+		},
+		{
+			"add type",
+			[2]string{"enum Role {", "scalar Upload\n\nenum Role {"},
+			[]diffTestExpectation{{Safe, "Upload", "Scalar Upload was added"}},
+		},
+		{
+			"change kind",
+			[2]string{"interface Node {", "type Node {"},
+			[]diffTestExpectation{{Breaking, "Node", "Node changed from interface to type"}},
+		},
+		{
+			"change description",
+			[2]string{"enum Role {", "\"The role of a user\"\nenum Role {"},
+			[]diffTestExpectation{{Safe, "Role", "Description of Role changed"}},
+		},
+
+		// Fields
+		{
+			"remove field",
+			[2]string{"  email: String!\n", ""},
+			[]diffTestExpectation{{Breaking, "User.email", "Field User.email was removed"}},
+		},
+		{
+			"remove deprecated field",
+			[2]string{"  age: Int @deprecated(reason: \"Use birthday\")\n", ""},
+			[]diffTestExpectation{{Breaking, "User.age", "Field User.age was removed (was deprecated)"}},
+		},
+		{
+			"add field",
+			[2]string{"  email: String!\n", "  email: String!\n  bio: String\n"},
+			[]diffTestExpectation{{Safe, "User.bio", "Field User.bio was added"}},
+		},
+		{
+			"make field nullable",
+			[2]string{"email: String!", "email: String"},
+			[]diffTestExpectation{{Breaking, "User.email", "Field User.email changed type from String! to String"}},
+		},
+		{
+			"make field non null",
+			[2]string{"name: String", "name: String!"},
+			[]diffTestExpectation{{Safe, "User.name", "Field User.name changed type from String to String!"}},
+		},
+		{
+			"make list items nullable",
+			[2]string{"friends(first: Int = 10): [User!]!", "friends(first: Int = 10): [User]!"},
+			[]diffTestExpectation{{Breaking, "User.friends", "Field User.friends changed type from [User!]! to [User]!"}},
+		},
+		{
+			"change field to list",
+			[2]string{"name: String", "name: [String]"},
+			[]diffTestExpectation{{Breaking, "User.name", "Field User.name changed type from String to [String]"}},
+		},
+		{
+			"change field type",
+			[2]string{"title: String!", "title: Int!"},
+			[]diffTestExpectation{{Breaking, "Post.title", "Field Post.title changed type from String! to Int!"}},
+		},
+		{
+			"deprecate field",
+			[2]string{"email: String!", "email: String! @deprecated"},
+			[]diffTestExpectation{{Safe, "User.email", "Field User.email was deprecated"}},
+		},
+		{
+			"undeprecate field",
+			[2]string{"age: Int @deprecated(reason: \"Use birthday\")", "age: Int"},
+			[]diffTestExpectation{{Safe, "User.age", "Field User.age is no longer deprecated"}},
+		},
+		{
+			"change deprecation reason",
+			[2]string{"Use birthday", "Use dateOfBirth"},
+			[]diffTestExpectation{{Safe, "User.age", "Deprecation reason of field User.age changed"}},
+		},
+
+		// Arguments
+		{
+			"remove argument",
+			[2]string{"users(filter: UserFilter, limit: Int)", "users(filter: UserFilter)"},
+			[]diffTestExpectation{{Breaking, "Query.users(limit:)", "Argument Query.users(limit:) was removed"}},
+		},
+		{
+			"add required argument",
+			[2]string{"deleteUser(id: ID!)", "deleteUser(id: ID!, reason: String!)"},
+			[]diffTestExpectation{{Breaking, "Mutation.deleteUser(reason:)", "Required argument Mutation.deleteUser(reason:) was added"}},
+		},
+		{
+			"add optional argument",
+			[2]string{"deleteUser(id: ID!)", "deleteUser(id: ID!, reason: String)"},
+			[]diffTestExpectation{{Dangerous, "Mutation.deleteUser(reason:)", "Optional argument Mutation.deleteUser(reason:) was added"}},
+		},
+		{
+			"add non null argument with default",
+			[2]string{"deleteUser(id: ID!)", "deleteUser(id: ID!, soft: Boolean! = true)"},
+			[]diffTestExpectation{{Dangerous, "Mutation.deleteUser(soft:)", "Optional argument Mutation.deleteUser(soft:) was added"}},
+		},
+		{
+			"make argument required",
+			[2]string{"limit: Int)", "limit: Int!)"},
+			[]diffTestExpectation{{Breaking, "Query.users(limit:)", "Argument Query.users(limit:) changed type from Int to Int!"}},
+		},
+		{
+			"make argument optional",
+			[2]string{"search(query: String!)", "search(query: String)"},
+			[]diffTestExpectation{{Safe, "Query.search(query:)", "Argument Query.search(query:) changed type from String! to String"}},
+		},
+		{
+			"change default value",
+			[2]string{"first: Int = 10", "first: Int = 20"},
+			[]diffTestExpectation{{Dangerous, "User.friends(first:)", "Argument User.friends(first:) default value changed from 10 to 20"}},
+		},
+		{
+			"remove default value",
+			[2]string{"first: Int = 10", "first: Int"},
+			[]diffTestExpectation{{Dangerous, "User.friends(first:)", "Argument User.friends(first:) no longer has a default value, it was 10"}},
+		},
+
+		// Interfaces and unions
+		{
+			"remove interface",
+			[2]string{"type Post implements Node {", "type Post {"},
+			[]diffTestExpectation{{Breaking, "Post", "Interface Node is no longer implemented by Post"}},
+		},
+		{
+			"add interface",
+			[2]string{"interface Node {", "interface Entity {\n  id: ID!\n}\n\ninterface Node {"},
+			[]diffTestExpectation{{Safe, "Entity", "Interface Entity was added"}},
+		},
+		{
+			"add union member",
+			[2]string{"union SearchResult = User | Post", "union SearchResult = User | Post | Query"},
+			[]diffTestExpectation{{Dangerous, "SearchResult", "Type Query was added as member of union SearchResult"}},
+		},
+
+		// Enums
+		{
+			"remove enum value",
+			[2]string{"  ADMIN\n", ""},
+			[]diffTestExpectation{{Breaking, "Role.ADMIN", "Enum value Role.ADMIN was removed"}},
+		},
+		{
+			"add enum value",
+			[2]string{"  ADMIN\n", "  ADMIN\n  GUEST\n"},
+			[]diffTestExpectation{{Dangerous, "Role.GUEST", "Enum value Role.GUEST was added"}},
+		},
+		{
+			"deprecate enum value",
+			[2]string{"  ADMIN\n", "  ADMIN @deprecated\n"},
+			[]diffTestExpectation{{Safe, "Role.ADMIN", "Enum value Role.ADMIN was deprecated"}},
+		},
+
+		// Inputs
+		{
+			"remove input field",
+			[2]string{"  name: String\n  role", "  role"},
+			[]diffTestExpectation{{Breaking, "UserFilter.name", "Input field UserFilter.name was removed"}},
+		},
+		{
+			"add required input field",
+			[2]string{"role: Role = USER", "role: Role = USER\n  active: Boolean!"},
+			[]diffTestExpectation{{Breaking, "UserFilter.active", "Required input field UserFilter.active was added"}},
+		},
+		{
+			"add optional input field",
+			[2]string{"role: Role = USER", "role: Role = USER\n  active: Boolean"},
+			[]diffTestExpectation{{Dangerous, "UserFilter.active", "Optional input field UserFilter.active was added"}},
+		},
+		{
+			"change input field default",
+			[2]string{"role: Role = USER", "role: Role = ADMIN"},
+			[]diffTestExpectation{{Dangerous, "UserFilter.role", "Input field UserFilter.role default value changed from USER to ADMIN"}},
+		},
+
+		// Directives
+		{
+			"remove directive",
+			[2]string{"directive @cached(ttl: Int) on FIELD | QUERY", ""},
+			[]diffTestExpectation{{Breaking, "@cached", "Directive @cached was removed"}},
+		},
+		{
+			"remove directive location",
+			[2]string{"on FIELD | QUERY", "on FIELD"},
+			[]diffTestExpectation{{Breaking, "@cached", "Location QUERY was removed from directive @cached"}},
+		},
+		{
+			"add directive location",
+			[2]string{"on FIELD | QUERY", "on FIELD | QUERY | MUTATION"},
+			[]diffTestExpectation{{Safe, "@cached", "Location MUTATION was added to directive @cached"}},
+		},
+		{
+			"add required directive argument",
+			[2]string{"@cached(ttl: Int)", "@cached(ttl: Int, scope: String!)"},
+			[]diffTestExpectation{{Breaking, "@cached(scope:)", "Required argument @cached(scope:) was added"}},
+		},
+		{
+			"remove directive argument",
+			[2]string{"@cached(ttl: Int)", "@cached"},
+			[]diffTestExpectation{{Breaking, "@cached(ttl:)", "Argument @cached(ttl:) was removed"}},
+		},
+
+		// Root types
+		{
+			"remove mutation",
+			[2]string{"  mutation: Mutation\n", ""},
+			[]diffTestExpectation{{Breaking, "schema.mutation", "Schema mutation root type Mutation was removed"}},
+		},
+	}
+
+	for _, option := range options {
+		newSDL := replaceOnce(t, diffTestSchema, option.replace[0], option.replace[1])
+		changes := compareWith(t, diffTestSchema, newSDL)
+
+		a.Equal(t, len(option.expected), len(changes), "%s: %v", option.name, changes)
+		if len(option.expected) != len(changes) {
+			continue
+		}
+		breaking := false
+		for idx, expected := range option.expected {
+			a.Equal(t, expected.criticality, changes[idx].Criticality, option.name)
+			a.Equal(t, expected.path, changes[idx].Path, option.name)
+			a.Equal(t, expected.message, changes[idx].Message, option.name)
+			breaking = breaking || expected.criticality == Breaking
+		}
+		a.Equal(t, breaking, changes.Breaking(), option.name)
+	}
+}
+
+func TestCompareSortedByPath(t *testing.T) {
+	newSDL := replaceOnce(t, diffTestSchema, "  ADMIN\n", "")
+	newSDL = replaceOnce(t, newSDL, "email: String!", "email: String")
+	newSDL = replaceOnce(t, newSDL, "deleteUser(id: ID!)", "deleteUser(id: ID!, reason: String)")
+
+	changes := compareWith(t, diffTestSchema, newSDL)
+	a.Equal(t, 3, len(changes))
+	a.Equal(t, "Mutation.deleteUser(reason:)", changes[0].Path)
+	a.Equal(t, "Role.ADMIN", changes[1].Path)
+	a.Equal(t, "User.email", changes[2].Path)
+
+	a.Equal(t, 2, len(changes.Filter(Breaking)))
+	a.Equal(t, 1, len(changes.Filter(Dangerous)))
+	a.Equal(t, 0, len(changes.Filter(Safe)))
+	a.Equal(t, "BREAKING Enum value Role.ADMIN was removed", changes[1].String())
+}
+
+func TestCompareWithoutSchemaDefinition(t *testing.T) {
+	oldSDL := "type Query {\n  a: Int\n}\n"
+	newSDL := "type Query {\n  a: Int\n}\n\ntype Mutation {\n  b: Int\n}\n"
+
+	changes := compareWith(t, oldSDL, newSDL)
+	a.Equal(t, 2, len(changes))
+	a.Equal(t, "Type Mutation was added", changes[0].Message)
+	a.Equal(t, "Schema mutation root type Mutation was added", changes[1].Message)
+	a.False(t, changes.Breaking())
+}
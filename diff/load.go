@@ -0,0 +1,338 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mjarkk/yarql"
+	"github.com/mjarkk/yarql/bytecode"
+)
+
+// FromSDL parses a schema written in the GraphQL schema definition language
+func FromSDL(sdl []byte) (*bytecode.TypeSystemDocument, error) {
+	doc, errs := bytecode.ParseSDL(sdl)
+	if len(errs) > 0 {
+		return nil, yarql.SDLErrors(errs)
+	}
+	return doc, nil
+}
+
+// FromSchema converts a parsed schema into a document that can be compared
+func FromSchema(s *yarql.Schema) (*bytecode.TypeSystemDocument, error) {
+	buf := bytes.NewBuffer(nil)
+	err := s.WriteSDL(buf)
+	if err != nil {
+		return nil, err
+	}
+	return FromSDL(buf.Bytes())
+}
+
+// CompareSchemas returns the changes needed to go from the oldSchema to the newSchema
+func CompareSchemas(oldSchema, newSchema *yarql.Schema) (Changes, error) {
+	oldDoc, err := FromSchema(oldSchema)
+	if err != nil {
+		return nil, err
+	}
+	newDoc, err := FromSchema(newSchema)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(oldDoc, newDoc), nil
+}
+
+// Load parses a schema as introspection JSON if it starts with { and as SDL otherwise
+func Load(schema []byte) (*bytecode.TypeSystemDocument, error) {
+	if trimmed := bytes.TrimSpace(schema); len(trimmed) > 0 && trimmed[0] == '{' {
+		return FromIntrospection(schema)
+	}
+	return FromSDL(schema)
+}
+
+// FromIntrospection converts the JSON result of a introspection query into a document that can be compared
+// The input can be the full response ({"data": {"__schema": ...}}) or only the data ({"__schema": ...})
+//
+// The query should request the deprecated fields and enum values using includeDeprecated: true,
+// otherwise removing a deprecated field is not detected
+func FromIntrospection(introspection []byte) (*bytecode.TypeSystemDocument, error) {
+	var response struct {
+		Data *struct {
+			Schema *introspectionSchema `json:"__schema"`
+		} `json:"data"`
+		Schema *introspectionSchema `json:"__schema"`
+	}
+	err := json.Unmarshal(introspection, &response)
+	if err != nil {
+		return nil, fmt.Errorf("invalid introspection JSON, %s", err.Error())
+	}
+
+	schema := response.Schema
+	if response.Data != nil && response.Data.Schema != nil {
+		schema = response.Data.Schema
+	}
+	if schema == nil {
+		return nil, errors.New("invalid introspection JSON, missing __schema")
+	}
+
+	doc := &bytecode.TypeSystemDocument{
+		Schema: &bytecode.SchemaDefinition{
+			Query:        schema.QueryType.name(),
+			Mutation:     schema.MutationType.name(),
+			Subscription: schema.SubscriptionType.name(),
+		},
+		Types:      []bytecode.TypeDefinition{},
+		Directives: []bytecode.DirectiveDefinition{},
+	}
+	if schema.Description != nil {
+		doc.Schema.Description = *schema.Description
+	}
+
+	for _, qlType := range schema.Types {
+		if isIntrospectionType(qlType.Name) || builtinScalars[qlType.Name] {
+			continue
+		}
+
+		definition, err := qlType.toDefinition()
+		if err != nil {
+			return nil, err
+		}
+		doc.Types = append(doc.Types, definition)
+	}
+
+	for _, directive := range schema.Directives {
+		if builtinDirectives[directive.Name] {
+			continue
+		}
+
+		arguments, err := inputValuesToDefinitions(directive.Args)
+		if err != nil {
+			return nil, fmt.Errorf("directive @%s: %s", directive.Name, err.Error())
+		}
+		doc.Directives = append(doc.Directives, bytecode.DirectiveDefinition{
+			Name:        directive.Name,
+			Description: directive.Description.value(),
+			Arguments:   arguments,
+			Repeatable:  directive.IsRepeatable,
+			Locations:   directive.Locations,
+		})
+	}
+
+	return doc, nil
+}
+
+// Types represent the introspection result:
+// https://spec.graphql.org/October2021/#sec-Schema-Introspection
+
+type introspectionSchema struct {
+	Description      *string                  `json:"description"`
+	QueryType        *introspectionNamedType  `json:"queryType"`
+	MutationType     *introspectionNamedType  `json:"mutationType"`
+	SubscriptionType *introspectionNamedType  `json:"subscriptionType"`
+	Types            []introspectionType      `json:"types"`
+	Directives       []introspectionDirective `json:"directives"`
+}
+
+type introspectionNamedType struct {
+	Name string `json:"name"`
+}
+
+func (t *introspectionNamedType) name() string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+type introspectionString struct {
+	*string
+}
+
+func (s *introspectionString) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.string)
+}
+
+func (s introspectionString) value() string {
+	if s.string == nil {
+		return ""
+	}
+	return *s.string
+}
+
+type introspectionType struct {
+	Kind           string                    `json:"kind"`
+	Name           string                    `json:"name"`
+	Description    introspectionString       `json:"description"`
+	Fields         []introspectionField      `json:"fields"`
+	InputFields    []introspectionInputValue `json:"inputFields"`
+	Interfaces     []introspectionTypeRef    `json:"interfaces"`
+	EnumValues     []introspectionEnumValue  `json:"enumValues"`
+	PossibleTypes  []introspectionTypeRef    `json:"possibleTypes"`
+	SpecifiedByURL introspectionString       `json:"specifiedByURL"`
+}
+
+type introspectionField struct {
+	Name              string                    `json:"name"`
+	Description       introspectionString       `json:"description"`
+	Args              []introspectionInputValue `json:"args"`
+	Type              introspectionTypeRef      `json:"type"`
+	IsDeprecated      bool                      `json:"isDeprecated"`
+	DeprecationReason introspectionString       `json:"deprecationReason"`
+}
+
+type introspectionInputValue struct {
+	Name         string               `json:"name"`
+	Description  introspectionString  `json:"description"`
+	Type         introspectionTypeRef `json:"type"`
+	DefaultValue introspectionString  `json:"defaultValue"`
+}
+
+type introspectionEnumValue struct {
+	Name              string              `json:"name"`
+	Description       introspectionString `json:"description"`
+	IsDeprecated      bool                `json:"isDeprecated"`
+	DeprecationReason introspectionString `json:"deprecationReason"`
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   *string               `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+type introspectionDirective struct {
+	Name         string                    `json:"name"`
+	Description  introspectionString       `json:"description"`
+	IsRepeatable bool                      `json:"isRepeatable"`
+	Locations    []string                  `json:"locations"`
+	Args         []introspectionInputValue `json:"args"`
+}
+
+var definitionKinds = map[string]bytecode.TypeDefinitionKind{
+	"SCALAR":       bytecode.TypeDefinitionScalar,
+	"OBJECT":       bytecode.TypeDefinitionObject,
+	"INTERFACE":    bytecode.TypeDefinitionInterface,
+	"UNION":        bytecode.TypeDefinitionUnion,
+	"ENUM":         bytecode.TypeDefinitionEnum,
+	"INPUT_OBJECT": bytecode.TypeDefinitionInputObject,
+}
+
+func (t introspectionType) toDefinition() (bytecode.TypeDefinition, error) {
+	kind, ok := definitionKinds[t.Kind]
+	if !ok {
+		return bytecode.TypeDefinition{}, fmt.Errorf("type %s has unknown kind %s", t.Name, t.Kind)
+	}
+
+	res := bytecode.TypeDefinition{
+		Kind:        kind,
+		Name:        t.Name,
+		Description: t.Description.value(),
+	}
+
+	if t.SpecifiedByURL.string != nil {
+		res.Directives = append(res.Directives, bytecode.Directive{
+			Name: "specifiedBy",
+			Arguments: []bytecode.ObjectField{{
+				Name:  "url",
+				Value: bytecode.Value{Kind: bytecode.ValueString, Value: *t.SpecifiedByURL.string},
+			}},
+		})
+	}
+
+	for _, qlInterface := range t.Interfaces {
+		res.Interfaces = append(res.Interfaces, qlInterface.toReference().Name)
+	}
+	for _, possibleType := range t.PossibleTypes {
+		if kind == bytecode.TypeDefinitionUnion {
+			res.Types = append(res.Types, possibleType.toReference().Name)
+		}
+	}
+
+	for _, field := range t.Fields {
+		arguments, err := inputValuesToDefinitions(field.Args)
+		if err != nil {
+			return res, fmt.Errorf("%s.%s: %s", t.Name, field.Name, err.Error())
+		}
+		res.Fields = append(res.Fields, bytecode.FieldDefinition{
+			Name:        field.Name,
+			Description: field.Description.value(),
+			Arguments:   arguments,
+			Type:        field.Type.toReference(),
+			Directives:  deprecatedDirective(field.IsDeprecated, field.DeprecationReason),
+		})
+	}
+
+	for _, value := range t.EnumValues {
+		res.EnumValues = append(res.EnumValues, bytecode.EnumValueDefinition{
+			Name:        value.Name,
+			Description: value.Description.value(),
+			Directives:  deprecatedDirective(value.IsDeprecated, value.DeprecationReason),
+		})
+	}
+
+	inputFields, err := inputValuesToDefinitions(t.InputFields)
+	if err != nil {
+		return res, fmt.Errorf("%s: %s", t.Name, err.Error())
+	}
+	res.InputFields = inputFields
+
+	return res, nil
+}
+
+func (t introspectionTypeRef) toReference() bytecode.TypeReference {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType == nil {
+			return bytecode.TypeReference{NonNull: true}
+		}
+		res := t.OfType.toReference()
+		res.NonNull = true
+		return res
+	case "LIST":
+		if t.OfType == nil {
+			return bytecode.TypeReference{List: &bytecode.TypeReference{}}
+		}
+		inner := t.OfType.toReference()
+		return bytecode.TypeReference{List: &inner}
+	default:
+		if t.Name == nil {
+			return bytecode.TypeReference{}
+		}
+		return bytecode.TypeReference{Name: *t.Name}
+	}
+}
+
+func inputValuesToDefinitions(values []introspectionInputValue) ([]bytecode.InputValueDefinition, error) {
+	var res []bytecode.InputValueDefinition
+	for _, value := range values {
+		definition := bytecode.InputValueDefinition{
+			Name:        value.Name,
+			Description: value.Description.value(),
+			Type:        value.Type.toReference(),
+		}
+		if value.DefaultValue.string != nil {
+			defaultValue, err := bytecode.ParseValue([]byte(*value.DefaultValue.string))
+			if err != nil {
+				return nil, fmt.Errorf("invalid default value of %s, %s", value.Name, err.Error())
+			}
+			definition.DefaultValue = defaultValue
+		}
+		res = append(res, definition)
+	}
+	return res, nil
+}
+
+func deprecatedDirective(isDeprecated bool, reason introspectionString) []bytecode.Directive {
+	if !isDeprecated {
+		return nil
+	}
+
+	directive := bytecode.Directive{Name: "deprecated"}
+	if reason.string != nil {
+		directive.Arguments = []bytecode.ObjectField{{
+			Name:  "reason",
+			Value: bytecode.Value{Kind: bytecode.ValueString, Value: *reason.string},
+		}}
+	}
+	return []bytecode.Directive{directive}
+}
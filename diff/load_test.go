@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mjarkk/yarql"
+	a "github.com/mjarkk/yarql/assert"
+	"github.com/mjarkk/yarql/explorer"
+)
+
+func replaceOnce(t *testing.T, s, old, new string) string {
+	a.True(t, strings.Contains(s, old), "%q not found in the schema", old)
+	return strings.Replace(s, old, new, 1)
+}
+
+type LoadTestStatus uint8
+
+const (
+	LoadTestStatusDraft LoadTestStatus = iota
+	LoadTestStatusPublished
+)
+
+type LoadTestPost struct {
+	Title  string `gqDescription:"The title of the post"`
+	Likes  int    `gqDeprecated:"Likes are no longer counted"`
+	Status LoadTestStatus
+}
+
+type LoadTestQuery struct {
+	Posts []LoadTestPost
+}
+
+func (LoadTestQuery) ResolvePost(args struct {
+	Title string
+	Limit int `gqDefault:"10"`
+}) *LoadTestPost {
+	return nil
+}
+
+type LoadTestMutation struct{}
+
+func newLoadTestSchema(t *testing.T, queries interface{}) *yarql.Schema {
+	s := yarql.NewSchema()
+	_, err := s.RegisterEnum(map[string]LoadTestStatus{
+		"DRAFT":     LoadTestStatusDraft,
+		"PUBLISHED": LoadTestStatusPublished,
+	})
+	a.NoError(t, err)
+
+	err = s.Parse(queries, LoadTestMutation{}, nil)
+	a.NoError(t, err)
+	return s
+}
+
+func TestFromIntrospection(t *testing.T) {
+	s := newLoadTestSchema(t, LoadTestQuery{})
+
+	errs := s.Resolve([]byte(explorer.IntrospectionQuery), yarql.ResolveOptions{})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+
+	fromIntrospection, err := FromIntrospection(s.Result)
+	a.NoError(t, err)
+	fromSchema, err := FromSchema(s)
+	a.NoError(t, err)
+
+	// Both ways of loading the schema should result in the same document
+	changes := Compare(fromSchema, fromIntrospection)
+	a.Equal(t, 0, len(changes), "%v", changes)
+	changes = Compare(fromIntrospection, fromSchema)
+	a.Equal(t, 0, len(changes), "%v", changes)
+
+	a.Equal(t, "LoadTestQuery", fromIntrospection.Schema.Query)
+	a.Equal(t, "LoadTestMutation", fromIntrospection.Schema.Mutation)
+
+	// Load should detect the introspection JSON
+	loaded, err := Load(s.Result)
+	a.NoError(t, err)
+	a.Equal(t, len(fromIntrospection.Types), len(loaded.Types))
+}
+
+func TestFromIntrospectionWithoutData(t *testing.T) {
+	doc, err := Load([]byte(`  {"__schema": {
+		"queryType": {"name": "Query"},
+		"types": [
+			{"kind": "SCALAR", "name": "String"},
+			{"kind": "OBJECT", "name": "__Type", "fields": []},
+			{"kind": "OBJECT", "name": "Query", "fields": [
+				{"name": "hello", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "SCALAR", "name": "String"}}}}
+			]}
+		],
+		"directives": []
+	}}`))
+	a.NoError(t, err)
+
+	a.Equal(t, "Query", doc.Schema.Query)
+	a.Equal(t, "", doc.Schema.Mutation)
+	a.Equal(t, 1, len(doc.Types))
+	a.Equal(t, "[String]!", doc.Types[0].Fields[0].Type.String())
+}
+
+func TestFromIntrospectionInvalid(t *testing.T) {
+	_, err := FromIntrospection([]byte(`{"data": null}`))
+	a.Error(t, err)
+
+	_, err = FromIntrospection([]byte(`{"__schema": `))
+	a.Error(t, err)
+
+	_, err = FromIntrospection([]byte(`{"__schema": {"types": [{"kind": "FOO", "name": "Bar"}]}}`))
+	a.Error(t, err)
+
+	_, err = Load([]byte(`type Query {`))
+	a.Error(t, err)
+}
+
+type LoadTestQueryV2 struct {
+	Posts []LoadTestPost
+}
+
+func (LoadTestQueryV2) ResolvePost(args struct{ Title string }) *LoadTestPost {
+	return nil
+}
+
+func TestCompareSchemas(t *testing.T) {
+	yarql.TypeRename(LoadTestQueryV2{}, "LoadTestQuery")
+
+	oldSchema := newLoadTestSchema(t, LoadTestQuery{})
+	newSchema := newLoadTestSchema(t, LoadTestQueryV2{})
+
+	changes, err := CompareSchemas(oldSchema, newSchema)
+	a.NoError(t, err)
+	a.True(t, changes.Breaking())
+	a.Equal(t, 1, len(changes), "%v", changes)
+	a.Equal(t, "LoadTestQuery.post(limit:)", changes[0].Path)
+	a.Equal(t, "Argument LoadTestQuery.post(limit:) was removed", changes[0].Message)
+
+	_, err = CompareSchemas(yarql.NewSchema(), newSchema)
+	a.Error(t, err)
+}
@@ -0,0 +1,211 @@
+package yarql
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// structField is a field defined in a struct or promoted from one of it's embedded structs
+type structField struct {
+	field reflect.StructField
+	idx   int
+
+	// embeddedPath contains the field indexes of the embedded structs the field is promoted from
+	// Empty if the field is defined in the struct itself
+	embeddedPath []int
+}
+
+// structFields returns the fields of the struct t including the fields promoted from embedded structs
+//
+// Like in go a field shadows the fields with the same name in deeper embedded structs
+// and fields with the same name at the same depth are left out as go can't select them
+// An embedded struct tagged with gq:"-" is ignored and an embedded struct with a name set using the gq tag is handled as a normal field
+func structFields(t reflect.Type) []structField {
+	type embeddedStruct struct {
+		t    reflect.Type
+		path []int
+	}
+
+	res := []structField{}
+	definedNames := map[string]bool{}
+	visited := map[reflect.Type]bool{}
+	current := []embeddedStruct{{t: t}}
+	for len(current) > 0 {
+		next := []embeddedStruct{}
+		found := []structField{}
+		names := map[string]int{}
+
+		for _, embedded := range current {
+			visited[embedded.t] = true
+		}
+		for _, embedded := range current {
+			for i := 0; i < embedded.t.NumField(); i++ {
+				field := embedded.t.Field(i)
+				if embeddedType, ok := promotesFields(field); ok {
+					names[field.Name]++
+					if !visited[embeddedType] {
+						path := append(append([]int{}, embedded.path...), i)
+						next = append(next, embeddedStruct{t: embeddedType, path: path})
+					}
+					continue
+				}
+				if definedNames[field.Name] {
+					// Shadowed by a field higher up
+					continue
+				}
+
+				names[field.Name]++
+				found = append(found, structField{
+					field:        field,
+					idx:          i,
+					embeddedPath: embedded.path,
+				})
+			}
+		}
+
+		for _, field := range found {
+			if names[field.field.Name] == 1 {
+				res = append(res, field)
+			}
+		}
+		for name := range names {
+			definedNames[name] = true
+		}
+		current = next
+	}
+
+	return res
+}
+
+// promotesFields returns the embedded struct type if the fields of the struct field are promoted to the struct it's defined in
+func promotesFields(field reflect.StructField) (reflect.Type, bool) {
+	if !field.Anonymous {
+		return nil, false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	tag, err := parseFieldTagGQ(&field)
+	if err != nil || tag.ignore || tag.name != nil {
+		return nil, false
+	}
+	return t, true
+}
+
+// promotedMethodPath returns the field indexes of the embedded fields the method is promoted from
+// Only returns a path if one of the embedded fields can be nil, as calling the method would panic if it's nil
+func promotedMethodPath(t reflect.Type, methodName string) []int {
+	type embeddedField struct {
+		t        reflect.Type
+		path     []int
+		nullable bool
+	}
+
+	visited := map[reflect.Type]bool{}
+	current := []embeddedField{{t: t}}
+	for len(current) > 0 {
+		next := []embeddedField{}
+		for _, embedded := range current {
+			visited[embedded.t] = true
+		}
+		for _, embedded := range current {
+			if embedded.path != nil {
+				_, ok := embedded.t.MethodByName(methodName)
+				if !ok && embedded.t.Kind() != reflect.Interface {
+					_, ok = reflect.PtrTo(embedded.t).MethodByName(methodName)
+				}
+				if ok {
+					if embedded.nullable {
+						return embedded.path
+					}
+					return nil
+				}
+			}
+			if embedded.t.Kind() != reflect.Struct {
+				continue
+			}
+
+			for i := 0; i < embedded.t.NumField(); i++ {
+				field := embedded.t.Field(i)
+				if !field.Anonymous {
+					continue
+				}
+				fieldType := field.Type
+				nullable := embedded.nullable || fieldType.Kind() == reflect.Interface
+				if fieldType.Kind() == reflect.Ptr {
+					fieldType = fieldType.Elem()
+					nullable = true
+				}
+				if !visited[fieldType] {
+					path := append(append([]int{}, embedded.path...), i)
+					next = append(next, embeddedField{t: fieldType, path: path, nullable: nullable})
+				}
+			}
+		}
+		current = next
+	}
+
+	return nil
+}
+
+// embeddedValue returns the embedded struct at path inside value
+// Returns false if one of the embedded pointers on the way is nil
+func embeddedValue(value reflect.Value, path []int) (reflect.Value, bool) {
+	for _, idx := range path {
+		value = value.Field(idx)
+		if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return value, false
+			}
+			value = value.Elem()
+		}
+	}
+	return value, true
+}
+
+// inputField returns the go value of the input field in inside the struct goValue
+// Nil embedded pointers on the way to the field are allocated
+func inputField(goValue reflect.Value, in *input) reflect.Value {
+	for _, idx := range in.embeddedPath {
+		goValue = goValue.Field(idx)
+		if goValue.Kind() == reflect.Ptr {
+			if goValue.IsNil() {
+				goValue.Set(reflect.New(goValue.Type().Elem()))
+			}
+			goValue = goValue.Elem()
+		}
+	}
+	return goValue.Field(in.goFieldIdx)
+}
+
+// inputStructField returns the go struct field of the input field in inside the struct t
+func inputStructField(t reflect.Type, in *input) reflect.StructField {
+	for _, idx := range in.embeddedPath {
+		t = t.Field(idx).Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return t.Field(in.goFieldIdx)
+}
+
+// checkEmbeddedInputPath returns an error if a embedded pointer on the path can't be allocated when binding an input value
+func checkEmbeddedInputPath(t reflect.Type, path []int) error {
+	for _, idx := range path {
+		field := t.Field(idx)
+		t = field.Type
+		if t.Kind() == reflect.Ptr {
+			if field.PkgPath != "" {
+				return fmt.Errorf("embedded pointer to unexported struct %s cannot be used as input, embed the struct without pointer or export it", t.Elem().Name())
+			}
+			t = t.Elem()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package yarql
+
+import (
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestEmbeddedTimestamps struct {
+	CreatedAt string
+	UpdatedAt string
+}
+
+type TestEmbeddedNamed struct {
+	Name string
+	Code string
+}
+
+func (n *TestEmbeddedNamed) ResolveUpperName() string {
+	return strings.ToUpper(n.Name)
+}
+
+type TestEmbeddedCoded struct {
+	Code string
+}
+
+type TestEmbeddedNode interface {
+	ResolveNodeId() string
+}
+
+type TestEmbeddedNodeBase struct {
+	ID string `gq:"-"`
+}
+
+func (n TestEmbeddedNodeBase) ResolveNodeId() string {
+	return n.ID
+}
+
+type TestEmbeddedUser struct {
+	Name string
+	TestEmbeddedTimestamps
+	*TestEmbeddedNamed
+	TestEmbeddedCoded
+	Meta TestEmbeddedTimestamps `gq:"meta"`
+	*TestEmbeddedNodeBase
+}
+
+var _ = Implements((*TestEmbeddedNode)(nil), TestEmbeddedUser{})
+
+type TestEmbeddedPaginationArgs struct {
+	First int `gqDefault:"10"`
+	After *string
+}
+
+type TestEmbeddedFilter struct {
+	*TestEmbeddedPaginationArgs
+	Search string
+}
+
+type TestEmbeddedQuery struct {
+	Users []TestEmbeddedUser
+	Nodes []TestEmbeddedNode
+}
+
+func (TestEmbeddedQuery) ResolvePage(args struct {
+	TestEmbeddedPaginationArgs
+	Search string
+}) string {
+	return describeEmbeddedPage(args.TestEmbeddedPaginationArgs, args.Search)
+}
+
+func (TestEmbeddedQuery) ResolveFilter(args struct{ Filter TestEmbeddedFilter }) string {
+	if args.Filter.TestEmbeddedPaginationArgs == nil {
+		return "no pagination " + args.Filter.Search
+	}
+	return describeEmbeddedPage(*args.Filter.TestEmbeddedPaginationArgs, args.Filter.Search)
+}
+
+func describeEmbeddedPage(args TestEmbeddedPaginationArgs, search string) string {
+	res := "first=" + strings.Repeat("i", args.First)
+	if args.After != nil {
+		res += " after=" + *args.After
+	}
+	return res + " search=" + search
+}
+
+var testEmbeddedData = TestEmbeddedQuery{
+	Users: []TestEmbeddedUser{
+		{
+			Name:                   "outer",
+			TestEmbeddedTimestamps: TestEmbeddedTimestamps{CreatedAt: "monday"},
+			TestEmbeddedNamed:      &TestEmbeddedNamed{Name: "inner", Code: "a"},
+			TestEmbeddedCoded:      TestEmbeddedCoded{Code: "b"},
+			TestEmbeddedNodeBase:   &TestEmbeddedNodeBase{ID: "1"},
+		},
+		{Name: "nil embeds"},
+	},
+	Nodes: []TestEmbeddedNode{
+		TestEmbeddedUser{TestEmbeddedNodeBase: &TestEmbeddedNodeBase{ID: "2"}},
+		TestEmbeddedUser{},
+	},
+}
+
+func TestEmbeddedObject(t *testing.T) {
+	res := bytecodeParseAndExpectNoErrs(t, `{users {name createdAt upperName nodeId meta {createdAt}}}`, testEmbeddedData, M{})
+	a.Equal(t, `{"users":[{"name":"outer","createdAt":"monday","upperName":"INNER","nodeId":"1","meta":{"createdAt":""}},{"name":"nil embeds","createdAt":"","upperName":null,"nodeId":null,"meta":{"createdAt":""}}]}`, res)
+
+	res = bytecodeParseAndExpectNoErrs(t, `{nodes {nodeId ... on TestEmbeddedUser {name}}}`, testEmbeddedData, M{})
+	a.Equal(t, `{"nodes":[{"nodeId":"2","name":""},{"nodeId":null,"name":""}]}`, res)
+}
+
+func TestEmbeddedInput(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"default", `{page(search: "a")}`, ``, `{"page":"first=iiiiiiiiii search=a"}`},
+		{"arguments", `{page(first: 2, after: "b", search: "c")}`, ``, `{"page":"first=ii after=b search=c"}`},
+		{"input object default", `{filter(filter: {search: "d"})}`, ``, `{"filter":"first=iiiiiiiiii search=d"}`},
+		{"input object", `{filter(filter: {first: 1, search: "e"})}`, ``, `{"filter":"first=i search=e"}`},
+		{"variable", `query ($filter: TestEmbeddedFilter!) {filter(filter: $filter)}`, `{"filter": {"first": 3, "search": "f"}}`, `{"filter":"first=iii search=f"}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			res := bytecodeParseAndExpectNoErrs(t, testCase.query, TestEmbeddedQuery{}, M{}, opts)
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestEmbeddedSDL(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestEmbeddedQuery{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	// code is defined in 2 embedded structs at the same depth so go can't select it
+	a.False(t, strings.Contains(sdl, "  code: String!\n"), sdl)
+	a.False(t, strings.Contains(sdl, "testEmbeddedTimestamps"), sdl)
+	a.True(t, strings.Contains(sdl, "  meta: TestEmbeddedTimestamps!\n"), sdl)
+	a.True(t, strings.Contains(sdl, "  updatedAt: String!\n"), sdl)
+	a.True(t, strings.Contains(sdl, "  page(after: String, first: Int! = 10, search: String!): String!\n"), sdl)
+	a.True(t, strings.Contains(sdl, "input TestEmbeddedFilter {\n  after: String\n  first: Int! = 10\n  search: String!\n}\n"), sdl)
+}
+
+func TestEmbeddedShadowing(t *testing.T) {
+	type Inner struct {
+		Value string
+		Other string
+	}
+	type Outer struct {
+		Inner
+		Value string
+	}
+
+	res := bytecodeParseAndExpectNoErrs(t, `{value other}`, Outer{Inner: Inner{Value: "inner", Other: "other"}, Value: "outer"}, M{}, ResolveOptions{NoMeta: true})
+	a.Equal(t, `{"value":"outer","other":"other"}`, res)
+
+	res = bytecodeParseAndExpectNoErrs(t, `{value(value: {value: "a", other: "b"})}`, struct {
+		Value func(args struct{ Value Outer }) string
+	}{
+		Value: func(args struct{ Value Outer }) string {
+			return args.Value.Value + args.Value.Inner.Value + args.Value.Other
+		},
+	}, M{}, ResolveOptions{NoMeta: true})
+	a.Equal(t, `{"value":"ab"}`, res)
+}
+
+type testEmbeddedUnexported struct {
+	Value string
+}
+
+func TestEmbeddedInvalidInput(t *testing.T) {
+	err := NewSchema().Parse(struct {
+		A func(args struct{ *testEmbeddedUnexported }) string
+	}{}, M{}, nil)
+	a.Error(t, err)
+
+	err = NewSchema().Parse(struct {
+		A func(args struct{ testEmbeddedUnexported }) string
+	}{}, M{}, nil)
+	a.NoError(t, err)
+}
@@ -15,6 +15,11 @@ type enum struct {
 	typeName    string
 	entries     []enumEntry
 	qlType      qlType
+
+	// Lookup tables to the index of an entry
+	entryByKey    map[string]int
+	entryByString map[string]int // contentKind == String
+	entryByNumber map[uint64]int // contentKind is a int or uint kind, ints are converted to uint64
 }
 
 type enumEntry struct {
@@ -23,13 +28,54 @@ type enumEntry struct {
 	value    reflect.Value
 }
 
+// Enum is a enum with descriptions and deprecated values, see (*Schema).RegisterEnumType
+type Enum struct {
+	// Name is the name of the enum in graphql
+	// Defaults to the name of the go type, set it if multiple packages have an enum type with the same name
+	Name string
+
+	// Description is the description of the enum shown in introspection and the SDL
+	Description string
+
+	// Values are the values of the enum, all values must have the same go type
+	Values []EnumValue
+}
+
+// EnumValue is a value of an Enum
+type EnumValue struct {
+	// Name is the name of the value in graphql
+	// If empty the name is derived from the String method of the value, see fmt.Stringer
+	Name string
+
+	// Value is the go value the name is mapped to
+	Value interface{}
+
+	// Description is the description of the value shown in introspection and the SDL
+	Description string
+
+	// DeprecationReason marks the value as deprecated with this reason
+	DeprecationReason string
+}
+
+// EnumValues returns the values as enum values of which the names are derived from their String method
+//
+// Example:
+//   s.RegisterEnumType(yarql.Enum{Values: yarql.EnumValues(Apple, Peer, Grapefruit)})
+func EnumValues(values ...fmt.Stringer) []EnumValue {
+	res := make([]EnumValue, len(values))
+	for idx, value := range values {
+		res[idx] = EnumValue{Value: value}
+	}
+	return res
+}
+
 func (s *Schema) getEnum(t reflect.Type) (int, *enum) {
 	if len(t.PkgPath()) == 0 || len(t.Name()) == 0 || !validEnumType(t) {
 		return -1, nil
 	}
 
 	for i, enum := range s.definedEnums {
-		if enum.typeName == t.Name() {
+		if enum.contentType == t {
 			return i, &enum
 		}
 	}
@@ -64,8 +110,65 @@ func (s *Schema) RegisterEnum(enumMap interface{}) (added bool, err error) {
 		return false, err
 	}
 
+	return true, s.addEnum(enum)
+}
+
+// RegisterEnumType registers a new enum type with descriptions and deprecated values
+//
+// Example:
+//   s.RegisterEnumType(yarql.Enum{
+//     Description: "A fruit that can be ordered",
+//     Values: []yarql.EnumValue{
+//       {Name: "APPLE", Value: Apple},
+//       {Name: "PEER", Value: Peer, DeprecationReason: "No longer sold"},
+//       {Value: Grapefruit}, // The name is derived from Grapefruit.String()
+//     },
+//   })
+func (s *Schema) RegisterEnumType(enumType Enum) error {
+	if s.parsed {
+		return errors.New("(*yarql.Schema).RegisterEnumType() cannot be ran after (*yarql.Schema).Parse()")
+	}
+	if len(enumType.Values) == 0 {
+		return errors.New("RegisterEnumType requires at least one value")
+	}
+
+	var contentType reflect.Type
+	for _, value := range enumType.Values {
+		valueType := reflect.TypeOf(value.Value)
+		if valueType == nil {
+			return errors.New("RegisterEnumType values cannot be nil")
+		}
+		if contentType == nil {
+			contentType = valueType
+		} else if valueType != contentType {
+			return fmt.Errorf("RegisterEnumType values must have the same type, got %s and %s", contentType.String(), valueType.String())
+		}
+	}
+	err := checkEnumContentType(contentType)
+	if err != nil {
+		return err
+	}
+
+	enum, err := newEnum(contentType, enumType.Name, enumType.Description, enumType.Values)
+	if err != nil {
+		return err
+	}
+	return s.addEnum(enum)
+}
+
+// addEnum adds the enum to the schema, returns an error if the go type or name is already used by another enum
+func (s *Schema) addEnum(enum *enum) error {
+	for _, existing := range s.definedEnums {
+		if existing.contentType == enum.contentType {
+			return fmt.Errorf("enum %s.%s is already registered", enum.contentType.PkgPath(), enum.contentType.Name())
+		}
+		if existing.typeName == enum.typeName {
+			return fmt.Errorf("cannot register enum %s.%s as %s, %s.%s is already registered with this name", enum.contentType.PkgPath(), enum.contentType.Name(), enum.typeName, existing.contentType.PkgPath(), existing.contentType.Name())
+		}
+	}
+
 	s.definedEnums = append(s.definedEnums, *enum)
-	return true, nil
+	return nil
 }
 
 func registerEnumCheck(enumMap interface{}) (*enum, error) {
@@ -91,8 +194,9 @@ func registerEnumCheck(enumMap interface{}) (*enum, error) {
 		return nil, invalidTypeMsg
 	}
 
-	if contentType.PkgPath() == "" || contentType.Name() == "" {
-		return nil, errors.New("RegisterEnum input map value must have a global custom type value (type Animals string) or (type Rules uint64)")
+	err := checkEnumContentType(contentType)
+	if err != nil {
+		return nil, err
 	}
 
 	inputLen := mapReflection.Len()
@@ -101,51 +205,169 @@ func registerEnumCheck(enumMap interface{}) (*enum, error) {
 		return nil, nil
 	}
 
-	entries := make([]enumEntry, inputLen)
-	qlTypeEnumValues := make([]qlEnumValue, inputLen)
-
+	values := make([]EnumValue, 0, inputLen)
 	iter := mapReflection.MapRange()
-	i := 0
 	for iter.Next() {
-		k := iter.Key()
-		keyStr := k.Interface().(string)
+		keyStr := iter.Key().String()
 		if keyStr == "" {
 			return nil, errors.New("RegisterEnum input map cannot contain empty keys")
 		}
 
-		err := validGraphQlName([]byte(keyStr))
+		values = append(values, EnumValue{
+			Name:  keyStr,
+			Value: iter.Value().Interface(),
+		})
+	}
+	sort.Slice(values, func(a int, b int) bool { return values[a].Name < values[b].Name })
+
+	return newEnum(contentType, "", "", values)
+}
+
+// checkEnumContentType returns an error if values of type t cannot be used as enum
+func checkEnumContentType(t reflect.Type) error {
+	if t.PkgPath() == "" || t.Name() == "" || !validEnumType(t) {
+		return errors.New("enum values must have a global custom type value (type Animals string) or (type Rules uint64)")
+	}
+	return nil
+}
+
+// newEnum creates a enum of the go type contentType with the values
+func newEnum(contentType reflect.Type, name string, description string, values []EnumValue) (*enum, error) {
+	if name == "" {
+		name = contentType.Name()
+	} else if err := validGraphQlName([]byte(name)); err != nil {
+		return nil, fmt.Errorf("invalid enum name %s, %s", name, err.Error())
+	}
+
+	res := &enum{
+		contentType:   contentType,
+		contentKind:   contentType.Kind(),
+		typeName:      name,
+		entries:       make([]enumEntry, len(values)),
+		entryByKey:    make(map[string]int, len(values)),
+		entryByString: map[string]int{},
+		entryByNumber: map[uint64]int{},
+	}
+	qlTypeEnumValues := make([]qlEnumValue, len(values))
+
+	for idx, value := range values {
+		key := value.Name
+		if key == "" {
+			stringer, ok := value.Value.(fmt.Stringer)
+			if !ok {
+				return nil, fmt.Errorf("enum %s value %v has no name and doesn't implement fmt.Stringer", name, value.Value)
+			}
+			key = stringer.String()
+		}
+
+		err := validGraphQlName([]byte(key))
 		if err != nil {
-			return nil, errors.New(`RegisterEnum map key must start with an alphabetic character (lower or upper) followed by the same or a "_", key given: ` + keyStr)
+			return nil, fmt.Errorf(`enum %s value name must start with an alphabetic character (lower or upper) followed by the same or a "_", name given: %s`, name, key)
+		}
+		if _, ok := res.entryByKey[key]; ok {
+			return nil, fmt.Errorf("enum %s cannot contain the value name %s multiple times", name, key)
+		}
+
+		goValue := reflect.ValueOf(value.Value)
+		if res.entryForValue(goValue) != nil {
+			return nil, fmt.Errorf("enum %s cannot contain the value %v multiple times", name, value.Value)
 		}
 
-		entries[i] = enumEntry{
-			keyBytes: []byte(keyStr),
-			key:      keyStr,
-			value:    iter.Value(),
+		res.entries[idx] = enumEntry{
+			keyBytes: []byte(key),
+			key:      key,
+			value:    goValue,
 		}
-		qlTypeEnumValues[i] = qlEnumValue{
-			Name:              keyStr,
-			Description:       h.PtrToEmptyStr,
-			IsDeprecated:      false,
-			DeprecationReason: nil,
+		res.entryByKey[key] = idx
+		switch res.contentKind {
+		case reflect.String:
+			res.entryByString[goValue.String()] = idx
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			res.entryByNumber[uint64(goValue.Int())] = idx
+		default:
+			res.entryByNumber[goValue.Uint()] = idx
+		}
+
+		valueDescription := value.Description
+		qlTypeEnumValues[idx] = qlEnumValue{
+			Name:        key,
+			Description: &valueDescription,
+		}
+		if value.DeprecationReason != "" {
+			reason := value.DeprecationReason
+			qlTypeEnumValues[idx].IsDeprecated = true
+			qlTypeEnumValues[idx].DeprecationReason = &reason
 		}
-		i++
 	}
 	sort.Slice(qlTypeEnumValues, func(a int, b int) bool { return qlTypeEnumValues[a].Name < qlTypeEnumValues[b].Name })
 
-	name := contentType.Name()
-	qlType := qlType{
+	res.qlType = qlType{
 		Kind:        typeKindEnum,
 		Name:        &name,
 		Description: h.PtrToEmptyStr,
-		EnumValues:  func(args isDeprecatedArgs) []qlEnumValue { return qlTypeEnumValues },
+		EnumValues:  resolveEnumValues(qlTypeEnumValues),
+	}
+	if description != "" {
+		res.qlType.Description = &description
 	}
 
-	return &enum{
-		contentType: contentType,
-		contentKind: contentType.Kind(),
-		entries:     entries,
-		typeName:    name,
-		qlType:      qlType,
-	}, nil
+	return res, nil
+}
+
+// resolveEnumValues returns the resolver of the enumValues field of a enum type
+func resolveEnumValues(values []qlEnumValue) func(args isDeprecatedArgs) []qlEnumValue {
+	return func(args isDeprecatedArgs) []qlEnumValue {
+		if args.IncludeDeprecated {
+			return values
+		}
+		res := []qlEnumValue{}
+		for _, value := range values {
+			if !value.IsDeprecated {
+				res = append(res, value)
+			}
+		}
+		return res
+	}
+}
+
+// entryForKey returns the entry with the graphql name key or nil if the enum doesn't contain the key
+func (e *enum) entryForKey(key string) *enumEntry {
+	idx, ok := e.entryByKey[key]
+	if !ok {
+		return nil
+	}
+	return &e.entries[idx]
+}
+
+// entryForValue returns the entry of the go value or nil if the value is not part of the enum
+func (e *enum) entryForValue(value reflect.Value) *enumEntry {
+	var idx int
+	var ok bool
+	switch e.contentKind {
+	case reflect.String:
+		idx, ok = e.entryByString[value.String()]
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		idx, ok = e.entryByNumber[uint64(value.Int())]
+	default:
+		idx, ok = e.entryByNumber[value.Uint()]
+	}
+	if !ok {
+		return nil
+	}
+	return &e.entries[idx]
+}
+
+// setValue sets goValue to the go value of the entry, returns false if the kind of goValue doesn't match the enum
+func (e *enum) setValue(goValue *reflect.Value, entry *enumEntry) bool {
+	switch e.contentKind {
+	case reflect.String:
+		goValue.SetString(entry.value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		goValue.SetInt(entry.value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		goValue.SetUint(entry.value.Uint())
+	default:
+		return false
+	}
+	return true
 }
@@ -1,6 +1,7 @@
 package yarql
 
 import (
+	"strings"
 	"testing"
 
 	a "github.com/mjarkk/yarql/assert"
@@ -57,12 +58,11 @@ func TestRegisterEnumFails(t *testing.T) {
 	_, err = registerEnumCheck(map[string]TestEnum{"": ""})
 	a.Error(t, err, "Enum keys cannot be empty")
 
-	// Maybe fix this??
-	// _, err = registerEnumCheck(map[string]TestEnum{
-	// 	"Foo": "Baz",
-	// 	"Bar": "Baz",
-	// })
-	// Error(t, err, "Enum cannot have duplicated values")
+	_, err = registerEnumCheck(map[string]TestEnum{
+		"Foo": "Baz",
+		"Bar": "Baz",
+	})
+	a.Error(t, err, "Enum cannot have duplicated values")
 
 	_, err = registerEnumCheck(map[string]TestEnum{"1": ""})
 	a.Error(t, err, "Enum cannot have an invalid graphql name, where first letter is number")
@@ -105,3 +105,172 @@ func TestEnum(t *testing.T) {
 	}
 	a.Equal(t, `{"bar":"BAZ"}`, res)
 }
+
+type TestEnumFruit uint8
+
+const (
+	TestEnumFruitApple TestEnumFruit = iota
+	TestEnumFruitPeer
+	TestEnumFruitGrapefruit
+	TestEnumFruitUnknown
+)
+
+func (f TestEnumFruit) String() string {
+	switch f {
+	case TestEnumFruitApple:
+		return "APPLE"
+	case TestEnumFruitPeer:
+		return "PEER"
+	case TestEnumFruitGrapefruit:
+		return "GRAPEFRUIT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+type TestEnumFruitQuery struct {
+	Favorite TestEnumFruit
+}
+
+func (TestEnumFruitQuery) ResolveEcho(args struct{ Fruit TestEnumFruit }) TestEnumFruit {
+	return args.Fruit
+}
+
+var testEnumFruit = Enum{
+	Description: "A fruit that can be ordered",
+	Values: []EnumValue{
+		{Value: TestEnumFruitApple, Description: "Red or green"},
+		{Name: "PEAR", Value: TestEnumFruitPeer, DeprecationReason: "Use APPLE"},
+		{Value: TestEnumFruitGrapefruit},
+	},
+}
+
+func TestEnumType(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"output and argument", `{favorite echo(fruit: PEAR)}`, ``, `{"favorite":"GRAPEFRUIT","echo":"PEAR"}`},
+		{"variable", `query ($fruit: TestEnumFruit!) {echo(fruit: $fruit)}`, `{"fruit": "APPLE"}`, `{"echo":"APPLE"}`},
+		{"introspection", `{
+			a: __type(name: "TestEnumFruit") {description enumValues {name description}}
+			b: __type(name: "TestEnumFruit") {enumValues(includeDeprecated: true) {name isDeprecated deprecationReason}}
+		}`, ``, `{"a":{"description":"A fruit that can be ordered","enumValues":[{"name":"APPLE","description":"Red or green"},{"name":"GRAPEFRUIT","description":""}]},"b":{"enumValues":[{"name":"APPLE","isDeprecated":false,"deprecationReason":null},{"name":"GRAPEFRUIT","isDeprecated":false,"deprecationReason":null},{"name":"PEAR","isDeprecated":true,"deprecationReason":"Use APPLE"}]}}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s := NewSchema()
+			err := s.RegisterEnumType(testEnumFruit)
+			a.NoError(t, err)
+
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			res, errs := bytecodeParse(t, s, testCase.query, TestEnumFruitQuery{Favorite: TestEnumFruitGrapefruit}, M{}, opts)
+			for _, err := range errs {
+				t.Fatal(err)
+			}
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestEnumTypeInvalidValue(t *testing.T) {
+	s := NewSchema()
+	err := s.RegisterEnumType(testEnumFruit)
+	a.NoError(t, err)
+
+	// PEER is the go name of the value but the graphql name is PEAR
+	_, errs := bytecodeParse(t, s, `{echo(fruit: PEER)}`, TestEnumFruitQuery{}, M{})
+	a.Equal(t, 1, len(errs))
+}
+
+func TestEnumTypeSDL(t *testing.T) {
+	s := NewSchema()
+	err := s.RegisterEnumType(testEnumFruit)
+	a.NoError(t, err)
+	err = s.Parse(TestEnumFruitQuery{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	a.True(t, strings.Contains(sdl, "\"A fruit that can be ordered\"\nenum TestEnumFruit {\n  \"Red or green\"\n  APPLE\n  GRAPEFRUIT\n  PEAR @deprecated(reason: \"Use APPLE\")\n}\n"), sdl)
+}
+
+func TestEnumTypeUnknownValue(t *testing.T) {
+	s := NewSchema()
+	err := s.RegisterEnumType(testEnumFruit)
+	a.NoError(t, err)
+
+	res, errs := bytecodeParse(t, s, `{favorite}`, TestEnumFruitQuery{Favorite: TestEnumFruitUnknown}, M{})
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "UNKNOWN is not a valid value for enum TestEnumFruit", errs[0].Error())
+	a.Equal(t, `{"favorite":null}`, res)
+}
+
+func TestEnumValuesStringer(t *testing.T) {
+	values := EnumValues(TestEnumFruitApple, TestEnumFruitPeer)
+	a.Equal(t, 2, len(values))
+
+	s := NewSchema()
+	err := s.RegisterEnumType(Enum{Values: values})
+	a.NoError(t, err)
+
+	res, errs := bytecodeParse(t, s, `{favorite}`, TestEnumFruitQuery{Favorite: TestEnumFruitPeer}, M{}, ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"favorite":"PEER"}`, res)
+}
+
+func TestEnumTypeSameName(t *testing.T) {
+	type Status string
+	type OuterStatus = Status
+
+	// Both types are named Status but are different types
+	otherStatus, query := func() (interface{}, interface{}) {
+		type Status string
+		return Status("on"), struct {
+			A OuterStatus
+			B Status
+		}{A: "active", B: "on"}
+	}()
+
+	s := NewSchema()
+	_, err := s.RegisterEnum(map[string]Status{"ACTIVE": "active"})
+	a.NoError(t, err)
+
+	_, err = s.RegisterEnum(map[string]Status{"ACTIVE": "active"})
+	a.Error(t, err, "The same type cannot be registered twice")
+
+	err = s.RegisterEnumType(Enum{Values: []EnumValue{{Name: "ON", Value: otherStatus}}})
+	a.Error(t, err, "The name Status is already used")
+
+	err = s.RegisterEnumType(Enum{Name: "OtherStatus", Values: []EnumValue{{Name: "ON", Value: otherStatus}}})
+	a.NoError(t, err)
+
+	res, errs := bytecodeParse(t, s, `{a b}`, query, M{}, ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"a":"ACTIVE","b":"ON"}`, res)
+	a.True(t, strings.Contains(s.SDL(), "enum OtherStatus {\n  ON\n}\n"))
+}
+
+func TestEnumTypeInvalid(t *testing.T) {
+	invalid := []Enum{
+		{},
+		{Values: []EnumValue{{Name: "A", Value: nil}}},
+		{Values: []EnumValue{{Name: "A", Value: "plain string"}}},
+		{Values: []EnumValue{{Name: "A", Value: TestEnumFruitApple}, {Name: "B", Value: TestEnum2Foo}}},
+		{Values: []EnumValue{{Name: "A", Value: TestEnumFruitApple}, {Name: "A", Value: TestEnumFruitPeer}}},
+		{Values: []EnumValue{{Name: "A", Value: TestEnumFruitApple}, {Name: "B", Value: TestEnumFruitApple}}},
+		{Values: []EnumValue{{Name: "A!", Value: TestEnumFruitApple}}},
+		{Values: []EnumValue{{Value: TestEnum2Foo}}},
+		{Name: "1Fruit", Values: []EnumValue{{Name: "A", Value: TestEnumFruitApple}}},
+	}
+	for _, enum := range invalid {
+		err := NewSchema().RegisterEnumType(enum)
+		a.Error(t, err, enum)
+	}
+}
@@ -79,8 +79,8 @@ func (m MethodRoot) ResolveCreateTodo(args CreateTodoArgs) Todo {
 // UpdateTodoArgs are the arguments for the ResolveUpdateTodo
 type UpdateTodoArgs struct {
 	ID    uint `gq:"id,id"` // rename field to id and label field to have ID type
-	Title *string
-	Done  *bool
+	Title yarql.OptionalString
+	Done  yarql.OptionalBool
 }
 
 // ResolveUpdateTodo updates a todo
@@ -96,12 +96,16 @@ func (m MethodRoot) ResolveUpdateTodo(args UpdateTodoArgs) (Todo, error) {
 		return Todo{}, fmt.Errorf("todo with id %d not found", args.ID)
 	}
 
+	if args.Title.Null || args.Done.Null {
+		return Todo{}, fmt.Errorf("title and done cannot be set to null")
+	}
+
 	todo := todos[idx]
-	if args.Title != nil {
-		todo.Title = *args.Title
+	if args.Title.Set {
+		todo.Title = args.Title.Value
 	}
-	if args.Done != nil {
-		todo.Done = *args.Done
+	if args.Done.Set {
+		todo.Done = args.Done.Value
 	}
 	todos[idx] = todo
 
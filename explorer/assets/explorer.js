@@ -0,0 +1,466 @@
+(function () {
+  'use strict';
+
+  var config = window.explorerConfig;
+  var storagePrefix = 'yarql-explorer:' + config.endpoint + ':';
+
+  var $ = function (id) {
+    return document.getElementById(id);
+  };
+  var queryEl = $('query');
+  var variablesEl = $('variables');
+  var headersEl = $('headers');
+  var resultEl = $('result');
+  var statusEl = $('status');
+  var operationEl = $('operation');
+  var docsEl = $('docs');
+  var docsNavEl = $('docs-nav');
+  var docsContentEl = $('docs-content');
+
+  var schema = null;
+  var docsHistory = [];
+  var activeSocket = null;
+
+  // Persist the editors in local storage so they survive a page reload
+  function load(key, fallback) {
+    try {
+      var value = localStorage.getItem(storagePrefix + key);
+      return value === null ? fallback : value;
+    } catch (e) {
+      return fallback;
+    }
+  }
+
+  function save(key, value) {
+    try {
+      localStorage.setItem(storagePrefix + key, value);
+    } catch (e) {
+      // Local storage might be disabled
+    }
+  }
+
+  queryEl.value = load('query', '{\n  __typename\n}\n');
+  variablesEl.value = load('variables', '');
+  headersEl.value = load('headers', JSON.stringify(config.headers || {}, null, 2));
+  $('endpoint').textContent = config.endpoint;
+
+  queryEl.addEventListener('input', function () {
+    save('query', queryEl.value);
+    updateOperations();
+  });
+  variablesEl.addEventListener('input', function () {
+    save('variables', variablesEl.value);
+  });
+  headersEl.addEventListener('input', function () {
+    save('headers', headersEl.value);
+  });
+
+  // Tabs
+  Array.prototype.forEach.call(document.querySelectorAll('nav button'), function (button) {
+    button.addEventListener('click', function () {
+      Array.prototype.forEach.call(document.querySelectorAll('nav button'), function (other) {
+        other.classList.toggle('active', other === button);
+      });
+      Array.prototype.forEach.call(document.querySelectorAll('.tab'), function (tab) {
+        tab.hidden = tab.id !== button.dataset.tab;
+      });
+    });
+  });
+
+  // Insert 2 spaces instead of moving the focus when pressing tab
+  Array.prototype.forEach.call(document.querySelectorAll('textarea'), function (textarea) {
+    textarea.addEventListener('keydown', function (e) {
+      if (e.key === 'Tab') {
+        e.preventDefault();
+        var start = textarea.selectionStart;
+        textarea.value = textarea.value.slice(0, start) + '  ' + textarea.value.slice(textarea.selectionEnd);
+        textarea.selectionStart = textarea.selectionEnd = start + 2;
+        textarea.dispatchEvent(new Event('input'));
+      } else if (e.key === 'Enter' && (e.ctrlKey || e.metaKey)) {
+        e.preventDefault();
+        run();
+      }
+    });
+  });
+
+  $('run').addEventListener('click', run);
+  $('prettify').addEventListener('click', function () {
+    queryEl.value = prettify(queryEl.value);
+    queryEl.dispatchEvent(new Event('input'));
+  });
+  $('toggle-docs').addEventListener('click', function () {
+    docsEl.hidden = !docsEl.hidden;
+  });
+
+  function setStatus(text, isError) {
+    statusEl.textContent = text;
+    statusEl.classList.toggle('error', !!isError);
+  }
+
+  function parseJSONEditor(el, name) {
+    var value = el.value.trim();
+    if (!value) {
+      return {};
+    }
+    try {
+      return JSON.parse(value);
+    } catch (e) {
+      throw new Error(name + ' are not valid JSON: ' + e.message);
+    }
+  }
+
+  // Operations returns the operations defined in the query as {type, name}
+  function operations(query) {
+    var res = [];
+    var withoutComments = query.replace(/#[^\n]*/g, '').replace(/"""[\s\S]*?"""|"(?:\\.|[^"\\])*"/g, '""');
+    var depth = 0;
+    var re = /[{}]|\b(query|mutation|subscription)\b\s*([_A-Za-z][_0-9A-Za-z]*)?/g;
+    var match;
+    while ((match = re.exec(withoutComments))) {
+      if (match[0] === '{') {
+        if (depth === 0 && res.length === 0) {
+          res.push({ type: 'query', name: '' });
+        }
+        depth++;
+      } else if (match[0] === '}') {
+        depth--;
+      } else if (depth === 0) {
+        res.push({ type: match[1], name: match[2] || '' });
+      }
+    }
+    return res;
+  }
+
+  function updateOperations() {
+    var ops = operations(queryEl.value).filter(function (op) {
+      return op.name;
+    });
+    var selected = operationEl.value;
+    operationEl.innerHTML = '';
+    ops.forEach(function (op) {
+      var option = document.createElement('option');
+      option.value = op.name;
+      option.textContent = op.type + ' ' + op.name;
+      operationEl.appendChild(option);
+    });
+    if (ops.some(function (op) { return op.name === selected; })) {
+      operationEl.value = selected;
+    }
+    operationEl.hidden = ops.length < 2;
+  }
+  updateOperations();
+
+  function run() {
+    var body;
+    var headers;
+    try {
+      body = {
+        query: queryEl.value,
+        variables: parseJSONEditor(variablesEl, 'Variables'),
+      };
+      headers = parseJSONEditor(headersEl, 'Headers');
+    } catch (e) {
+      setStatus(e.message, true);
+      return;
+    }
+    if (!operationEl.hidden && operationEl.value) {
+      body.operationName = operationEl.value;
+    }
+
+    var ops = operations(body.query).filter(function (op) {
+      return !body.operationName || op.name === body.operationName;
+    });
+    if (ops.length && ops[0].type === 'subscription') {
+      subscribe(body, headers);
+      return;
+    }
+
+    setStatus('Loading\u2026');
+    var start = Date.now();
+    request(body, headers)
+      .then(function (res) {
+        setStatus(res.status + ' ' + res.statusText + ' in ' + (Date.now() - start) + 'ms', res.status >= 400);
+        resultEl.textContent = res.body;
+      })
+      .catch(function (e) {
+        setStatus('Request failed: ' + e.message, true);
+      });
+  }
+
+  function request(body, headers) {
+    var allHeaders = {
+      'Content-Type': 'application/json',
+      Accept: 'application/graphql-response+json, application/json;q=0.9',
+    };
+    Object.keys(headers || {}).forEach(function (key) {
+      allHeaders[key] = headers[key];
+    });
+
+    return fetch(config.endpoint, {
+      method: 'POST',
+      headers: allHeaders,
+      body: JSON.stringify(body),
+      credentials: 'same-origin',
+    }).then(function (res) {
+      return res.text().then(function (text) {
+        var pretty = text;
+        try {
+          pretty = JSON.stringify(JSON.parse(text), null, 2);
+        } catch (e) {
+          // Not JSON, show the raw response
+        }
+        return { status: res.status, statusText: res.statusText, body: pretty, text: text };
+      });
+    });
+  }
+
+  // subscribe executes a subscription using the graphql-transport-ws protocol
+  function subscribe(body, headers) {
+    if (!config.subscriptionUrl) {
+      setStatus('Subscriptions are not configured', true);
+      return;
+    }
+    if (activeSocket) {
+      activeSocket.close();
+    }
+
+    var socket = new WebSocket(config.subscriptionUrl, 'graphql-transport-ws');
+    activeSocket = socket;
+    var messages = [];
+    resultEl.textContent = '';
+    setStatus('Connecting\u2026');
+
+    socket.onopen = function () {
+      socket.send(JSON.stringify({ type: 'connection_init', payload: headers }));
+    };
+    socket.onmessage = function (event) {
+      var message = JSON.parse(event.data);
+      switch (message.type) {
+        case 'connection_ack':
+          setStatus('Subscribed');
+          socket.send(JSON.stringify({ id: '1', type: 'subscribe', payload: body }));
+          break;
+        case 'ping':
+          socket.send(JSON.stringify({ type: 'pong' }));
+          break;
+        case 'next':
+        case 'error':
+          messages.unshift(message.payload);
+          resultEl.textContent = messages.map(function (payload) {
+            return JSON.stringify(payload, null, 2);
+          }).join('\n\n');
+          break;
+        case 'complete':
+          setStatus('Subscription completed');
+          socket.close();
+          break;
+      }
+    };
+    socket.onerror = function () {
+      setStatus('Subscription connection failed', true);
+    };
+    socket.onclose = function () {
+      if (activeSocket === socket) {
+        activeSocket = null;
+      }
+    };
+  }
+
+  // prettify re-indents the query based on the braces and parentheses
+  function prettify(query) {
+    var out = '';
+    var indent = 0;
+    var lines = query.split('\n');
+    lines.forEach(function (rawLine) {
+      var line = rawLine.trim();
+      if (!line) {
+        return;
+      }
+      var closing = /^[}\)]/.test(line) ? 1 : 0;
+      out += new Array(Math.max(indent - closing, 0) + 1).join('  ') + line + '\n';
+      var withoutStrings = line.replace(/"(?:\\.|[^"\\])*"/g, '""').replace(/#.*/, '');
+      indent += (withoutStrings.match(/[{(]/g) || []).length;
+      indent -= (withoutStrings.match(/[})]/g) || []).length;
+      indent = Math.max(indent, 0);
+    });
+    return out;
+  }
+
+  // Documentation explorer
+  function loadSchema() {
+    var headers;
+    try {
+      headers = parseJSONEditor(headersEl, 'Headers');
+    } catch (e) {
+      headers = {};
+    }
+
+    request({ query: config.introspectionQuery, operationName: 'IntrospectionQuery' }, headers)
+      .then(function (res) {
+        var parsed = JSON.parse(res.text);
+        if (parsed.errors && parsed.errors.length) {
+          throw new Error(parsed.errors[0].message);
+        }
+        schema = parsed.data.__schema;
+        showRoot();
+      })
+      .catch(function (e) {
+        docsContentEl.textContent = 'Unable to load schema: ' + e.message;
+      });
+  }
+
+  function el(tag, className, text) {
+    var res = document.createElement(tag);
+    if (className) {
+      res.className = className;
+    }
+    if (text !== undefined) {
+      res.textContent = text;
+    }
+    return res;
+  }
+
+  function typeLink(name) {
+    var link = el('a', '', name);
+    link.addEventListener('click', function () {
+      showType(name, true);
+    });
+    return link;
+  }
+
+  function appendTypeRef(parent, ref) {
+    if (ref.kind === 'NON_NULL') {
+      appendTypeRef(parent, ref.ofType);
+      parent.appendChild(document.createTextNode('!'));
+    } else if (ref.kind === 'LIST') {
+      parent.appendChild(document.createTextNode('['));
+      appendTypeRef(parent, ref.ofType);
+      parent.appendChild(document.createTextNode(']'));
+    } else {
+      parent.appendChild(typeLink(ref.name));
+    }
+  }
+
+  function renderNav() {
+    docsNavEl.innerHTML = '';
+    if (docsHistory.length) {
+      var back = el('a', '', '\u2190 Back');
+      back.addEventListener('click', function () {
+        docsHistory.pop();
+        var previous = docsHistory[docsHistory.length - 1];
+        if (previous) {
+          showType(previous, false);
+        } else {
+          showRoot();
+        }
+      });
+      docsNavEl.appendChild(back);
+    }
+  }
+
+  function showRoot() {
+    docsHistory = [];
+    renderNav();
+    docsContentEl.innerHTML = '';
+    docsContentEl.appendChild(el('h2', '', 'Schema'));
+    if (schema.description) {
+      docsContentEl.appendChild(el('div', 'description', schema.description));
+    }
+
+    [['query', schema.queryType], ['mutation', schema.mutationType], ['subscription', schema.subscriptionType]].forEach(function (root) {
+      if (!root[1]) {
+        return;
+      }
+      var row = el('div', 'field', root[0] + ': ');
+      row.appendChild(typeLink(root[1].name));
+      docsContentEl.appendChild(row);
+    });
+
+    docsContentEl.appendChild(el('h2', '', 'Types'));
+    schema.types
+      .filter(function (type) {
+        return type.name.indexOf('__') !== 0;
+      })
+      .forEach(function (type) {
+        var row = el('div', 'field');
+        row.appendChild(typeLink(type.name));
+        docsContentEl.appendChild(row);
+      });
+  }
+
+  function showType(name, push) {
+    var type = schema.types.filter(function (type) {
+      return type.name === name;
+    })[0];
+    if (!type) {
+      return;
+    }
+    if (push) {
+      docsHistory.push(name);
+    }
+    renderNav();
+
+    docsContentEl.innerHTML = '';
+    docsContentEl.appendChild(el('h2', '', type.name));
+    docsContentEl.appendChild(el('div', 'description', type.description || type.kind.toLowerCase().replace('_', ' ')));
+
+    if (type.interfaces && type.interfaces.length) {
+      var implementsRow = el('div', 'field', 'implements ');
+      type.interfaces.forEach(function (ref, idx) {
+        if (idx) {
+          implementsRow.appendChild(document.createTextNode(' & '));
+        }
+        appendTypeRef(implementsRow, ref);
+      });
+      docsContentEl.appendChild(implementsRow);
+    }
+
+    (type.fields || type.inputFields || []).forEach(function (field) {
+      var row = el('div', 'field' + (field.isDeprecated ? ' deprecated' : ''), field.name);
+      if (field.args && field.args.length) {
+        row.appendChild(document.createTextNode('('));
+        field.args.forEach(function (arg, idx) {
+          if (idx) {
+            row.appendChild(document.createTextNode(', '));
+          }
+          row.appendChild(document.createTextNode(arg.name + ': '));
+          appendTypeRef(row, arg.type);
+          if (arg.defaultValue !== null && arg.defaultValue !== undefined) {
+            row.appendChild(document.createTextNode(' = ' + arg.defaultValue));
+          }
+        });
+        row.appendChild(document.createTextNode(')'));
+      }
+      row.appendChild(document.createTextNode(': '));
+      appendTypeRef(row, field.type);
+      if (field.defaultValue !== null && field.defaultValue !== undefined) {
+        row.appendChild(document.createTextNode(' = ' + field.defaultValue));
+      }
+      docsContentEl.appendChild(row);
+      if (field.description) {
+        docsContentEl.appendChild(el('div', 'description', field.description));
+      }
+      if (field.deprecationReason) {
+        docsContentEl.appendChild(el('div', 'description', 'Deprecated: ' + field.deprecationReason));
+      }
+    });
+
+    (type.enumValues || []).forEach(function (value) {
+      docsContentEl.appendChild(el('div', 'field' + (value.isDeprecated ? ' deprecated' : ''), value.name));
+      if (value.description) {
+        docsContentEl.appendChild(el('div', 'description', value.description));
+      }
+    });
+
+    if (type.possibleTypes && type.possibleTypes.length) {
+      docsContentEl.appendChild(el('div', 'description', 'Possible types:'));
+      type.possibleTypes.forEach(function (ref) {
+        var row = el('div', 'field');
+        appendTypeRef(row, ref);
+        docsContentEl.appendChild(row);
+      });
+    }
+  }
+
+  loadSchema();
+})();
@@ -0,0 +1,120 @@
+// Package explorer serves a GraphQL IDE that can be used to explore and query a schema
+//
+// All assets are embedded in the binary so no CDN or internet connection is required
+package explorer
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/http"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// IntrospectionQuery is the introspection query used by the explorer to obtain the schema
+//
+//go:embed introspection.graphql
+var IntrospectionQuery string
+
+// Options are the options for the explorer
+type Options struct {
+	// Endpoint is the url of the GraphQL endpoint, defaults to /graphql
+	Endpoint string
+
+	// Headers are the default headers send with every request, these can be changed in the explorer
+	Headers map[string]string
+
+	// SubscriptionURL is the websocket url used for subscriptions (graphql-transport-ws protocol)
+	// If empty subscriptions are disabled
+	SubscriptionURL string
+
+	// Title is the title of the page, defaults to YarQL explorer
+	Title string
+}
+
+type pageConfig struct {
+	Endpoint           string            `json:"endpoint"`
+	Headers            map[string]string `json:"headers"`
+	SubscriptionURL    string            `json:"subscriptionUrl"`
+	IntrospectionQuery string            `json:"introspectionQuery"`
+}
+
+type pageData struct {
+	Title  string
+	Config pageConfig
+	Script template.JS
+	Style  template.CSS
+}
+
+type handler struct {
+	page []byte
+}
+
+// New returns a http.Handler that serves the explorer
+//
+// Example:
+//   http.Handle("/explorer", explorer.New(explorer.Options{Endpoint: "/graphql"}))
+func New(options Options) http.Handler {
+	if options.Endpoint == "" {
+		options.Endpoint = "/graphql"
+	}
+	if options.Title == "" {
+		options.Title = "YarQL explorer"
+	}
+	if options.Headers == nil {
+		options.Headers = map[string]string{}
+	}
+
+	page, err := renderPage(options)
+	if err != nil {
+		panic("INTERNAL ERROR: unable to render explorer page, " + err.Error())
+	}
+	return &handler{page: page}
+}
+
+func renderPage(options Options) ([]byte, error) {
+	script, err := assets.ReadFile("assets/explorer.js")
+	if err != nil {
+		return nil, err
+	}
+	style, err := assets.ReadFile("assets/explorer.css")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.ParseFS(assets, "assets/index.html")
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = tmpl.Execute(buf, pageData{
+		Title: options.Title,
+		Config: pageConfig{
+			Endpoint:           options.Endpoint,
+			Headers:            options.Headers,
+			SubscriptionURL:    options.SubscriptionURL,
+			IntrospectionQuery: IntrospectionQuery,
+		},
+		Script: template.JS(script),
+		Style:  template.CSS(style),
+	})
+	return buf.Bytes(), err
+}
+
+// ServeHTTP implements http.Handler
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(h.page)
+	}
+}
@@ -0,0 +1,248 @@
+package explorer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	yarql "github.com/mjarkk/yarql"
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+type inputValue struct {
+	Name string  `json:"name"`
+	Type typeRef `json:"type"`
+}
+
+type fullType struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Fields []struct {
+		Name string       `json:"name"`
+		Args []inputValue `json:"args"`
+		Type typeRef      `json:"type"`
+	} `json:"fields"`
+	InputFields   []inputValue `json:"inputFields"`
+	Interfaces    []typeRef    `json:"interfaces"`
+	EnumValues    []struct{}   `json:"enumValues"`
+	PossibleTypes []typeRef    `json:"possibleTypes"`
+}
+
+type introspectionResult struct {
+	Data struct {
+		Schema struct {
+			QueryType    *struct{ Name string } `json:"queryType"`
+			MutationType *struct{ Name string } `json:"mutationType"`
+			Types        []fullType             `json:"types"`
+			Directives   []struct {
+				Name      string       `json:"name"`
+				Locations []string     `json:"locations"`
+				Args      []inputValue `json:"args"`
+			} `json:"directives"`
+		} `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type testFruit uint8
+
+const (
+	testFruitApple testFruit = iota
+	testFruitPeer
+)
+
+type testNode interface {
+	ResolveId() (string, yarql.AttrIsID)
+}
+
+type testPost struct {
+	Title     string
+	Fruit     testFruit
+	CreatedAt time.Time
+}
+
+func (testPost) ResolveId() (string, yarql.AttrIsID) {
+	return "1", 0
+}
+
+type testQuery struct {
+	Posts []testPost
+}
+
+func (testQuery) ResolveNode(args struct{ ID string }) testNode {
+	return testPost{}
+}
+
+type testPostInput struct {
+	Title string
+	Fruit *testFruit
+}
+
+type testMutation struct{}
+
+func (testMutation) ResolveCreatePost(args struct{ Post testPostInput }) testPost {
+	return testPost{Title: args.Post.Title}
+}
+
+func newTestSchema(t *testing.T) *yarql.Schema {
+	s := yarql.NewSchema()
+	_, err := s.RegisterEnum(map[string]testFruit{
+		"APPLE": testFruitApple,
+		"PEER":  testFruitPeer,
+	})
+	a.NoError(t, err)
+	yarql.Implements((*testNode)(nil), testPost{})
+
+	err = s.Parse(testQuery{}, testMutation{}, nil)
+	a.NoError(t, err)
+	return s
+}
+
+func refName(ref typeRef) string {
+	for ref.OfType != nil {
+		ref = *ref.OfType
+	}
+	if ref.Name == nil {
+		return ""
+	}
+	return *ref.Name
+}
+
+func TestIntrospectionQuery(t *testing.T) {
+	s := newTestSchema(t)
+
+	errs := s.Resolve([]byte(IntrospectionQuery), yarql.ResolveOptions{})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+
+	var res introspectionResult
+	err := json.Unmarshal(s.Result, &res)
+	a.NoError(t, err)
+	a.Equal(t, 0, len(res.Errors))
+
+	schema := res.Data.Schema
+	a.NotNil(t, schema.QueryType)
+	a.NotNil(t, schema.MutationType)
+	a.Equal(t, "testQuery", schema.QueryType.Name)
+	a.Equal(t, "testMutation", schema.MutationType.Name)
+
+	types := map[string]fullType{}
+	for _, qlType := range schema.Types {
+		types[qlType.Name] = qlType
+	}
+
+	// Every type referenced in the schema should be defined with the same kind
+	checkRef := func(ref typeRef, location string) {
+		name := refName(ref)
+		definition, ok := types[name]
+		a.True(t, ok, "%s references undefined type %s", location, name)
+
+		for ref.OfType != nil {
+			ref = *ref.OfType
+		}
+		a.Equal(t, definition.Kind, ref.Kind, location)
+	}
+	for _, qlType := range schema.Types {
+		switch qlType.Kind {
+		case "OBJECT", "INTERFACE":
+			a.NotEqual(t, 0, len(qlType.Fields), qlType.Name)
+			for _, field := range qlType.Fields {
+				checkRef(field.Type, qlType.Name+"."+field.Name)
+				for _, arg := range field.Args {
+					checkRef(arg.Type, qlType.Name+"."+field.Name+"("+arg.Name+")")
+				}
+			}
+			for _, ref := range qlType.Interfaces {
+				checkRef(ref, qlType.Name)
+			}
+		case "INPUT_OBJECT":
+			a.NotEqual(t, 0, len(qlType.InputFields), qlType.Name)
+			for _, field := range qlType.InputFields {
+				checkRef(field.Type, qlType.Name+"."+field.Name)
+			}
+		case "ENUM":
+			a.NotEqual(t, 0, len(qlType.EnumValues), qlType.Name)
+		}
+	}
+
+	a.Equal(t, "createPost", types["testMutation"].Fields[0].Name)
+	a.Equal(t, 1, len(types["testPost"].Interfaces))
+	a.Equal(t, "testNode", refName(types["testPost"].Interfaces[0]))
+	a.Equal(t, 1, len(types["testNode"].PossibleTypes))
+
+	for _, directive := range schema.Directives {
+		a.Equal(t, 1, len(directive.Args), directive.Name)
+		a.Equal(t, "if", directive.Args[0].Name)
+		checkRef(directive.Args[0].Type, "@"+directive.Name)
+	}
+}
+
+func TestIntrospectionQueryOverHTTP(t *testing.T) {
+	s := newTestSchema(t)
+
+	body, err := json.Marshal(map[string]string{"query": IntrospectionQuery, "operationName": "IntrospectionQuery"})
+	a.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	yarql.NewHTTPHandler(s, yarql.HTTPHandlerOptions{}).ServeHTTP(res, req)
+
+	a.Equal(t, http.StatusOK, res.Code)
+	var parsed introspectionResult
+	err = json.Unmarshal(res.Body.Bytes(), &parsed)
+	a.NoError(t, err)
+	a.Equal(t, 0, len(parsed.Errors))
+	a.NotEqual(t, 0, len(parsed.Data.Schema.Types))
+}
+
+func TestHandler(t *testing.T) {
+	h := New(Options{
+		Endpoint:        "/api/graphql",
+		Headers:         map[string]string{"Authorization": "Bearer </script><script>alert(1)</script>"},
+		SubscriptionURL: "ws://localhost/graphql",
+		Title:           "My <API>",
+	})
+
+	req := httptest.NewRequest("GET", "/explorer", nil)
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, "text/html; charset=utf-8", res.Header().Get("Content-Type"))
+
+	page := res.Body.String()
+	a.True(t, strings.Contains(page, "<title>My &lt;API&gt;</title>"))
+	a.True(t, strings.Contains(page, `"endpoint":"/api/graphql"`))
+	a.True(t, strings.Contains(page, `"subscriptionUrl":"ws://localhost/graphql"`))
+	a.False(t, strings.Contains(page, "<script>alert(1)</script>"), "headers should be escaped")
+	a.False(t, strings.Contains(page, "cdn"), "all assets should be embedded")
+	a.True(t, strings.Contains(page, "loadSchema()"), "the script should be embedded")
+
+	req = httptest.NewRequest("POST", "/explorer", nil)
+	res = httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	a.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
+
+func TestHandlerDefaults(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	res := httptest.NewRecorder()
+	New(Options{}).ServeHTTP(res, req)
+
+	page := res.Body.String()
+	a.True(t, strings.Contains(page, `"endpoint":"/graphql"`))
+	a.True(t, strings.Contains(page, "<title>YarQL explorer</title>"))
+}
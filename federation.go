@@ -0,0 +1,295 @@
+package yarql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mjarkk/yarql/bytecode"
+	h "github.com/mjarkk/yarql/helpers"
+)
+
+// federationLinkURL is the version of the apollo federation spec the subgraph schema is written for
+const federationLinkURL = "https://specs.apollo.dev/federation/v2.0"
+
+// Entity is a type that can be referenced and resolved by a apollo federation gateway using its key fields
+type Entity struct {
+	// Type is a value of the go struct that is the entity, like Product{}
+	Type interface{}
+
+	// Keys are the field sets that identify the entity, every key is added as @key(fields: "..") to the type
+	// A key can contain multiple and nested fields, like "upc" or "id organization { id }"
+	Keys []string
+
+	// Shareable marks every field of the type as resolvable by multiple subgraphs using the @shareable directive
+	Shareable bool
+
+	// Resolve is the reference resolver of the entity
+	// It's called with the representation send by the gateway, this contains the __typename and key fields of the entity.
+	// The returned value must be of the entity type or a pointer to it, return nil if the entity does not exist.
+	//
+	// If Resolve is not set the keys are marked with resolvable: false as this subgraph can only reference the entity
+	Resolve func(ctx *Ctx, representation Representation) (interface{}, error)
+
+	typeName string
+	goType   reflect.Type
+}
+
+// Representation is the _Any scalar used by apollo federation to send the key fields of an entity to a subgraph
+// The values are decoded like encoding/json does when decoding into a interface{}, numbers are float64 values and nested objects are map[string]interface{} values
+//
+// Example:
+//   Representation{"__typename": "Product", "upc": "1"}
+type Representation map[string]interface{}
+
+// TypeName returns the __typename of the representation
+func (r Representation) TypeName() string {
+	name, _ := r["__typename"].(string)
+	return name
+}
+
+var representationType = reflect.TypeOf(Representation{})
+
+// _Entity is the union of all entities, the go type is only used to identify the union
+type _Entity interface{}
+
+// _Service is the result of the _service field
+type _Service struct {
+	Sdl string
+}
+
+var scalarAny = qlType{
+	Kind:        typeKindScalar,
+	Name:        h.StrPtr("_Any"),
+	Description: h.StrPtr("The _Any scalar is used to pass representations of entities from external services into the root _entities field for execution"),
+}
+
+type federation struct {
+	entities map[string]*Entity
+}
+
+// RegisterEntity registers a apollo federation entity
+// Federation must be enabled using the Federation schema option
+//
+// Must be called before (*Schema).Parse
+//
+// Example:
+//   err := schema.RegisterEntity(yarql.Entity{
+//     Type: Product{},
+//     Keys: []string{"upc"},
+//     Resolve: func(ctx *yarql.Ctx, representation yarql.Representation) (interface{}, error) {
+//       upc, _ := representation["upc"].(string)
+//       return products[upc], nil
+//     },
+//   })
+func (s *Schema) RegisterEntity(entity Entity) error {
+	if s.parsed {
+		return errors.New("(*yarql.Schema).RegisterEntity() cannot be ran after (*yarql.Schema).Parse()")
+	}
+
+	if entity.Type == nil {
+		return errors.New("entity type cannot be nil")
+	}
+	entity.goType = reflect.TypeOf(entity.Type)
+	if entity.goType.Kind() != reflect.Struct || entity.goType.Name() == "" {
+		return fmt.Errorf("entity type must be a named struct, got %s", entity.goType.String())
+	}
+	if len(entity.Keys) == 0 {
+		return fmt.Errorf("entity %s must have at least one key", entity.goType.Name())
+	}
+
+	for _, registered := range s.definedEntities {
+		if registered.goType == entity.goType {
+			return fmt.Errorf("entity %s is already registered", entity.goType.Name())
+		}
+	}
+
+	s.definedEntities = append(s.definedEntities, &entity)
+	return nil
+}
+
+// injectFederation adds the entities, the _service field and the _entities field to the schema
+// https://www.apollographql.com/docs/federation/subgraph-spec
+func (s *Schema) injectFederation(ctx *parseCtx) error {
+	entityImplementations := []*obj{}
+	for _, entity := range s.definedEntities {
+		ref, err := ctx.check(entity.goType, false)
+		if err != nil {
+			return err
+		}
+		entityObj := s.types[ref.typeName]
+		entity.typeName = entityObj.typeName
+
+		for _, key := range entity.Keys {
+			err = checkFieldSet(entityObj, key)
+			if err != nil {
+				return fmt.Errorf("invalid key %q on entity %s, %s", key, entity.typeName, err.Error())
+			}
+
+			directive := "@key(fields: " + bytecode.QuoteString(key)
+			if entity.Resolve == nil {
+				directive += ", resolvable: false"
+			}
+			entityObj.appliedDirectives = append(entityObj.appliedDirectives, directive+")")
+		}
+		if entity.Shareable {
+			entityObj.appliedDirectives = append(entityObj.appliedDirectives, "@shareable")
+		}
+
+		s.federation.entities[entity.typeName] = entity
+		entityImplementations = append(entityImplementations, ref)
+	}
+
+	// Inject _service: _Service!
+	serviceResolver := func(ctx *Ctx) _Service {
+		return _Service{Sdl: ctx.schema.federationSDL()}
+	}
+	err := s.injectFederationField(ctx, "_service", reflect.ValueOf(serviceResolver))
+	if err != nil {
+		return err
+	}
+	s.rootQuery.objContents[getObjKey([]byte("_service"))].isServiceSDL = true
+
+	if len(entityImplementations) == 0 {
+		// The _Entity union and _entities field are only added if there are entities
+		return nil
+	}
+
+	// Inject union _Entity, as there are no unions in go this is a interface with isUnion set
+	entityGoType := reflect.TypeOf((*_Entity)(nil)).Elem()
+	s.interfaces["_Entity"] = &obj{
+		valueType:       valueTypeInterface,
+		typeName:        "_Entity",
+		typeNameBytes:   []byte("_Entity"),
+		goTypeName:      entityGoType.Name(),
+		goPkgPath:       entityGoType.PkgPath(),
+		isUnion:         true,
+		objContents:     map[uint32]*obj{},
+		implementations: entityImplementations,
+	}
+
+	// Inject _entities(representations: [_Any!]!): [_Entity]!
+	return s.injectFederationField(ctx, "_entities", reflect.ValueOf(resolveEntities))
+}
+
+func (s *Schema) injectFederationField(ctx *parseCtx, name string, resolver reflect.Value) error {
+	functionObj, err := ctx.checkStructFieldFunc(name, resolver.Type(), false, -1)
+	if err != nil {
+		return err
+	}
+
+	functionObj.customObjValue = &resolver
+	functionObj.qlFieldName = []byte(name)
+	s.rootQuery.objContents[getObjKey(functionObj.qlFieldName)] = functionObj
+	return nil
+}
+
+// resolveEntities resolves the _entities field by calling the reference resolver of every representation
+func resolveEntities(ctx *Ctx, args struct{ Representations []Representation }) []_Entity {
+	res := make([]_Entity, len(args.Representations))
+	for idx, representation := range args.Representations {
+		typeName := representation.TypeName()
+		entity, ok := ctx.schema.federation.entities[typeName]
+		if !ok {
+			ctx.errf("unknown entity type %q", typeName)
+			continue
+		}
+		if entity.Resolve == nil {
+			ctx.errf("entity %s cannot be resolved by this service", typeName)
+			continue
+		}
+
+		value, err := entity.Resolve(ctx, representation)
+		if err != nil {
+			ctx.err(err.Error())
+			continue
+		}
+		if value == nil {
+			continue
+		}
+
+		goValue := reflect.ValueOf(value)
+		if goValue.Kind() == reflect.Ptr {
+			if goValue.IsNil() {
+				continue
+			}
+			goValue = goValue.Elem()
+		}
+		if goValue.Type() != entity.goType {
+			ctx.errf("reference resolver of %s returned %T, expected %s", typeName, value, entity.goType.Name())
+			continue
+		}
+		res[idx] = goValue.Interface()
+	}
+	return res
+}
+
+// checkFieldSet checks if the top level fields of a field set like "id organization { id }" exist on item
+func checkFieldSet(item *obj, fieldSet string) error {
+	fieldSet = strings.NewReplacer("{", " { ", "}", " } ").Replace(fieldSet)
+	fields := strings.Fields(fieldSet)
+	if len(fields) == 0 {
+		return errors.New("field set cannot be empty")
+	}
+
+	depth := 0
+	for _, field := range fields {
+		switch field {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth < 0 {
+				return errors.New("unexpected }")
+			}
+		default:
+			if depth > 0 {
+				continue
+			}
+			if _, ok := item.objContents[getObjKey([]byte(field))]; !ok {
+				return fmt.Errorf("field %s does not exists on %s", field, item.typeName)
+			}
+		}
+	}
+	if depth != 0 {
+		return errors.New("missing }")
+	}
+	return nil
+}
+
+// federationFieldDirectives returns the federation directives of a field
+func federationFieldDirectives(external bool, shareable bool, requires string, provides string) []string {
+	var res []string
+	if external {
+		res = append(res, "@external")
+	}
+	if shareable {
+		res = append(res, "@shareable")
+	}
+	if requires != "" {
+		res = append(res, "@requires(fields: "+bytecode.QuoteString(requires)+")")
+	}
+	if provides != "" {
+		res = append(res, "@provides(fields: "+bytecode.QuoteString(provides)+")")
+	}
+	return res
+}
+
+// federationTypeNames are the types added by federation, these are not part of the subgraph SDL
+var federationTypeNames = map[string]bool{
+	"_Any":     true,
+	"_Entity":  true,
+	"_Service": true,
+}
+
+// federationFieldNames are the query fields added by federation, these are not part of the subgraph SDL
+var federationFieldNames = map[string]bool{
+	"_service":  true,
+	"_entities": true,
+}
+
+// federationSDL returns the SDL of the subgraph as returned by the _service field
+func (s *Schema) federationSDL() string {
+	return "extend schema @link(url: " + bytecode.QuoteString(federationLinkURL) + ", import: [\"@key\", \"@shareable\", \"@external\", \"@requires\", \"@provides\"])\n\n" + s.SDL()
+}
@@ -0,0 +1,229 @@
+package yarql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type FederationTestProduct struct {
+	Upc    string
+	Name   string `gq:",shareable"`
+	Weight int    `gq:",external"`
+	Price  int    `gq:",external"`
+}
+
+func (p FederationTestProduct) ResolveShippingEstimate() int {
+	return p.Weight * p.Price
+}
+
+type FederationTestReview struct {
+	Body    string
+	Product FederationTestProduct `gqProvides:"name"`
+}
+
+type FederationTestUser struct {
+	ID string `gq:"id,id"`
+}
+
+type FederationTestQuery struct {
+	Reviews []FederationTestReview
+	Author  FederationTestUser
+}
+
+var federationTestProducts = map[string]FederationTestProduct{
+	"1": {Upc: "1", Name: "Table", Weight: 10},
+	"2": {Upc: "2", Name: "Chair", Weight: 4},
+}
+
+func newFederationTestSchema(t *testing.T) *Schema {
+	s := NewSchema()
+	err := s.RegisterEntity(Entity{
+		Type: FederationTestProduct{},
+		Keys: []string{"upc"},
+		Resolve: func(ctx *Ctx, representation Representation) (interface{}, error) {
+			upc, _ := representation["upc"].(string)
+			if upc == "error" {
+				return nil, errors.New("product service is down")
+			}
+			product, ok := federationTestProducts[upc]
+			if !ok {
+				return nil, nil
+			}
+			if price, ok := representation["price"].(float64); ok {
+				product.Price = int(price)
+			}
+			return &product, nil
+		},
+	})
+	a.NoError(t, err)
+	err = s.RegisterEntity(Entity{
+		Type: FederationTestUser{},
+		Keys: []string{"id"},
+	})
+	a.NoError(t, err)
+
+	err = s.Parse(FederationTestQuery{}, M{}, &SchemaOptions{Federation: true})
+	a.NoError(t, err)
+	err = s.SetFieldOptions("FederationTestProduct", "shippingEstimate", FieldOptions{Requires: "weight price"})
+	a.NoError(t, err)
+	return s
+}
+
+const federationTestEntitiesQuery = `query ($representations: [_Any!]!) {
+	_entities(representations: $representations) {
+		__typename
+		... on FederationTestProduct {
+			name
+			shippingEstimate
+		}
+	}
+}`
+
+func TestFederationEntities(t *testing.T) {
+	s := newFederationTestSchema(t)
+
+	errs := s.Resolve([]byte(federationTestEntitiesQuery), ResolveOptions{
+		NoMeta:    true,
+		Variables: `{"representations": [{"__typename": "FederationTestProduct", "upc": "2", "price": 5}, {"__typename": "FederationTestProduct", "upc": "3"}]}`,
+	})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"_entities":[{"__typename":"FederationTestProduct","name":"Chair","shippingEstimate":20},null]}`, string(s.Result))
+
+	// The representations can also be set inline
+	errs = s.Resolve([]byte(`{_entities(representations: [{__typename: "FederationTestProduct", upc: "1", extra: {list: [1, true, null, ENUM]}}]) {... on FederationTestProduct {upc name}}}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"_entities":[{"upc":"1","name":"Table"}]}`, string(s.Result))
+}
+
+func TestFederationEntitiesErrors(t *testing.T) {
+	s := newFederationTestSchema(t)
+
+	options := []struct {
+		representation string
+		err            string
+	}{
+		{`{"__typename": "FederationTestProduct", "upc": "error"}`, "product service is down"},
+		{`{"__typename": "FederationTestReview"}`, `unknown entity type "FederationTestReview"`},
+		{`{"__typename": "FederationTestUser", "id": "1"}`, "entity FederationTestUser cannot be resolved by this service"},
+		{`"FederationTestProduct"`, "cannot assign string to _Any value, expected an object"},
+	}
+	for _, option := range options {
+		errs := s.Resolve([]byte(federationTestEntitiesQuery), ResolveOptions{
+			NoMeta:    true,
+			Variables: `{"representations": [` + option.representation + `]}`,
+		})
+		a.Equal(t, 1, len(errs), option.representation)
+		a.Equal(t, option.err, errs[0].Error(), option.representation)
+	}
+
+	errs := s.Resolve([]byte(`query ($representations: [String!]!) {_entities(representations: $representations) {__typename}}`), ResolveOptions{
+		Variables: `{"representations": []}`,
+	})
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "expected variable type _Any but got String", errs[0].Error())
+}
+
+func TestFederationService(t *testing.T) {
+	s := newFederationTestSchema(t).Copy()
+
+	errs := s.Resolve([]byte(`{_service {sdl}}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	res := string(s.Result)
+	a.True(t, strings.HasPrefix(res, `{"_service":{"sdl":"extend schema @link(url: \"https://specs.apollo.dev/federation/v2.0\"`), res)
+
+	sdl := s.SDL()
+	a.True(t, strings.Contains(sdl, `type FederationTestProduct @key(fields: "upc") {`), sdl)
+	a.True(t, strings.Contains(sdl, `type FederationTestUser @key(fields: "id", resolvable: false) {`), sdl)
+	a.True(t, strings.Contains(sdl, "  name: String! @shareable\n"), sdl)
+	a.True(t, strings.Contains(sdl, "  price: Int! @external\n"), sdl)
+	a.True(t, strings.Contains(sdl, `  shippingEstimate: Int! @requires(fields: "weight price")`), sdl)
+	a.True(t, strings.Contains(sdl, `  product: FederationTestProduct! @provides(fields: "name")`), sdl)
+
+	// The federation types and fields and the empty mutation type are not part of the subgraph schema
+	a.True(t, strings.HasPrefix(sdl, "schema {\n  query: FederationTestQuery\n}\n"), sdl)
+	a.False(t, strings.Contains(sdl, "type M"), sdl)
+	for _, name := range []string{"_Any", "_Entity", "_Service", "_entities", "_service"} {
+		a.False(t, strings.Contains(sdl, name), name)
+	}
+
+	// But they are visible in the introspection
+	errs = s.Resolve([]byte(`{__type(name: "_Entity") {kind possibleTypes {name}}}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"__type":{"kind":"UNION","possibleTypes":[{"name":"FederationTestProduct"},{"name":"FederationTestUser"}]}}`, string(s.Result))
+}
+
+func TestFederationWithoutEntities(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(FederationTestQuery{}, M{}, &SchemaOptions{Federation: true})
+	a.NoError(t, err)
+
+	errs := s.Resolve([]byte(`{_service {sdl}}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 0, len(errs))
+
+	errs = s.Resolve([]byte(`{_entities(representations: []) {__typename}}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 1, len(errs))
+}
+
+func TestFederationDisableServiceSDL(t *testing.T) {
+	s := newFederationTestSchema(t)
+
+	errs := s.Resolve([]byte(`{_service {sdl}}`), ResolveOptions{NoMeta: true, DisableIntrospection: true})
+	a.Equal(t, 0, len(errs))
+
+	errs = s.Resolve([]byte(`{_service {sdl}}`), ResolveOptions{NoMeta: true, DisableIntrospection: true, DisableServiceSDL: true})
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "the service SDL is disabled, _service can't be queried", errs[0].Error())
+	a.Equal(t, `{"_service":null}`, string(s.Result))
+
+	// The option is kept when copying the schema
+	errs = s.Copy().Resolve([]byte(`{_service {sdl}}`), ResolveOptions{NoMeta: true, DisableServiceSDL: true})
+	a.Equal(t, 1, len(errs))
+}
+
+func TestFederationInvalid(t *testing.T) {
+	s := NewSchema()
+	a.NoError(t, s.RegisterEntity(Entity{Type: FederationTestUser{}, Keys: []string{"id"}}))
+	a.Error(t, s.RegisterEntity(Entity{Type: FederationTestUser{}, Keys: []string{"id"}}))
+	a.Error(t, s.RegisterEntity(Entity{Type: &FederationTestProduct{}, Keys: []string{"upc"}}))
+	a.Error(t, s.RegisterEntity(Entity{Type: FederationTestProduct{}}))
+
+	// Entities require the federation option
+	err := s.Parse(FederationTestQuery{}, M{}, nil)
+	a.Error(t, err)
+
+	s = NewSchema()
+	a.NoError(t, s.RegisterEntity(Entity{Type: FederationTestUser{}, Keys: []string{"name"}}))
+	err = s.Parse(FederationTestQuery{}, M{}, &SchemaOptions{Federation: true})
+	a.Error(t, err)
+	a.Equal(t, `invalid key "name" on entity FederationTestUser, field name does not exists on FederationTestUser`, err.Error())
+
+	// The federation struct tags require the federation option
+	err = NewSchema().Parse(FederationTestQuery{}, M{}, nil)
+	a.Error(t, err)
+}
+
+func TestCheckFieldSet(t *testing.T) {
+	item := &obj{typeName: "Foo", objContents: map[uint32]*obj{
+		getObjKey([]byte("id")):           {},
+		getObjKey([]byte("organization")): {},
+	}}
+
+	a.NoError(t, checkFieldSet(item, "id"))
+	a.NoError(t, checkFieldSet(item, "id organization { id name }"))
+	a.NoError(t, checkFieldSet(item, "organization{id}"))
+	a.Error(t, checkFieldSet(item, ""))
+	a.Error(t, checkFieldSet(item, "name"))
+	a.Error(t, checkFieldSet(item, "organization { id"))
+	a.Error(t, checkFieldSet(item, "id }"))
+}
@@ -0,0 +1,156 @@
+package yarql
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FieldOptions are extra options that can be set for a field of a type
+// These options can also be set using the gq struct tag but sometimes it's easier to define them here
+type FieldOptions struct {
+	// Timeout is the max duration a resolver method of this field may take
+	// When the timeout is reached the field is resolved as null and an error is added to the response
+	// Only works on methods, set to 0 to disable the timeout
+	//
+	// Equal to the `gq:",timeout=2s"` tag
+	Timeout time.Duration
+
+	// Description is the description of the field shown in introspection and the SDL
+	// Keeps the current description if empty
+	//
+	// Equal to the `gqDescription:"The name of the user"` tag
+	Description string
+
+	// DeprecationReason marks the field as deprecated with this reason
+	// Keeps the current deprecation if empty
+	//
+	// Equal to the `gqDeprecated:"Use fullName instead"` tag
+	DeprecationReason string
+
+	// External, Shareable, Requires and Provides add the apollo federation directives to the field
+	// Can only be used if the Federation schema option is set, these are added to the directives set using the struct tags
+	//
+	// Equal to the `gq:",external"`, `gq:",shareable"`, `gqRequires:"weight"` and `gqProvides:"name"` tags
+	External  bool
+	Shareable bool
+	Requires  string
+	Provides  string
+}
+
+// SetFieldOptions sets the options of a field
+// The typeName and fieldName are the GraphQL names of the type and field
+//
+// Must be called after (*Schema).Parse
+//
+// Example:
+//   err := schema.SetFieldOptions("QueryRoot", "slowField", yarql.FieldOptions{Timeout: time.Second})
+func (s *Schema) SetFieldOptions(typeName, fieldName string, options FieldOptions) error {
+	if !s.parsed {
+		return errors.New("schema has not been parsed yet, call Parse before setting field options")
+	}
+
+	typeObj, ok := s.types[typeName]
+	if !ok {
+		typeObj, ok = s.interfaces[typeName]
+		if !ok {
+			return fmt.Errorf("unknown type %s", typeName)
+		}
+	}
+
+	field, ok := typeObj.objContents[getObjKey([]byte(fieldName))]
+	if !ok {
+		return fmt.Errorf("unknown field %s on type %s", fieldName, typeName)
+	}
+
+	if options.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+	if options.Timeout != 0 && field.valueType != valueTypeMethod {
+		return fmt.Errorf("cannot set timeout on %s.%s, timeouts can only be set on methods", typeName, fieldName)
+	}
+	directives := federationFieldDirectives(options.External, options.Shareable, options.Requires, options.Provides)
+	if len(directives) > 0 && s.federation == nil {
+		return errors.New("federation directives can only be used if the Federation schema option is set")
+	}
+	field.timeout = options.Timeout
+
+	if options.Description != "" {
+		field.description = options.Description
+	}
+	if options.DeprecationReason != "" {
+		field.deprecationReason = &options.DeprecationReason
+	}
+	if len(directives) > 0 {
+		field.appliedDirectives = append(append([]string{}, field.appliedDirectives...), directives...)
+	}
+
+	s.clearIntrospectionCache()
+	return nil
+}
+
+// TypeOptions are extra options that can be set for a type, interface, enum or input type
+type TypeOptions struct {
+	// Description is the description of the type shown in introspection and the SDL
+	Description string
+
+	// OneOf marks a input type as oneOf input object, exactly one field of the input must be set to a non null value
+	// Can only be used on input types, keeps the current value if false
+	//
+	// Equal to embedding yarql.OneOf in the input struct
+	OneOf bool
+}
+
+// SetTypeOptions sets the options of a type, interface, enum or input type
+// The typeName is the GraphQL name of the type
+//
+// Must be called after (*Schema).Parse
+//
+// Example:
+//   err := schema.SetTypeOptions("User", yarql.TypeOptions{Description: "A user of the application"})
+func (s *Schema) SetTypeOptions(typeName string, options TypeOptions) error {
+	if !s.parsed {
+		return errors.New("schema has not been parsed yet, call Parse before setting type options")
+	}
+
+	if inputType, ok := s.inTypes[typeName]; ok && options.OneOf && !inputType.isOneOf {
+		inputType.isOneOf = true
+		err := s.checkOneOfInput(inputType)
+		if err != nil {
+			inputType.isOneOf = false
+			return err
+		}
+	} else if !ok && options.OneOf {
+		return fmt.Errorf("cannot mark %s as oneOf, only input types can be oneOf", typeName)
+	}
+
+	if typeObj, ok := s.types[typeName]; ok {
+		typeObj.description = options.Description
+	} else if typeObj, ok := s.interfaces[typeName]; ok {
+		typeObj.description = options.Description
+	} else if inputType, ok := s.inTypes[typeName]; ok {
+		inputType.description = options.Description
+	} else {
+		found := false
+		for idx, enum := range s.definedEnums {
+			if enum.typeName == typeName {
+				s.definedEnums[idx].qlType.Description = &options.Description
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown type %s", typeName)
+		}
+	}
+
+	s.clearIntrospectionCache()
+	return nil
+}
+
+// clearIntrospectionCache removes the cached introspection results so they are regenerated on the next request
+func (s *Schema) clearIntrospectionCache() {
+	s.graphqlTypesMap = nil
+	s.graphqlTypesList = nil
+	s.graphqlObjFields = map[string][]qlField{}
+}
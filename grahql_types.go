@@ -12,18 +12,22 @@ import (
 var _ = TypeRename(qlSchema{}, "__Schema", true)
 
 type qlSchema struct {
+	Description *string `json:"description"`
+
 	Types func() []qlType `json:"-"`
 	// For testing perposes mainly
 	JSONTypes []qlType `json:"types" gq:"-"`
 
-	QueryType        *qlType       `json:"queryType"`
-	MutationType     *qlType       `json:"mutationType"`
-	SubscriptionType *qlType       `json:"subscriptionType"`
-	Directives       []qlDirective `json:"directives"`
+	QueryType        *qlType              `json:"queryType"`
+	MutationType     *qlType              `json:"mutationType"`
+	SubscriptionType *qlType              `json:"subscriptionType"`
+	Directives       func() []qlDirective `json:"-"`
+	// For testing perposes mainly
+	JSONDirectives []qlDirective `json:"directives" gq:"-"`
 }
 
 type isDeprecatedArgs struct {
-	IncludeDeprecated bool `json:"includeDeprecated"`
+	IncludeDeprecated bool `json:"includeDeprecated" gqDefault:"false"`
 }
 
 type __TypeKind uint8
@@ -95,6 +99,7 @@ type qlType struct {
 
 	// INPUT_OBJECT only
 	InputFields func() []qlInputValue `json:"-"`
+	IsOneOf     *bool                 `json:"isOneOf"`
 
 	// NON_NULL and LIST only
 	OfType *qlType `json:"ofType"`
@@ -181,6 +186,16 @@ var directiveLocationMap = map[string]__DirectiveLocation{
 	"INPUT_FIELD_DEFINITION": directiveLocationInputFieldDefinition,
 }
 
+// String returns the graphql name of the location
+func (l __DirectiveLocation) String() string {
+	for name, location := range directiveLocationMap {
+		if location == l {
+			return name
+		}
+	}
+	return ""
+}
+
 var _ = TypeRename(qlDirective{}, "__Directive", true)
 
 type qlDirective struct {
@@ -189,6 +204,7 @@ type qlDirective struct {
 	Locations     []__DirectiveLocation `json:"-"`
 	JSONLocations []string              `json:"locations" gq:"-"`
 	Args          []qlInputValue        `json:"args"`
+	IsRepeatable  bool                  `json:"isRepeatable"`
 }
 
 var (
@@ -229,8 +245,39 @@ var (
 		Description:    h.StrPtr("The Time scalar type references to a ISO 8601 date+time, often used to insert and/or view dates. Expects a string with the ISO 8601 format"),
 		SpecifiedByURL: h.StrPtr("https://en.wikipedia.org/wiki/ISO_8601"),
 	}
+	scalarJSON = qlType{
+		Kind:           typeKindScalar,
+		Name:           h.StrPtr("JSON"),
+		Description:    h.StrPtr("The JSON scalar type represents arbitrary JSON values, used for interface{} and json.RawMessage values"),
+		SpecifiedByURL: h.StrPtr("https://www.rfc-editor.org/rfc/rfc8259"),
+	}
+	scalarDate = qlType{
+		Kind:           typeKindScalar,
+		Name:           h.StrPtr("Date"),
+		Description:    h.StrPtr("The Date scalar type represents a calendar date without a time or timezone. Expects a string with the format YYYY-MM-DD"),
+		SpecifiedByURL: h.StrPtr("https://www.rfc-editor.org/rfc/rfc3339#section-5.6"),
+	}
+	scalarLocalTime = qlType{
+		Kind:           typeKindScalar,
+		Name:           h.StrPtr("LocalTime"),
+		Description:    h.StrPtr("The LocalTime scalar type represents a time of day without a date or timezone. Expects a string with the format HH:MM:SS with optional fractional seconds"),
+		SpecifiedByURL: h.StrPtr("https://www.rfc-editor.org/rfc/rfc3339#section-5.6"),
+	}
+	scalarDuration = qlType{
+		Kind:           typeKindScalar,
+		Name:           h.StrPtr("Duration"),
+		Description:    h.StrPtr("The Duration scalar type represents a amount of time. Expects a string like 1h30m or 250ms"),
+		SpecifiedByURL: h.StrPtr("https://pkg.go.dev/time#ParseDuration"),
+	}
 )
 
+// builtinTextScalars are the scalars of text values that are added to the schema when used
+var builtinTextScalars = map[string]qlType{
+	"Date":      scalarDate,
+	"LocalTime": scalarLocalTime,
+	"Duration":  scalarDuration,
+}
+
 var scalars = map[string]qlType{
 	"Boolean": scalarBoolean,
 	"Int":     scalarInt,
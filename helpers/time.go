@@ -9,8 +9,13 @@ import (
 var timeISO8601Layout = "2006-01-02T15:04:05.000Z"
 
 // ParseIso8601String parses a string in the ISO 8601 format
+// RFC 3339 values with a timezone offset and/or without milliseconds are also accepted
 func ParseIso8601String(val string) (time.Time, error) {
 	parsedTime, err := time.Parse(timeISO8601Layout, val)
+	if err == nil {
+		return parsedTime, nil
+	}
+	parsedTime, err = time.Parse(time.RFC3339Nano, val)
 	if err != nil {
 		return time.Time{}, errors.New("time value doesn't match the ISO 8601 layout")
 	}
@@ -18,7 +23,8 @@ func ParseIso8601String(val string) (time.Time, error) {
 }
 
 // TimeToIso8601String converts a time.Time to a string in the ISO 8601 format
+// The time is converted to UTC as the layout always ends with Z
 // The value is appended to the target
 func TimeToIso8601String(target *[]byte, t time.Time) {
-	*target = t.AppendFormat(*target, timeISO8601Layout)
+	*target = t.UTC().AppendFormat(*target, timeISO8601Layout)
 }
@@ -0,0 +1,492 @@
+package yarql
+
+import (
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mjarkk/yarql/helpers"
+	"github.com/valyala/fastjson"
+)
+
+// HTTPHandlerOptions are options for NewHTTPHandler
+type HTTPHandlerOptions struct {
+	// MaxBodySize is the max size of the request body in bytes
+	// Defaults to 10MB, set to -1 to disable the limit
+	MaxBodySize int64
+
+	// Tracing enables apollo tracing for every request
+	Tracing bool
+
+	// Tracer is called for every request and can return a Tracer that receives the tracing events of the request
+	// The handler resolves requests concurrently, return a new tracer per request or a tracer that is safe for concurrent use
+	Tracer func(r *http.Request) Tracer
+
+	// Metrics collects metrics about every request, see Metrics
+	Metrics Metrics
+
+	// Timeout is the max duration of a single operation, 0 = no timeout
+	Timeout time.Duration
+
+	// Values is called for every request and can be used to set the context values of the request
+	Values func(r *http.Request) map[string]interface{}
+
+	// DisableIntrospection is called for every request, if it returns true the __schema and __type fields can't be queried
+	// This can be used to only allow introspection for authenticated users
+	DisableIntrospection func(r *http.Request) bool
+
+	// DisableServiceSDL is called for every request, if it returns true the federation _service field can't be queried
+	// This can be used to only allow the gateway to fetch the SDL of the subgraph
+	DisableServiceSDL func(r *http.Request) bool
+
+	// Visibility is called for every request and can return a VisibilityFunc to hide types and fields for the request
+	Visibility func(r *http.Request) VisibilityFunc
+
+	// MaxFiles is the max number of files that can be uploaded in a single request, 0 = no limit
+	MaxFiles int
+
+	// MaxFileSize is the max size of a single uploaded file in bytes, 0 = no limit
+	// Note that MaxBodySize also limits the size of all files together
+	MaxFileSize int64
+
+	// AllowMultipartWithoutPreflight accepts multipart/form-data requests without a Apollo-Require-Preflight or
+	// X-Apollo-Operation-Name header
+	// Browsers send multipart forms cross-site without a CORS preflight, only disable this check if the handler is
+	// protected against CSRF in another way
+	AllowMultipartWithoutPreflight bool
+}
+
+const (
+	contentTypeJSON                = "application/json"
+	contentTypeGraphQLResponseJSON = "application/graphql-response+json"
+	contentTypeGraphQL             = "application/graphql"
+	contentTypeMultipart           = "multipart/form-data"
+
+	defaultMaxBodySize = 10 << 20
+)
+
+// HTTPHandler is a http.Handler that executes GraphQL requests
+// It follows the GraphQL over HTTP spec: https://graphql.github.io/graphql-over-http/draft/
+//
+// Create a new handler using NewHTTPHandler
+type HTTPHandler struct {
+	schemas sync.Pool
+	options HTTPHandlerOptions
+}
+
+// NewHTTPHandler creates a new http.Handler that executes GraphQL requests using the schema
+// The schema must be parsed before calling this function
+//
+// The handler is safe for concurrent use, it keeps a pool of schema copies (see (*Schema).Copy)
+//
+// Example:
+//   http.Handle("/graphql", yarql.NewHTTPHandler(schema, yarql.HTTPHandlerOptions{}))
+func NewHTTPHandler(schema *Schema, options HTTPHandlerOptions) *HTTPHandler {
+	if !schema.parsed {
+		panic("Schema has not been parsed yet, call Parse before creating a http handler")
+	}
+
+	if options.MaxBodySize == 0 {
+		options.MaxBodySize = defaultMaxBodySize
+	}
+
+	template := schema.Copy()
+	h := &HTTPHandler{options: options}
+	h.schemas.New = func() interface{} {
+		return template.Copy()
+	}
+	return h
+}
+
+// httpRequestErr is an error that occurred before a query could be executed
+type httpRequestErr struct {
+	status  int
+	message string
+}
+
+func (e httpRequestErr) Error() string {
+	return e.message
+}
+
+// ServeHTTP implements http.Handler
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	responseContentType, ok := negotiateResponseContentType(r.Header.Get("Accept"))
+	if !ok {
+		h.writeRequestErr(w, contentTypeJSON, httpRequestErr{
+			status:  http.StatusNotAcceptable,
+			message: "accept header must allow " + contentTypeGraphQLResponseJSON + " or " + contentTypeJSON,
+		})
+		return
+	}
+
+	isGet := r.Method == http.MethodGet
+	if !isGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		h.writeRequestErr(w, responseContentType, httpRequestErr{
+			status:  http.StatusMethodNotAllowed,
+			message: "method " + r.Method + " not allowed, use GET or POST",
+		})
+		return
+	}
+
+	resolveOptions := ResolveOptions{
+		Context:     r.Context(),
+		Tracing:     h.options.Tracing,
+		Metrics:     h.options.Metrics,
+		Timeout:     h.options.Timeout,
+		Headers:     r.Header,
+		noMutations: isGet,
+	}
+	if h.options.Tracer != nil {
+		resolveOptions.Tracer = h.options.Tracer(r)
+	}
+	if h.options.Values != nil {
+		values := h.options.Values(r)
+		if values != nil {
+			resolveOptions.Values = &values
+		}
+	}
+	if h.options.DisableIntrospection != nil {
+		resolveOptions.DisableIntrospection = h.options.DisableIntrospection(r)
+	}
+	if h.options.DisableServiceSDL != nil {
+		resolveOptions.DisableServiceSDL = h.options.DisableServiceSDL(r)
+	}
+	if h.options.Visibility != nil {
+		resolveOptions.Visibility = h.options.Visibility(r)
+	}
+
+	if isGet {
+		h.serveGet(w, r, resolveOptions, responseContentType)
+	} else {
+		h.servePost(w, r, resolveOptions, responseContentType)
+	}
+}
+
+func (h *HTTPHandler) serveGet(w http.ResponseWriter, r *http.Request, resolveOptions ResolveOptions, responseContentType string) {
+	query := r.URL.Query()
+	if query.Get("query") == "" {
+		h.writeRequestErr(w, responseContentType, httpRequestErr{http.StatusBadRequest, "query should be defined"})
+		return
+	}
+
+	resolveOptions.OperatorTarget = query.Get("operationName")
+	resolveOptions.Variables = query.Get("variables")
+	if len(resolveOptions.Variables) > 0 {
+		var p fastjson.Parser
+		variables, err := p.Parse(resolveOptions.Variables)
+		if err != nil || variables.Type() != fastjson.TypeObject {
+			h.writeRequestErr(w, responseContentType, httpRequestErr{http.StatusBadRequest, "expected variables to be a key value object"})
+			return
+		}
+	}
+
+	h.resolve(w, []byte(query.Get("query")), resolveOptions, responseContentType)
+}
+
+func (h *HTTPHandler) servePost(w http.ResponseWriter, r *http.Request, resolveOptions ResolveOptions, responseContentType string) {
+	if h.options.MaxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.options.MaxBodySize)
+	}
+
+	query, requests, isBatch, err := h.readPostBody(r, &resolveOptions)
+	if err != nil {
+		requestErr, ok := err.(httpRequestErr)
+		if !ok {
+			requestErr = httpRequestErr{http.StatusBadRequest, err.Error()}
+		}
+		h.writeRequestErr(w, responseContentType, requestErr)
+		return
+	}
+
+	if query != nil {
+		h.resolve(w, query, resolveOptions, responseContentType)
+		return
+	}
+
+	if isBatch {
+		h.resolveBatch(w, requests, resolveOptions, responseContentType)
+		return
+	}
+
+	queryStr, operationName, variables, err := getBodyData(requests[0])
+	if err != nil {
+		h.writeRequestErr(w, responseContentType, httpRequestErr{http.StatusBadRequest, err.Error()})
+		return
+	}
+	resolveOptions.OperatorTarget = operationName
+	resolveOptions.Variables = variables
+	h.resolve(w, s2b(queryStr), resolveOptions, responseContentType)
+}
+
+// hasPreflightHeader returns true if the request has a header that makes browsers send a CORS preflight request
+// Requests with these headers can't be send by a cross-site form, this prevents CSRF for multipart requests
+func hasPreflightHeader(r *http.Request) bool {
+	return r.Header.Get("Apollo-Require-Preflight") != "" || r.Header.Get("X-Apollo-Operation-Name") != ""
+}
+
+// readPostBody reads the body of a POST request
+// Returns the query directly if the body is a application/graphql body, otherwise the JSON request(s)
+func (h *HTTPHandler) readPostBody(r *http.Request, resolveOptions *ResolveOptions) (query []byte, requests []*fastjson.Value, isBatch bool, err error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, false, httpRequestErr{http.StatusUnsupportedMediaType, "invalid or missing content type"}
+	}
+
+	var body []byte
+	switch mediaType {
+	case contentTypeJSON:
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, nil, false, bodyReadErr(err)
+		}
+	case contentTypeGraphQL:
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, nil, false, bodyReadErr(err)
+		}
+		if len(body) == 0 {
+			return nil, nil, false, errors.New("empty body")
+		}
+		query := r.URL.Query()
+		resolveOptions.OperatorTarget = query.Get("operationName")
+		resolveOptions.Variables = query.Get("variables")
+		return body, nil, false, nil
+	case contentTypeMultipart:
+		if !h.options.AllowMultipartWithoutPreflight && !hasPreflightHeader(r) {
+			return nil, nil, false, httpRequestErr{http.StatusBadRequest, "multipart requests must set the Apollo-Require-Preflight or X-Apollo-Operation-Name header"}
+		}
+		err = r.ParseMultipartForm(32 << 20)
+		if err != nil {
+			return nil, nil, false, bodyReadErr(err)
+		}
+		err = h.checkFileLimits(r.MultipartForm)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		body = []byte(r.FormValue("operations"))
+		resolveOptions.GetFormFile = func(key string) (*multipart.FileHeader, error) {
+			files := r.MultipartForm.File[key]
+			if len(files) == 0 {
+				return nil, errors.New("file " + key + " not found")
+			}
+			return files[0], nil
+		}
+	default:
+		return nil, nil, false, httpRequestErr{http.StatusUnsupportedMediaType, "unsupported content type " + mediaType}
+	}
+
+	if len(body) == 0 {
+		return nil, nil, false, errors.New("empty body")
+	}
+
+	var p fastjson.Parser
+	v, err := p.ParseBytes(body)
+	if err != nil {
+		return nil, nil, false, errors.New("invalid json body")
+	}
+	if mediaType == contentTypeMultipart {
+		fileMap := r.FormValue("map")
+		if len(fileMap) > 0 {
+			err = applyMultipartMap(v, fileMap)
+			if err != nil {
+				return nil, nil, false, err
+			}
+		}
+	}
+	if v.Type() == fastjson.TypeArray {
+		requests = v.GetArray()
+		if len(requests) == 0 {
+			return nil, nil, false, errors.New("empty batch")
+		}
+		return nil, requests, true, nil
+	}
+	return nil, []*fastjson.Value{v}, false, nil
+}
+
+func (h *HTTPHandler) checkFileLimits(form *multipart.Form) error {
+	filesCount := 0
+	for _, files := range form.File {
+		filesCount += len(files)
+		if h.options.MaxFileSize <= 0 {
+			continue
+		}
+		for _, file := range files {
+			if file.Size > h.options.MaxFileSize {
+				return httpRequestErr{http.StatusRequestEntityTooLarge, "file " + file.Filename + " is too large"}
+			}
+		}
+	}
+
+	if h.options.MaxFiles > 0 && filesCount > h.options.MaxFiles {
+		return httpRequestErr{http.StatusRequestEntityTooLarge, "too many files, max " + strconv.Itoa(h.options.MaxFiles) + " files allowed"}
+	}
+	return nil
+}
+
+func bodyReadErr(err error) error {
+	if strings.Contains(err.Error(), "request body too large") {
+		return httpRequestErr{http.StatusRequestEntityTooLarge, "request body too large"}
+	}
+	return httpRequestErr{http.StatusBadRequest, "unable to read body: " + err.Error()}
+}
+
+// resolve resolves a single query and streams the result to w
+func (h *HTTPHandler) resolve(w http.ResponseWriter, query []byte, opts ResolveOptions, responseContentType string) {
+	s := h.schemas.Get().(*Schema)
+	defer h.schemas.Put(s)
+
+	w.Header().Set("Content-Type", responseContentType+"; charset=utf-8")
+	if responseContentType == contentTypeJSON {
+		// With the legacy application/json content type invalid requests are also responded with 200
+		// so the result can be streamed
+		s.ResolveTo(&statusWriter{w: w, schema: s, responseContentType: responseContentType}, query, opts)
+		return
+	}
+
+	// Invalid queries and variables are found while resolving, the result is buffered so we know the status code
+	s.Resolve(query, opts)
+	w.WriteHeader(responseStatus(s.ctx, responseContentType))
+	if s.ctx.requestFailed {
+		// Invalid requests are responded without the data entry
+		w.Write([]byte{'{'})
+		w.Write(s.Result[s.ctx.errorsStart+1:])
+	} else {
+		w.Write(s.Result)
+	}
+}
+
+// resolveBatch resolves a list of queries and writes the results as a JSON array
+func (h *HTTPHandler) resolveBatch(w http.ResponseWriter, requests []*fastjson.Value, opts ResolveOptions, responseContentType string) {
+	s := h.schemas.Get().(*Schema)
+	defer h.schemas.Put(s)
+
+	response := []byte{'['}
+	for idx, request := range requests {
+		if idx > 0 {
+			response = append(response, ',')
+		}
+
+		query, operationName, variables, err := getBodyData(request)
+		if err != nil {
+			response = appendErrResponse(response, err.Error())
+			continue
+		}
+
+		opts.OperatorTarget = operationName
+		opts.Variables = variables
+		s.Resolve(s2b(query), opts)
+		response = append(response, s.Result...)
+	}
+	response = append(response, ']')
+
+	w.Header().Set("Content-Type", responseContentType+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
+
+func (h *HTTPHandler) writeRequestErr(w http.ResponseWriter, responseContentType string, err httpRequestErr) {
+	w.Header().Set("Content-Type", responseContentType+"; charset=utf-8")
+	w.WriteHeader(err.status)
+	w.Write(appendErrResponse(nil, err.message))
+}
+
+func appendErrResponse(response []byte, errorMsg string) []byte {
+	response = append(response, []byte(`{"errors":[{"message":`)...)
+	helpers.StringToJSON(errorMsg, &response)
+	return append(response, []byte(`}],"extensions":{}}`)...)
+}
+
+// statusWriter writes the response status code before the first write
+type statusWriter struct {
+	w                   http.ResponseWriter
+	schema              *Schema
+	responseContentType string
+	wroteHeader         bool
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.w.WriteHeader(w.status())
+	}
+	return w.w.Write(b)
+}
+
+func (w *statusWriter) Flush() {
+	flusher, ok := w.w.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+func (w *statusWriter) status() int {
+	return responseStatus(w.schema.ctx, w.responseContentType)
+}
+
+// responseStatus returns the status code of a resolved request
+func responseStatus(ctx *Ctx, responseContentType string) int {
+	if ctx.mutationRejected {
+		return http.StatusMethodNotAllowed
+	}
+	if ctx.requestFailed && responseContentType == contentTypeGraphQLResponseJSON {
+		// With the legacy application/json content type request errors are also responded with 200
+		return http.StatusBadRequest
+	}
+	return http.StatusOK
+}
+
+// negotiateResponseContentType returns the response content type based on the Accept header
+// ok is false if none of the supported content types are accepted
+func negotiateResponseContentType(accept string) (contentType string, ok bool) {
+	if strings.TrimSpace(accept) == "" {
+		return contentTypeJSON, true
+	}
+
+	var graphqlResponseQ, jsonQ, wildcardQ float64 = -1, -1, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if rawQ, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(rawQ, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		switch mediaType {
+		case contentTypeGraphQLResponseJSON:
+			graphqlResponseQ = q
+		case contentTypeJSON:
+			jsonQ = q
+		case "*/*", "application/*":
+			if wildcardQ < q {
+				wildcardQ = q
+			}
+		}
+	}
+	if graphqlResponseQ < 0 {
+		graphqlResponseQ = wildcardQ
+	}
+
+	if graphqlResponseQ < 0 && jsonQ < 0 {
+		return "", false
+	}
+	if jsonQ > graphqlResponseQ {
+		return contentTypeJSON, true
+	}
+	return contentTypeGraphQLResponseJSON, true
+}
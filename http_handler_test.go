@@ -0,0 +1,454 @@
+package yarql
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestHTTPHandlerQuery struct {
+	Foo string
+}
+
+func (TestHTTPHandlerQuery) ResolveHeader(ctx *Ctx, args struct{ Name string }) string {
+	return ctx.GetHeader(args.Name)
+}
+
+func (TestHTTPHandlerQuery) ResolveFail() (string, error) {
+	return "", errors.New("failed")
+}
+
+type TestHTTPHandlerMethods struct{}
+
+func (TestHTTPHandlerMethods) ResolveBar() string {
+	return "bar"
+}
+
+func newTestHTTPHandler(t *testing.T, options HTTPHandlerOptions) *HTTPHandler {
+	s := NewSchema()
+	err := s.Parse(TestHTTPHandlerQuery{Foo: "foo"}, TestHTTPHandlerMethods{}, nil)
+	a.NoError(t, err)
+	return NewHTTPHandler(s, options)
+}
+
+func doTestRequest(h http.Handler, method, target, contentType, accept, body string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	return res
+}
+
+func TestHTTPHandlerGet(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	res := doTestRequest(h, "GET", "/graphql?query="+url.QueryEscape("{foo}"), "", "", "")
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+	a.Equal(t, `{"data":{"foo":"foo"}}`, res.Body.String())
+
+	res = doTestRequest(h, "GET", "/graphql", "", "", "")
+	a.Equal(t, http.StatusBadRequest, res.Code)
+
+	res = doTestRequest(h, "GET", "/graphql?query="+url.QueryEscape("{foo}")+"&variables=1", "", "", "")
+	a.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestHTTPHandlerRejectMutationOverGet(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	res := doTestRequest(h, "GET", "/graphql?query="+url.QueryEscape("mutation {bar}"), "", "", "")
+	a.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	a.True(t, strings.Contains(res.Body.String(), "mutations can only be executed using a POST request"))
+
+	res = doTestRequest(h, "POST", "/graphql", "application/json", "", `{"query":"mutation {bar}"}`)
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, `{"data":{"bar":"bar"}}`, res.Body.String())
+}
+
+func TestHTTPHandlerMethodNotAllowed(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	res := doTestRequest(h, "PUT", "/graphql", "application/json", "", `{"query":"{foo}"}`)
+	a.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	a.Equal(t, "GET, POST", res.Header().Get("Allow"))
+}
+
+func TestHTTPHandlerPost(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	res := doTestRequest(h, "POST", "/graphql", "application/json; charset=utf-8", "", `{"query":"query A {a: foo} query B {b: foo}","operationName":"B"}`)
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, `{"data":{"b":"foo"}}`, res.Body.String())
+
+	res = doTestRequest(h, "POST", "/graphql", "application/graphql", "", `{foo}`)
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, `{"data":{"foo":"foo"}}`, res.Body.String())
+
+	res = doTestRequest(h, "POST", "/graphql", "application/xml", "", `<query>foo</query>`)
+	a.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+
+	res = doTestRequest(h, "POST", "/graphql", "application/json", "", `{"query":`)
+	a.Equal(t, http.StatusBadRequest, res.Code)
+
+	res = doTestRequest(h, "POST", "/graphql", "application/json", "", `{"variables":{}}`)
+	a.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestHTTPHandlerRejectPlainText(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	// Browsers send text/plain requests cross-site without a preflight, accepting them would allow CSRF
+	res := doTestRequest(h, "POST", "/graphql", "text/plain", "", `{"query":"mutation {bar}"}`)
+	a.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+	a.False(t, strings.Contains(res.Body.String(), `"bar"`), res.Body.String())
+}
+
+func TestHTTPHandlerBatch(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	res := doTestRequest(h, "POST", "/graphql", "application/json", "", `[{"query":"{foo}"},{"query":"mutation {bar}"}]`)
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, `[{"data":{"foo":"foo"}},{"data":{"bar":"bar"}}]`, res.Body.String())
+}
+
+func TestHTTPHandlerContentNegotiation(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+	validQuery := "/graphql?query=" + url.QueryEscape("{foo}")
+	invalidQuery := "/graphql?query=" + url.QueryEscape("{foo")
+
+	res := doTestRequest(h, "GET", validQuery, "", "application/graphql-response+json", "")
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, "application/graphql-response+json; charset=utf-8", res.Header().Get("Content-Type"))
+
+	res = doTestRequest(h, "GET", validQuery, "", "application/json;q=0.9, application/graphql-response+json", "")
+	a.Equal(t, "application/graphql-response+json; charset=utf-8", res.Header().Get("Content-Type"))
+
+	res = doTestRequest(h, "GET", validQuery, "", "application/json, application/graphql-response+json;q=0.5", "")
+	a.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+
+	res = doTestRequest(h, "GET", validQuery, "", "*/*", "")
+	a.Equal(t, "application/graphql-response+json; charset=utf-8", res.Header().Get("Content-Type"))
+
+	res = doTestRequest(h, "GET", validQuery, "", "text/html", "")
+	a.Equal(t, http.StatusNotAcceptable, res.Code)
+
+	// Request errors result in a 400 with application/graphql-response+json and a 200 with application/json
+	res = doTestRequest(h, "GET", invalidQuery, "", "application/graphql-response+json", "")
+	a.Equal(t, http.StatusBadRequest, res.Code)
+	res = doTestRequest(h, "GET", invalidQuery, "", "application/json", "")
+	a.Equal(t, http.StatusOK, res.Code)
+
+	// Field errors are not request errors
+	res = doTestRequest(h, "GET", "/graphql?query="+url.QueryEscape("{foo fail}"), "", "application/graphql-response+json", "")
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, `{"data":{"foo":"foo","fail":""},"errors":[{"message":"failed","path":["fail"]}],"extensions":{}}`, res.Body.String())
+}
+
+func TestHTTPHandlerValidationErrors(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	testCases := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"unknown field", `{"query":"{foo doesNotExist}"}`, `{"message":"doesNotExist does not exists on TestHTTPHandlerQuery","path":["doesNotExist"]}`},
+		{"invalid variable", `{"query":"query ($name: String!) {header(name: $name)}","variables":{"name":1}}`, `{"message":"cannot assign number to string","path":["header"]}`},
+		{"missing variable", `{"query":"query ($name: String!) {header(name: $name)}"}`, `{"message":"variable has no value nor default","path":["header"]}`},
+	}
+	for _, testCase := range testCases {
+		res := doTestRequest(h, "POST", "/graphql", "application/json", "application/graphql-response+json", testCase.body)
+		a.Equal(t, http.StatusBadRequest, res.Code, testCase.name)
+		a.Equal(t, `{"errors":[`+testCase.err+`],"extensions":{}}`, res.Body.String(), testCase.name)
+
+		// The legacy application/json content type responds with 200 and the partial data
+		res = doTestRequest(h, "POST", "/graphql", "application/json", "application/json", testCase.body)
+		a.Equal(t, http.StatusOK, res.Code, testCase.name)
+		a.True(t, strings.HasPrefix(res.Body.String(), `{"data":`), testCase.name)
+	}
+}
+
+func TestHTTPHandlerMaxBodySize(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{MaxBodySize: 20})
+
+	res := doTestRequest(h, "POST", "/graphql", "application/json", "", `{"query":"{foo}"}`)
+	a.Equal(t, http.StatusOK, res.Code)
+
+	res = doTestRequest(h, "POST", "/graphql", "application/json", "", `{"query":"{foo foo foo foo}"}`)
+	a.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+}
+
+func TestHTTPHandlerHeaders(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":"{header(name: \"X-Test\")}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test", "some value")
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, `{"data":{"header":"some value"}}`, res.Body.String())
+}
+
+func TestHTTPHandlerIntrospectionAndVisibility(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{
+		DisableIntrospection: func(r *http.Request) bool {
+			return r.Header.Get("X-Staff") == ""
+		},
+		Visibility: func(r *http.Request) VisibilityFunc {
+			if r.Header.Get("X-Staff") != "" {
+				return nil
+			}
+			return func(typeName, fieldName string) bool {
+				return fieldName != "header"
+			}
+		},
+	})
+
+	doRequest := func(query string, staff bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(query), nil)
+		if staff {
+			req.Header.Set("X-Staff", "true")
+		}
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		return res
+	}
+
+	res := doRequest("{__schema {queryType {name}}}", false)
+	a.True(t, strings.Contains(res.Body.String(), "introspection is disabled"), res.Body.String())
+	res = doRequest("{__schema {queryType {name}}}", true)
+	a.Equal(t, `{"data":{"__schema":{"queryType":{"name":"TestHTTPHandlerQuery"}}}}`, res.Body.String())
+
+	res = doRequest(`{header(name: "X-Staff")}`, false)
+	a.True(t, strings.Contains(res.Body.String(), "header does not exists on TestHTTPHandlerQuery"), res.Body.String())
+	res = doRequest(`{header(name: "X-Staff")}`, true)
+	a.Equal(t, `{"data":{"header":"true"}}`, res.Body.String())
+}
+
+func TestHTTPHandlerConcurrentRequests(t *testing.T) {
+	h := newTestHTTPHandler(t, HTTPHandlerOptions{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				res := doTestRequest(h, "POST", "/graphql", "application/json", "", `{"query":"{foo}"}`)
+				a.Equal(t, `{"data":{"foo":"foo"}}`, res.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type TestHTTPHandlerUploadMethods struct{}
+
+type TestHTTPHandlerUploadInput struct {
+	Name string
+	File *multipart.FileHeader
+}
+
+func readTestUpload(file *multipart.FileHeader) string {
+	if file == nil {
+		return ""
+	}
+	f, err := file.Open()
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	contents, err := ioutil.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+	return string(contents)
+}
+
+func (TestHTTPHandlerUploadMethods) ResolveUpload(args struct{ File *multipart.FileHeader }) string {
+	return readTestUpload(args.File)
+}
+
+func (TestHTTPHandlerUploadMethods) ResolveUploadMany(args struct{ Files []*multipart.FileHeader }) []string {
+	res := []string{}
+	for _, file := range args.Files {
+		res = append(res, readTestUpload(file))
+	}
+	return res
+}
+
+func (TestHTTPHandlerUploadMethods) ResolveUploadNested(args struct{ Input TestHTTPHandlerUploadInput }) string {
+	return args.Input.Name + ": " + readTestUpload(args.Input.File)
+}
+
+func newTestUploadRequest(operations, fileMap string, files map[string]string) *http.Request {
+	body := bytes.NewBuffer(nil)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("operations", operations)
+	if fileMap != "" {
+		writer.WriteField("map", fileMap)
+	}
+	for key, contents := range files {
+		fileWriter, _ := writer.CreateFormFile(key, key+".txt")
+		fileWriter.Write([]byte(contents))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/graphql", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Apollo-Require-Preflight", "true")
+	return req
+}
+
+func newTestUploadHandler(t *testing.T, options HTTPHandlerOptions) *HTTPHandler {
+	s := NewSchema()
+	err := s.Parse(TestHTTPHandlerQuery{}, TestHTTPHandlerUploadMethods{}, nil)
+	a.NoError(t, err)
+	return NewHTTPHandler(s, options)
+}
+
+func TestHTTPHandlerMultipartUpload(t *testing.T) {
+	h := newTestUploadHandler(t, HTTPHandlerOptions{})
+
+	testCases := []struct {
+		name       string
+		operations string
+		fileMap    string
+		files      map[string]string
+		expected   string
+	}{
+		{
+			name:       "single file",
+			operations: `{"query":"mutation ($file: Upload!) {upload(file: $file)}","variables":{"file":null}}`,
+			fileMap:    `{"0":["variables.file"]}`,
+			files:      map[string]string{"0": "hello world"},
+			expected:   `{"data":{"upload":"hello world"}}`,
+		},
+		{
+			name:       "file list",
+			operations: `{"query":"mutation ($files: [Upload!]!) {uploadMany(files: $files)}","variables":{"files":[null,null]}}`,
+			fileMap:    `{"0":["variables.files.0"],"1":["variables.files.1"]}`,
+			files:      map[string]string{"0": "a", "1": "b"},
+			expected:   `{"data":{"uploadMany":["a","b"]}}`,
+		},
+		{
+			name:       "nested input",
+			operations: `{"query":"mutation ($input: TestHTTPHandlerUploadInput!) {uploadNested(input: $input)}","variables":{"input":{"name":"foo","file":null}}}`,
+			fileMap:    `{"0":["variables.input.file"]}`,
+			files:      map[string]string{"0": "bar"},
+			expected:   `{"data":{"uploadNested":"foo: bar"}}`,
+		},
+		{
+			name:       "batched operations",
+			operations: `[{"query":"mutation ($file: Upload!) {upload(file: $file)}","variables":{"file":null}},{"query":"mutation ($file: Upload!) {upload(file: $file)}","variables":{"file":null}}]`,
+			fileMap:    `{"0":["0.variables.file"],"1":["1.variables.file"]}`,
+			files:      map[string]string{"0": "first", "1": "second"},
+			expected:   `[{"data":{"upload":"first"}},{"data":{"upload":"second"}}]`,
+		},
+		{
+			name:       "legacy form field name",
+			operations: `{"query":"mutation {upload(file: \"some_file\")}"}`,
+			files:      map[string]string{"some_file": "legacy"},
+			expected:   `{"data":{"upload":"legacy"}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		req := newTestUploadRequest(testCase.operations, testCase.fileMap, testCase.files)
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		a.Equal(t, http.StatusOK, res.Code, testCase.name)
+		a.Equal(t, testCase.expected, res.Body.String(), testCase.name)
+	}
+}
+
+func TestHTTPHandlerMultipartPreflight(t *testing.T) {
+	operations := `{"query":"mutation ($file: Upload!) {upload(file: $file)}","variables":{"file":null}}`
+	fileMap := `{"0":["variables.file"]}`
+	files := map[string]string{"0": "a"}
+
+	// A cross-site form can't set custom headers
+	h := newTestUploadHandler(t, HTTPHandlerOptions{})
+	req := newTestUploadRequest(operations, fileMap, files)
+	req.Header.Del("Apollo-Require-Preflight")
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	a.Equal(t, http.StatusBadRequest, res.Code)
+	a.False(t, strings.Contains(res.Body.String(), `"upload"`), res.Body.String())
+
+	req = newTestUploadRequest(operations, fileMap, files)
+	req.Header.Del("Apollo-Require-Preflight")
+	req.Header.Set("X-Apollo-Operation-Name", "upload")
+	res = httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	a.Equal(t, http.StatusOK, res.Code)
+
+	h = newTestUploadHandler(t, HTTPHandlerOptions{AllowMultipartWithoutPreflight: true})
+	req = newTestUploadRequest(operations, fileMap, files)
+	req.Header.Del("Apollo-Require-Preflight")
+	res = httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, `{"data":{"upload":"a"}}`, res.Body.String())
+}
+
+func TestHTTPHandlerMultipartInvalidMap(t *testing.T) {
+	h := newTestUploadHandler(t, HTTPHandlerOptions{})
+
+	invalidMaps := []string{
+		`not json`,
+		`["variables.file"]`,
+		`{"0":"variables.file"}`,
+		`{"0":["variables.doesNotExist"]}`,
+		`{"0":["variables.file.0"]}`,
+		`{"0":["query"]}`,
+	}
+	for _, fileMap := range invalidMaps {
+		req := newTestUploadRequest(`{"query":"mutation ($file: Upload!) {upload(file: $file)}","variables":{"file":null}}`, fileMap, map[string]string{"0": "a"})
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		a.Equal(t, http.StatusBadRequest, res.Code, fileMap)
+	}
+}
+
+func TestHTTPHandlerMultipartLimits(t *testing.T) {
+	operations := `{"query":"mutation ($files: [Upload!]!) {uploadMany(files: $files)}","variables":{"files":[null,null]}}`
+	fileMap := `{"0":["variables.files.0"],"1":["variables.files.1"]}`
+
+	h := newTestUploadHandler(t, HTTPHandlerOptions{MaxFiles: 1})
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, newTestUploadRequest(operations, fileMap, map[string]string{"0": "a", "1": "b"}))
+	a.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+
+	h = newTestUploadHandler(t, HTTPHandlerOptions{MaxFileSize: 5})
+	res = httptest.NewRecorder()
+	h.ServeHTTP(res, newTestUploadRequest(operations, fileMap, map[string]string{"0": "a", "1": "more than 5 bytes"}))
+	a.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+
+	res = httptest.NewRecorder()
+	h.ServeHTTP(res, newTestUploadRequest(operations, fileMap, map[string]string{"0": "a", "1": "b"}))
+	a.Equal(t, http.StatusOK, res.Code)
+	a.Equal(t, `{"data":{"uploadMany":["a","b"]}}`, res.Body.String())
+}
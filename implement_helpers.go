@@ -5,7 +5,10 @@ import (
 	"context"
 	"errors"
 	"mime/multipart"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mjarkk/yarql/helpers"
 	"github.com/valyala/fastjson"
@@ -17,6 +20,10 @@ type RequestOptions struct {
 	Values      map[string]interface{}                          // Passed directly to the request context
 	GetFormFile func(key string) (*multipart.FileHeader, error) // Get form file to support file uploading
 	Tracing     bool                                            // https://github.com/apollographql/apollo-tracing
+	Tracer      Tracer                                          // Receives the tracing events of the request, see Tracer
+	Metrics     Metrics                                         // Collects metrics about the request, see Metrics
+	Timeout     time.Duration                                   // Max duration of a single operation, 0 = no timeout
+	Headers     http.Header                                     // Request headers, resolvers can read them using (*Ctx).GetHeader
 }
 
 // HandleRequest handles a http request and returns a response
@@ -57,6 +64,16 @@ func (s *Schema) HandleRequest(
 		if err != nil {
 			return errRes("invalid json body")
 		}
+		if contentType == "multipart/form-data" {
+			// The map field is only set by clients following the graphql-multipart-request-spec
+			fileMap, err := getFormField("map")
+			if err == nil && len(fileMap) > 0 {
+				err = applyMultipartMap(v, fileMap)
+				if err != nil {
+					return errRes(err.Error())
+				}
+			}
+		}
 		if v.Type() == fastjson.TypeArray {
 			// Handle batch query
 			responseErrs := []error{}
@@ -134,11 +151,104 @@ func (s *Schema) handleSingleRequest(
 			resolveOptions.GetFormFile = options.GetFormFile
 		}
 		resolveOptions.Tracing = options.Tracing
+		resolveOptions.Tracer = options.Tracer
+		resolveOptions.Metrics = options.Metrics
+		resolveOptions.Timeout = options.Timeout
+		resolveOptions.Headers = options.Headers
 	}
 
 	return s.Resolve(s2b(query), resolveOptions)
 }
 
+// applyMultipartMap applies the map form field of the graphql-multipart-request-spec to the operations
+// https://github.com/jaydenseric/graphql-multipart-request-spec
+//
+// Every file placeholder (a null value) referenced in the map is replaced with the form field name of the file,
+// because of this the binding of files works equal to clients that directly define the form field name as value
+func applyMultipartMap(operations *fastjson.Value, fileMap string) error {
+	var p fastjson.Parser
+	parsedMap, err := p.Parse(fileMap)
+	if err != nil {
+		return errors.New("invalid json in map form field")
+	}
+	mapObj, err := parsedMap.Object()
+	if err != nil {
+		return errors.New("expected map form field to be a key value object")
+	}
+
+	var arena fastjson.Arena
+	mapObj.Visit(func(fieldName []byte, paths *fastjson.Value) {
+		if err != nil {
+			return
+		}
+
+		pathsList, pathsErr := paths.Array()
+		if pathsErr != nil {
+			err = errors.New("expected map form field values to be a list of object paths")
+			return
+		}
+
+		for _, path := range pathsList {
+			pathBytes, pathErr := path.StringBytes()
+			if pathErr != nil {
+				err = errors.New("expected map form field values to be a list of object paths")
+				return
+			}
+			err = setMultipartPlaceholder(operations, string(pathBytes), arena.NewString(string(fieldName)))
+			if err != nil {
+				return
+			}
+		}
+	})
+	return err
+}
+
+// setMultipartPlaceholder replaces the null value at the object path with value
+// An object path looks like: variables.files.0 or for batched operations 1.variables.file
+func setMultipartPlaceholder(operations *fastjson.Value, path string, value *fastjson.Value) error {
+	invalidPathErr := errors.New("invalid map form field object path " + path)
+
+	parts := strings.Split(path, ".")
+	current := operations
+	for idx, part := range parts {
+		isLast := idx == len(parts)-1
+
+		switch current.Type() {
+		case fastjson.TypeObject:
+			next := current.Get(part)
+			if next == nil {
+				return invalidPathErr
+			}
+			if isLast {
+				if next.Type() != fastjson.TypeNull {
+					return errors.New("expected file placeholder at " + path + " to be null")
+				}
+				current.Set(part, value)
+				return nil
+			}
+			current = next
+		case fastjson.TypeArray:
+			itemIdx, err := strconv.Atoi(part)
+			items := current.GetArray()
+			if err != nil || itemIdx < 0 || itemIdx >= len(items) {
+				return invalidPathErr
+			}
+			if isLast {
+				if items[itemIdx].Type() != fastjson.TypeNull {
+					return errors.New("expected file placeholder at " + path + " to be null")
+				}
+				current.SetArrayItem(itemIdx, value)
+				return nil
+			}
+			current = items[itemIdx]
+		default:
+			return invalidPathErr
+		}
+	}
+
+	return invalidPathErr
+}
+
 func getBodyData(body *fastjson.Value) (query, operationName, variables string, err error) {
 	if body.Type() != fastjson.TypeObject {
 		err = errors.New("body should be a object")
@@ -1,7 +1,9 @@
 package yarql
 
 import (
+	"bytes"
 	"errors"
+	"mime/multipart"
 	"strings"
 	"testing"
 
@@ -164,3 +166,59 @@ func TestHandleRequestRequestBatch(t *testing.T) {
 	}
 	a.Equal(t, `[{"data":{"a":{"bar":"baz"}}},{"data":{"a":{"foo":null}}}]`, string(res))
 }
+
+func TestHandleRequestRequestFormWithFileMap(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestResolveWithFileData{}, M{}, nil)
+	a.NoError(t, err)
+
+	fileHeader := createTestFormFile("hello world")
+
+	requestedFiles := []string{}
+	res, errs := s.HandleRequest(
+		"POST",
+		func(key string) string { return "" },
+		func(key string) (string, error) {
+			switch key {
+			case "operations":
+				return `{"query": "query ($file: Upload) {foo(file: $file)}", "variables": {"file": null}}`, nil
+			case "map":
+				return `{"0": ["variables.file"]}`, nil
+			}
+			return "", errors.New("unknown form field")
+		},
+		func() []byte { return nil },
+		"multipart/form-data",
+		&RequestOptions{
+			GetFormFile: func(key string) (*multipart.FileHeader, error) {
+				requestedFiles = append(requestedFiles, key)
+				return fileHeader, nil
+			},
+		},
+	)
+	for _, err := range errs {
+		panic(err)
+	}
+	a.Equal(t, `{"data":{"foo":"hello world"}}`, string(res))
+	a.Equal(t, []string{"0"}, requestedFiles)
+}
+
+func createTestFormFile(contents string) *multipart.FileHeader {
+	buf := bytes.NewBuffer(nil)
+	multiPartWriter := multipart.NewWriter(buf)
+	writer, err := multiPartWriter.CreateFormFile("file", "test.txt")
+	if err != nil {
+		panic(err)
+	}
+	writer.Write([]byte(contents))
+	err = multiPartWriter.Close()
+	if err != nil {
+		panic(err)
+	}
+
+	form, err := multipart.NewReader(buf, multiPartWriter.Boundary()).ReadForm(1024 * 1024)
+	if err != nil {
+		panic(err)
+	}
+	return form.File["file"][0]
+}
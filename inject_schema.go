@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"sort"
 
+	"github.com/mjarkk/yarql/bytecode"
 	h "github.com/mjarkk/yarql/helpers"
 )
 
@@ -24,7 +25,7 @@ func (s *Schema) injectQLTypes(ctx *parseCtx) {
 
 	// Inject __type(name: String!): __Type
 	typeResolver := func(ctx *Ctx, args struct{ Name string }) *qlType {
-		return ctx.schema.getTypeByName(args.Name)
+		return ctx.schema.getVisibleTypeByName(args.Name)
 	}
 	typeResolverReflection := reflect.ValueOf(typeResolver)
 	functionObj, err := ctx.checkStructFieldFunc("__type", typeResolverReflection.Type(), false, -1)
@@ -40,61 +41,23 @@ func (s *Schema) injectQLTypes(ctx *parseCtx) {
 
 func (s *Schema) getQLSchema() qlSchema {
 	res := qlSchema{
-		Types:      s.getAllQLTypes,
-		Directives: s.getDirectives(),
+		Types:      s.getVisibleQLTypes,
+		Directives: s.getDirectives,
 		QueryType: &qlType{
 			Kind:        typeKindObject,
-			Name:        h.StrPtr(s.rootQuery.typeName),
-			Description: h.PtrToEmptyStr,
-			Fields: func(isDeprecatedArgs) []qlField {
-				fields, ok := s.graphqlObjFields[s.rootQuery.typeName]
-				if ok {
-					return fields
-				}
-
-				res := []qlField{}
-				for _, item := range s.rootQuery.objContents {
-					if item.hidden {
-						continue
-					}
-					res = append(res, qlField{
-						Name: string(item.qlFieldName),
-						Args: s.getObjectArgs(item),
-						Type: *wrapQLTypeInNonNull(s.objToQLType(item)),
-					})
-				}
-				sort.Slice(res, func(a int, b int) bool { return res[a].Name < res[b].Name })
-
-				s.graphqlObjFields[s.rootQuery.typeName] = res
-				return res
+			Name:        &s.rootQuery.typeName,
+			Description: &s.rootQuery.description,
+			Fields: func(args isDeprecatedArgs) []qlField {
+				return s.getObjFields(s.rootQuery, args)
 			},
 			Interfaces: []qlType{},
 		},
 		MutationType: &qlType{
 			Kind:        typeKindObject,
-			Name:        h.StrPtr(s.rootMethod.typeName),
-			Description: h.PtrToEmptyStr,
-			Fields: func(isDeprecatedArgs) []qlField {
-				fields, ok := s.graphqlObjFields[s.rootMethod.typeName]
-				if ok {
-					return fields
-				}
-
-				res := []qlField{}
-				for _, item := range s.rootMethod.objContents {
-					if item.hidden {
-						continue
-					}
-					res = append(res, qlField{
-						Name: string(item.qlFieldName),
-						Args: s.getObjectArgs(item),
-						Type: *wrapQLTypeInNonNull(s.objToQLType(item)),
-					})
-				}
-				sort.Slice(res, func(a int, b int) bool { return res[a].Name < res[b].Name })
-
-				s.graphqlObjFields[s.rootQuery.typeName] = res
-				return res
+			Name:        &s.rootMethod.typeName,
+			Description: &s.rootMethod.description,
+			Fields: func(args isDeprecatedArgs) []qlField {
+				return s.getObjFields(s.rootMethod, args)
 			},
 			Interfaces: []qlType{},
 		},
@@ -148,8 +111,14 @@ func (s *Schema) getAllQLTypes() []qlType {
 
 		s.graphqlTypesList = make(
 			[]qlType,
-			len(s.types)+len(s.inTypes)+len(s.definedEnums)+len(scalars)+len(s.interfaces),
+			len(s.types)+len(s.inTypes)+len(s.definedEnums)+len(scalars)+len(s.definedScalars)+len(s.interfaces),
 		)
+		if s.federation != nil {
+			s.graphqlTypesList = append(s.graphqlTypesList, scalarAny)
+		}
+		if s.usesJSON {
+			s.graphqlTypesList = append(s.graphqlTypesList, scalarJSON)
+		}
 
 		idx := 0
 		for _, qlType := range s.types {
@@ -170,6 +139,10 @@ func (s *Schema) getAllQLTypes() []qlType {
 			s.graphqlTypesList[idx] = scalar
 			idx++
 		}
+		for _, scalar := range s.definedScalars {
+			s.graphqlTypesList[idx] = scalar
+			idx++
+		}
 		for _, qlInterface := range s.interfaces {
 			obj, _ := s.objToQLType(qlInterface)
 			s.graphqlTypesList[idx] = *obj
@@ -209,8 +182,36 @@ func wrapQLTypeInNonNull(t *qlType, isNonNull bool) *qlType {
 	}
 }
 
+// withSDLType replaces the list and non null wrappers of goType with the ones defined in the SDL
+// The named type is equal in the SDL and go as this is checked by ParseWithSDL
+func withSDLType(goType *qlType, sdlType *bytecode.TypeReference) *qlType {
+	if sdlType == nil {
+		return goType
+	}
+
+	named := goType
+	for named.OfType != nil && (named.Kind == typeKindList || named.Kind == typeKindNonNull) {
+		named = named.OfType
+	}
+
+	var res *qlType
+	if sdlType.List != nil {
+		res = &qlType{
+			Kind:   typeKindList,
+			OfType: withSDLType(named, sdlType.List),
+		}
+	} else {
+		res = named
+	}
+	return wrapQLTypeInNonNull(res, sdlType.NonNull)
+}
+
 func (s *Schema) inputToQLType(in *input) (res *qlType, isNonNull bool) {
-	if in.isID {
+	if in.isOptional {
+		// Basically sets the isNonNull to false
+		res, _ = s.inputToQLType(in.elem)
+		return
+	} else if in.isID {
 		isNonNull = true
 		res = &scalarID
 		return
@@ -221,25 +222,51 @@ func (s *Schema) inputToQLType(in *input) (res *qlType, isNonNull bool) {
 	} else if in.isFile {
 		res = &scalarFile
 		return
+	} else if in.isEnum {
+		enumType := s.definedEnums[in.enumTypeIndex].qlType
+		return &enumType, true
+	} else if in.isAny {
+		isNonNull = true
+		res = &scalarAny
+		return
+	} else if in.isJSON {
+		res = &scalarJSON
+		return
+	} else if in.isText {
+		return s.textScalarQLType(in.scalarName), true
+	} else if in.isLong {
+		return s.longQLType(), true
+	} else if in.isBase64 {
+		res = &scalarString
+		isNonNull = in.kind == reflect.Array
+		return
 	}
 
 	switch in.kind {
 	case reflect.Struct:
 		isNonNull = true
 
+		description := ""
+		isOneOf := false
+		if inType, ok := s.inTypes[in.structName]; ok {
+			description = inType.description
+			isOneOf = inType.isOneOf
+		}
+
 		res = &qlType{
 			Kind:        typeKindInputObject,
 			Name:        h.StrPtr(in.structName),
-			Description: h.PtrToEmptyStr,
+			Description: &description,
+			IsOneOf:     &isOneOf,
 			InputFields: func() []qlInputValue {
 				res := make([]qlInputValue, len(in.structContent))
 				i := 0
 				for key, item := range in.structContent {
 					res[i] = qlInputValue{
 						Name:         key,
-						Description:  h.PtrToEmptyStr,
-						Type:         *wrapQLTypeInNonNull(s.inputToQLType(&item)),
-						DefaultValue: nil, // We do not support this atm
+						Description:  h.StrPtr(item.description),
+						Type:         *withSDLType(wrapQLTypeInNonNull(s.inputToQLType(&item)), item.sdlType),
+						DefaultValue: item.defaultValue,
 					}
 					i++
 				}
@@ -247,7 +274,7 @@ func (s *Schema) inputToQLType(in *input) (res *qlType, isNonNull bool) {
 				return res
 			},
 		}
-	case reflect.Array, reflect.Slice:
+	case reflect.Array, reflect.Slice, reflect.Map:
 		res = &qlType{
 			Kind:   typeKindList,
 			OfType: wrapQLTypeInNonNull(s.inputToQLType(in.elem)),
@@ -294,9 +321,9 @@ func (s *Schema) getMethodArgs(inputs map[string]referToInput) []qlInputValue {
 	for key, value := range inputs {
 		res = append(res, qlInputValue{
 			Name:         key,
-			Description:  h.PtrToEmptyStr,
-			Type:         *wrapQLTypeInNonNull(s.inputToQLType(&value.input)),
-			DefaultValue: nil,
+			Description:  h.StrPtr(value.input.description),
+			Type:         *withSDLType(wrapQLTypeInNonNull(s.inputToQLType(&value.input)), value.input.sdlType),
+			DefaultValue: value.input.defaultValue,
 		})
 	}
 	sort.Slice(res, func(a int, b int) bool { return res[a].Name < res[b].Name })
@@ -330,28 +357,9 @@ func (s *Schema) objToQLType(item *obj) (res *qlType, isNonNull bool) {
 		res = &qlType{
 			Kind:        typeKindObject,
 			Name:        &item.typeName,
-			Description: h.PtrToEmptyStr,
+			Description: &item.description,
 			Fields: func(args isDeprecatedArgs) []qlField {
-				fields, ok := s.graphqlObjFields[item.typeName]
-				if ok {
-					return fields
-				}
-
-				res := []qlField{}
-				for _, innerItem := range item.objContents {
-					if innerItem.hidden {
-						continue
-					}
-					res = append(res, qlField{
-						Name: string(innerItem.qlFieldName),
-						Args: s.getObjectArgs(innerItem),
-						Type: *wrapQLTypeInNonNull(s.objToQLType(innerItem)),
-					})
-				}
-				sort.Slice(res, func(a int, b int) bool { return res[a].Name < res[b].Name })
-
-				s.graphqlObjFields[item.typeName] = res
-				return res
+				return s.getObjFields(item, args)
 			},
 			Interfaces: interfaces,
 		}
@@ -360,6 +368,14 @@ func (s *Schema) objToQLType(item *obj) (res *qlType, isNonNull bool) {
 		enumType := s.definedEnums[item.enumTypeIndex].qlType
 		res = &enumType
 		return res, true
+	case valueTypeJSON:
+		res = &scalarJSON
+		return res, false
+	case valueTypeText:
+		return s.textScalarQLType(item.scalarName), true
+	case valueTypeBase64:
+		res = &scalarString
+		return res, item.dataValueType == reflect.Array
 	case valueTypePtr:
 		// This basically sets the isNonNull to false
 		res, _ := s.objToQLType(item.innerContent)
@@ -376,48 +392,98 @@ func (s *Schema) objToQLType(item *obj) (res *qlType, isNonNull bool) {
 		// A interface should be non null BUT as a interface in go can be nil we set it to false
 		isNonNull = false
 
-		res = &qlType{
-			Kind:        typeKindInterface,
-			Name:        &item.typeName,
-			Description: h.PtrToEmptyStr,
-			Interfaces:  []qlType{},
-			PossibleTypes: func() []qlType {
-				possibleTypes := make([]qlType, len(item.implementations))
-				for idx, implementation := range item.implementations {
-					item, _ := s.objToQLType(implementation)
-					possibleTypes[idx] = *item
-				}
-				return possibleTypes
-			},
-			Fields: func(args isDeprecatedArgs) []qlField {
-				fields, ok := s.graphqlObjFields[item.typeName]
-				if ok {
-					return fields
-				}
+		possibleTypes := func() []qlType {
+			possibleTypes := make([]qlType, len(item.implementations))
+			for idx, implementation := range item.implementations {
+				item, _ := s.objToQLType(implementation)
+				possibleTypes[idx] = *item
+			}
+			return possibleTypes
+		}
 
-				res := []qlField{}
-				for _, innerItem := range item.objContents {
-					if item.hidden {
-						continue
-					}
-					res = append(res, qlField{
-						Name: string(innerItem.qlFieldName),
-						Args: s.getObjectArgs(innerItem),
-						Type: *wrapQLTypeInNonNull(s.objToQLType(innerItem)),
-					})
-				}
-				sort.Slice(res, func(a int, b int) bool { return res[a].Name < res[b].Name })
+		if item.isUnion {
+			res = &qlType{
+				Kind:          typeKindUnion,
+				Name:          &item.typeName,
+				Description:   &item.description,
+				PossibleTypes: possibleTypes,
+			}
+			return
+		}
 
-				s.graphqlObjFields[item.typeName] = res
-				return res
+		interfaces := []qlType{}
+		for _, parent := range item.interfaces {
+			interfaceType, _ := s.objToQLType(parent)
+			interfaces = append(interfaces, *interfaceType)
+		}
+
+		res = &qlType{
+			Kind:          typeKindInterface,
+			Name:          &item.typeName,
+			Description:   &item.description,
+			Interfaces:    interfaces,
+			PossibleTypes: possibleTypes,
+			Fields: func(args isDeprecatedArgs) []qlField {
+				return s.getObjFields(item, args)
 			},
 		}
 		return
 	default:
+		if item.isLong {
+			return s.longQLType(), true
+		}
 		return resolveObjToScalar(item), true
 	}
 }
 
+// getObjFields returns the fields of a object or interface
+// Deprecated fields are only included if args.IncludeDeprecated is set and fields hidden by the visibility of the current request are excluded
+func (s *Schema) getObjFields(item *obj, args isDeprecatedArgs) []qlField {
+	fields, ok := s.graphqlObjFields[item.typeName]
+	if !ok {
+		fields = []qlField{}
+		for _, innerItem := range item.objContents {
+			if innerItem.hidden {
+				continue
+			}
+			fields = append(fields, qlField{
+				Name:              string(innerItem.qlFieldName),
+				Description:       &innerItem.description,
+				Args:              s.getObjectArgs(innerItem),
+				Type:              *withSDLType(wrapQLTypeInNonNull(s.objToQLType(innerItem)), innerItem.sdlType),
+				IsDeprecated:      innerItem.deprecationReason != nil,
+				DeprecationReason: innerItem.deprecationReason,
+			})
+		}
+		sort.Slice(fields, func(a int, b int) bool { return fields[a].Name < fields[b].Name })
+
+		s.graphqlObjFields[item.typeName] = fields
+	}
+
+	if s.ctx.visibility != nil {
+		fields = s.visibleQLFields(item.typeName, fields)
+	}
+
+	if args.IncludeDeprecated {
+		return fields
+	}
+	for idx, field := range fields {
+		if !field.IsDeprecated {
+			continue
+		}
+
+		// Only allocate a new list if there are deprecated fields
+		res := append([]qlField{}, fields[:idx]...)
+		for _, field := range fields[idx+1:] {
+			if !field.IsDeprecated {
+				res = append(res, field)
+			}
+		}
+		return res
+	}
+	return fields
+}
+
 func resolveObjToScalar(item *obj) *qlType {
 	var res qlType
 	switch item.valueType {
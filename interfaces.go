@@ -1,73 +1,204 @@
 package yarql
 
 import (
+	"bytes"
+	"errors"
 	"reflect"
+	"sort"
 )
 
-// implementationMap is a map of interface names and the types that implement them
-var implementationMap = map[string][]reflect.Type{}
-
-// structImplementsMap is list of all structs and their interfaces that they implement
-var structImplementsMap = map[string][]reflect.Type{}
+// ResolveTypeFunc is called for interface values that can't be matched by go type to one of the types implementing the interface
+// The interfaceName is the graphql name of the interface, the returned value must be one of the types implementing the interface
+// Returning nil resolves the value as null
+//
+// Example:
+//   func(interfaceName string, value interface{}) interface{} {
+//     if user, ok := value.(*db.User); ok {
+//       return User{ID: user.ID, Name: user.Name}
+//     }
+//     return nil
+//   }
+type ResolveTypeFunc func(interfaceName string, value interface{}) interface{}
 
 // Implements registers a new type that implementation an interface
 // The interfaceValue should be a pointer to the interface type like: (*InterfaceType)(nil)
 // The typeValue should be a empty struct that implements the interfaceValue
+// If the struct implements the interface using pointer receivers a pointer to the struct can be used like: (*StructThatImplements)(nil)
+// The type is registered for all schemas, use (*Schema).Implements to register it for a single schema
+//
+// The typeValue can also be a pointer to a interface that embeds the interfaceValue, the embedding interface then implements
+// the interfaceValue in graphql, this is never inferred from the methods of the interfaces
 //
 // Example:
 //   var _ = Implements((*InterfaceType)(nil), StructThatImplements{})
+//   var _ = Implements((*InterfaceType)(nil), (*InterfaceThatEmbedsInterfaceType)(nil))
 func Implements(interfaceValue interface{}, typeValue interface{}) bool {
+	err := globalTypeRegistry.implements(interfaceValue, typeValue)
+	if err != nil {
+		panic(err.Error())
+	}
+	return true
+}
+
+func (r *typeRegistry) implements(interfaceValue interface{}, typeValue interface{}) error {
 	if interfaceValue == nil {
-		panic("interfaceValue cannot be nil")
+		return errors.New("interfaceValue cannot be nil")
 	}
 	interfaceType := reflect.TypeOf(interfaceValue)
 	if interfaceType.Kind() != reflect.Ptr {
-		panic("interfaceValue should be a pointer to a interface")
+		return errors.New("interfaceValue should be a pointer to a interface")
 	}
 	interfaceType = interfaceType.Elem()
 	if interfaceType.Kind() != reflect.Interface {
-		panic("interfaceValue should be a pointer to a interface")
+		return errors.New("interfaceValue should be a pointer to a interface")
 	}
 
 	interfaceName := interfaceType.Name()
 	interfacePath := interfaceType.PkgPath()
 	if interfaceName == "" || interfacePath == "" {
-		panic("interfaceValue should be a pointer to a named interface, not a inline interface")
+		return errors.New("interfaceValue should be a pointer to a named interface, not a inline interface")
 	}
 
 	if typeValue == nil {
-		panic("typeValue cannot be nil")
+		return errors.New("typeValue cannot be nil")
 	}
 	typeType := reflect.TypeOf(typeValue)
+	if typeType.Kind() == reflect.Ptr && typeType.Elem().Kind() == reflect.Interface {
+		childType := typeType.Elem()
+		if childType.Name() == "" || childType.PkgPath() == "" {
+			return errors.New("typeValue should be a pointer to a named interface, not a inline interface")
+		}
+		if childType == interfaceType || !childType.Implements(interfaceType) || interfaceType.Implements(childType) {
+			return errors.New(typeKey(childType) + " does not embed " + typeKey(interfaceType))
+		}
+		r.interfaces[typeKey(interfaceType)] = interfaceType
+		r.interfaces[typeKey(childType)] = childType
+		childKey := typeKey(childType)
+		if !containsType(r.interfaceParents[childKey], interfaceType) {
+			r.interfaceParents[childKey] = append(r.interfaceParents[childKey], interfaceType)
+		}
+		return nil
+	}
+	if typeType.Kind() == reflect.Ptr && typeType.Elem().Kind() == reflect.Struct {
+		// The pointer and the struct are both resolved as the struct type
+		typeType = typeType.Elem()
+	}
 	if typeType.Kind() != reflect.Struct {
-		panic("typeValue must be a struct")
+		return errors.New("typeValue must be a struct")
+	}
+
+	if typeType.Name() == "" || typeType.PkgPath() == "" {
+		return errors.New("typeName should is not allowed to be a inline struct")
+	}
+
+	if !implementsInterface(typeType, interfaceType) {
+		return errors.New(typeKey(typeType) + " does not implement " + typeKey(interfaceType))
 	}
 
-	typeName := typeType.Name()
-	typePath := typeType.PkgPath()
-	if typeName == "" || typePath == "" {
-		panic("typeName should is not allowed to be a inline struct")
+	interfaceKey := typeKey(interfaceType)
+	r.interfaces[interfaceKey] = interfaceType
+	if containsType(r.implementations[interfaceKey], typeType) {
+		// already registered
+		return nil
+	}
+	r.implementations[interfaceKey] = append(r.implementations[interfaceKey], typeType)
+
+	structKey := typeKey(typeType)
+	r.structInterfaces[structKey] = append(r.structInterfaces[structKey], interfaceType)
+
+	return nil
+}
+
+// implementsInterface returns true if the struct t or a pointer to t implements interfaceType
+func implementsInterface(t reflect.Type, interfaceType reflect.Type) bool {
+	return t.Implements(interfaceType) || reflect.PtrTo(t).Implements(interfaceType)
+}
+
+// parentInterfaces returns the interfaces implemented by the interface t
+// These are the interfaces t is registered for using Implements and the interfaces implemented by those
+func (r *typeRegistry) parentInterfaces(t reflect.Type) []reflect.Type {
+	res := []reflect.Type{}
+	toCheck := []reflect.Type{t}
+	for len(toCheck) > 0 {
+		current := toCheck[0]
+		toCheck = toCheck[1:]
+		for _, parent := range r.interfaceParents[typeKey(current)] {
+			if parent != t && !containsType(res, parent) {
+				res = append(res, parent)
+				toCheck = append(toCheck, parent)
+			}
+		}
 	}
+	sortTypes(res)
+	return res
+}
 
-	if !typeType.Implements(interfaceType) {
-		panic(typePath + "." + typePath + " does not implement " + interfacePath + "." + interfaceName)
+// childInterfaces returns the registered interfaces that implement the interface t
+func (r *typeRegistry) childInterfaces(t reflect.Type) []reflect.Type {
+	res := []reflect.Type{}
+	for _, registered := range r.interfaces {
+		if registered != t && containsType(r.parentInterfaces(registered), t) {
+			res = append(res, registered)
+		}
 	}
+	sortTypes(res)
+	return res
+}
 
-	typesThatImplementInterf, ok := implementationMap[interfaceName]
-	if !ok {
-		typesThatImplementInterf = []reflect.Type{}
-	} else {
-		for _, t := range typesThatImplementInterf {
-			if t.Name() == typeName && t.PkgPath() == typePath {
-				// already registered
-				return true
+// withParentInterfaces returns the interfaces together with the interfaces they implement
+// A type implementing a interface must also implement the interfaces implemented by that interface
+func (r *typeRegistry) withParentInterfaces(interfaces []reflect.Type) []reflect.Type {
+	res := []reflect.Type{}
+	for _, interfaceType := range interfaces {
+		for _, t := range append([]reflect.Type{interfaceType}, r.parentInterfaces(interfaceType)...) {
+			if !containsType(res, t) {
+				res = append(res, t)
 			}
 		}
 	}
-	typesThatImplementInterf = append(typesThatImplementInterf, typeType)
-	implementationMap[interfaceName] = typesThatImplementInterf
+	return res
+}
+
+func containsType(list []reflect.Type, t reflect.Type) bool {
+	for _, item := range list {
+		if item == t {
+			return true
+		}
+	}
+	return false
+}
 
-	structImplementsMap[typeName] = append(structImplementsMap[typeName], interfaceType)
+func sortTypes(list []reflect.Type) {
+	sort.Slice(list, func(a, b int) bool {
+		return typeKey(list[a]) < typeKey(list[b])
+	})
+}
 
-	return true
+// typeConditionMatches returns true if a fragment with the type condition name can be applied to the object typeObj
+// That is the case if name is the name of the object or of one of the interfaces it implements
+func typeConditionMatches(typeObj *obj, name []byte) bool {
+	if bytes.Equal(typeObj.typeNameBytes, name) {
+		return true
+	}
+	if typeObj.valueType != valueTypeObj {
+		return false
+	}
+	for _, implementation := range typeObj.implementations {
+		if implementation.typeName == b2s(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// findImplementation returns the implementation of the interface typeObj with the go type t
+func findImplementation(typeObj *obj, t reflect.Type) *obj {
+	name := t.Name()
+	pkgPath := t.PkgPath()
+	for _, implementation := range typeObj.implementations {
+		if implementation.goTypeName == name && implementation.goPkgPath == pkgPath {
+			return implementation
+		}
+	}
+	return nil
 }
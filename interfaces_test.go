@@ -2,6 +2,7 @@ package yarql
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	a "github.com/mjarkk/yarql/assert"
@@ -33,16 +34,16 @@ func (BazWImpl) ResolveFoo() string { return "this is baz" }
 func (BazWImpl) ResolveBar() string { return "This is baz" }
 
 func TestInterfaceType(t *testing.T) {
-	implementationMapLen := len(implementationMap)
-	structImplementsMapLen := len(structImplementsMap)
+	implementationMapLen := len(globalTypeRegistry.implementations)
+	structImplementsMapLen := len(globalTypeRegistry.structInterfaces)
 
 	Implements((*InterfaceType)(nil), BarWImpl{})
-	a.Equal(t, implementationMapLen+1, len(implementationMap))
-	a.Equal(t, structImplementsMapLen+1, len(structImplementsMap))
+	a.Equal(t, implementationMapLen+1, len(globalTypeRegistry.implementations))
+	a.Equal(t, structImplementsMapLen+1, len(globalTypeRegistry.structInterfaces))
 
 	Implements((*InterfaceType)(nil), BazWImpl{})
-	a.Equal(t, implementationMapLen+1, len(implementationMap))
-	a.Equal(t, structImplementsMapLen+2, len(structImplementsMap))
+	a.Equal(t, implementationMapLen+1, len(globalTypeRegistry.implementations))
+	a.Equal(t, structImplementsMapLen+2, len(globalTypeRegistry.structInterfaces))
 
 	_, err := newParseCtx().check(reflect.TypeOf(InterfaceSchema{}), false)
 	a.Nil(t, err)
@@ -78,3 +79,241 @@ func TestInterfaceInvalidInput(t *testing.T) {
 		Implements((*InterfaceType)(nil), InvalidStruct{})
 	}, "cannot use struct that doesn't implement the interface")
 }
+
+type HierarchyNode interface {
+	ResolveHierarchyId() string
+}
+
+type HierarchyResource interface {
+	HierarchyNode
+	ResolveUrl() string
+}
+
+type HierarchyMedia interface {
+	HierarchyResource
+	ResolveDuration() int
+}
+
+type HierarchyImage struct{}
+
+func (HierarchyImage) ResolveHierarchyId() string { return "1" }
+func (HierarchyImage) ResolveUrl() string         { return "image.png" }
+
+type HierarchyVideo struct{}
+
+func (HierarchyVideo) ResolveHierarchyId() string { return "2" }
+func (HierarchyVideo) ResolveUrl() string         { return "video.mp4" }
+func (HierarchyVideo) ResolveDuration() int       { return 10 }
+
+var _ = Implements((*HierarchyNode)(nil), (*HierarchyResource)(nil))
+var _ = Implements((*HierarchyResource)(nil), (*HierarchyMedia)(nil))
+var _ = Implements((*HierarchyResource)(nil), HierarchyImage{})
+var _ = Implements((*HierarchyMedia)(nil), HierarchyVideo{})
+
+type HierarchyQuery struct {
+	Nodes     []HierarchyNode
+	Resources []HierarchyResource
+}
+
+func newHierarchySchema(t *testing.T) *Schema {
+	s := NewSchema()
+	err := s.Parse(HierarchyQuery{
+		Nodes:     []HierarchyNode{HierarchyImage{}, HierarchyVideo{}},
+		Resources: []HierarchyResource{HierarchyVideo{}},
+	}, M{}, nil)
+	a.NoError(t, err)
+	return s
+}
+
+func TestInterfaceImplementsInterface(t *testing.T) {
+	s := newHierarchySchema(t)
+
+	sdl := s.SDL()
+	expected := []string{
+		"interface HierarchyMedia implements HierarchyNode & HierarchyResource {\n",
+		"interface HierarchyNode {\n",
+		"interface HierarchyResource implements HierarchyNode {\n",
+		"type HierarchyImage implements HierarchyNode & HierarchyResource {\n",
+		"type HierarchyVideo implements HierarchyMedia & HierarchyNode & HierarchyResource {\n",
+	}
+	for _, part := range expected {
+		a.True(t, strings.Contains(sdl, part), part+"\n"+sdl)
+	}
+
+	query := `{
+		node: __type(name: "HierarchyNode") {interfaces {name} possibleTypes {name}}
+		media: __type(name: "HierarchyMedia") {interfaces {name} possibleTypes {name}}
+	}`
+	errs := s.Resolve([]byte(query), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"node":{"interfaces":[],"possibleTypes":[{"name":"HierarchyVideo"},{"name":"HierarchyImage"}]},"media":{"interfaces":[{"name":"HierarchyNode"},{"name":"HierarchyResource"}],"possibleTypes":[{"name":"HierarchyVideo"}]}}`, string(s.Result))
+}
+
+func TestInterfaceFragments(t *testing.T) {
+	s := newHierarchySchema(t)
+
+	query := `{
+		nodes {
+			__typename
+			...node
+			... on HierarchyResource {url}
+			... on HierarchyMedia {duration}
+		}
+		resources {... on HierarchyNode {hierarchyId}}
+	}
+	fragment node on HierarchyNode {hierarchyId}`
+	errs := s.Resolve([]byte(query), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"nodes":[{"__typename":"HierarchyImage","hierarchyId":"1","url":"image.png"},{"__typename":"HierarchyVideo","hierarchyId":"2","url":"video.mp4","duration":10}],"resources":[{"hierarchyId":"2"}]}`, string(s.Result))
+}
+
+// HierarchyLink has all methods of HierarchyResource without being registered as implementing it
+type HierarchyLink interface {
+	ResolveHierarchyId() string
+	ResolveUrl() string
+	ResolveTarget() string
+}
+
+type HierarchyAnchor struct{}
+
+func (HierarchyAnchor) ResolveHierarchyId() string { return "3" }
+func (HierarchyAnchor) ResolveUrl() string         { return "index.html" }
+func (HierarchyAnchor) ResolveTarget() string      { return "_blank" }
+
+func TestInterfaceImplementsInterfaceNotInferred(t *testing.T) {
+	s := NewSchema()
+	err := s.Implements((*HierarchyLink)(nil), HierarchyAnchor{})
+	a.NoError(t, err)
+	err = s.Parse(struct {
+		Nodes []HierarchyNode
+		Links []HierarchyLink
+	}{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	a.True(t, strings.Contains(sdl, "interface HierarchyLink {\n"), sdl)
+	a.True(t, strings.Contains(sdl, "type HierarchyAnchor implements HierarchyLink {\n"), sdl)
+
+	errs := s.Resolve([]byte(`{__type(name: "HierarchyNode") {possibleTypes {name}}}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.False(t, strings.Contains(string(s.Result), "HierarchyAnchor"), string(s.Result))
+}
+
+func TestInterfaceImplementsInterfaceInvalid(t *testing.T) {
+	a.Panics(t, func() {
+		// HierarchyNode doesn't embed HierarchyResource
+		Implements((*HierarchyResource)(nil), (*HierarchyNode)(nil))
+	})
+	a.Panics(t, func() {
+		Implements((*HierarchyNode)(nil), (*interface{ ResolveHierarchyId() string })(nil))
+	})
+}
+
+func TestInterfaceImplementsInterfaceSDL(t *testing.T) {
+	sdl := newHierarchySchema(t).SDL()
+
+	// The exported SDL can be used to parse the same schema
+	err := NewSchema().ParseWithSDL([]byte(sdl), HierarchyQuery{}, M{})
+	a.NoError(t, err)
+
+	invalidSDL := strings.Replace(sdl, "interface HierarchyResource implements HierarchyNode {", "interface HierarchyResource {", 1)
+	err = NewSchema().ParseWithSDL([]byte(invalidSDL), HierarchyQuery{}, M{})
+	a.Error(t, err)
+	a.True(t, strings.Contains(err.Error(), "interface HierarchyResource implements nothing in the SDL but HierarchyNode in go"), err.Error())
+}
+
+type PointerShape interface {
+	ResolveArea() int
+}
+
+type PointerSquare struct {
+	Size int
+}
+
+func (s *PointerSquare) ResolveArea() int { return s.Size * s.Size }
+
+type PointerCircle struct{}
+
+func (PointerCircle) ResolveArea() int { return 3 }
+
+// pointerLegacyShape implements PointerShape but isn't part of the schema
+type pointerLegacyShape struct {
+	size int
+}
+
+func (s pointerLegacyShape) ResolveArea() int { return s.size }
+
+var _ = Implements((*PointerShape)(nil), (*PointerSquare)(nil))
+var _ = Implements((*PointerShape)(nil), PointerCircle{})
+
+type PointerQuery struct {
+	Square PointerSquare
+	Shapes []PointerShape
+}
+
+func (q *PointerQuery) ResolveShapeCount() int { return len(q.Shapes) }
+
+func TestInterfacePointerImplementations(t *testing.T) {
+	query := PointerQuery{
+		Square: PointerSquare{Size: 2},
+		Shapes: []PointerShape{&PointerSquare{Size: 3}, PointerCircle{}, (*PointerSquare)(nil)},
+	}
+
+	res := bytecodeParseAndExpectNoErrs(t, `{
+		shapeCount
+		square {area}
+		shapes {
+			__typename
+			area
+			... on PointerSquare {size}
+		}
+	}`, query, M{}, ResolveOptions{NoMeta: true})
+	a.Equal(t, `{"shapeCount":3,"square":{"area":4},"shapes":[{"__typename":"PointerSquare","area":9,"size":3},{"__typename":"PointerCircle","area":3},null]}`, res)
+}
+
+func TestInterfaceResolveType(t *testing.T) {
+	query := PointerQuery{
+		Shapes: []PointerShape{pointerLegacyShape{size: 4}, pointerLegacyShape{}},
+	}
+
+	var interfaceNames []string
+	s := NewSchema()
+	err := s.Parse(query, M{}, &SchemaOptions{
+		ResolveType: func(interfaceName string, value interface{}) interface{} {
+			interfaceNames = append(interfaceNames, interfaceName)
+			shape := value.(pointerLegacyShape)
+			if shape.size == 0 {
+				return nil
+			}
+			return &PointerSquare{Size: shape.size}
+		},
+	})
+	a.NoError(t, err)
+
+	errs := s.Resolve([]byte(`{shapes {__typename area}}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"shapes":[{"__typename":"PointerSquare","area":16},null]}`, string(s.Result))
+	a.Equal(t, []string{"PointerShape", "PointerShape"}, interfaceNames)
+
+	// Values returned by ResolveType must implement the interface
+	err = s.Parse(query, M{}, &SchemaOptions{
+		ResolveType: func(interfaceName string, value interface{}) interface{} {
+			return HierarchyImage{}
+		},
+	})
+	a.NoError(t, err)
+	errs = s.Resolve([]byte(`{shapes {area}}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 2, len(errs))
+
+	// Without a ResolveType hook unknown implementations resolve to null
+	res := bytecodeParseAndExpectNoErrs(t, `{shapes {area}}`, query, M{}, ResolveOptions{NoMeta: true})
+	a.Equal(t, `{"shapes":[null,null]}`, res)
+}
@@ -0,0 +1,56 @@
+package yarql
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// interface{} and json.RawMessage values are exposed as the JSON scalar
+// Outputs are encoded using encoding/json, json.RawMessage values are passed through after being compacted
+// Inputs are decoded like encoding/json does when decoding into a interface{}, json.RawMessage inputs get the JSON encoded value
+
+var jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// isJSONType returns true for the go types that are exposed as the JSON scalar, interface{} and json.RawMessage
+func isJSONType(t reflect.Type) bool {
+	return t == jsonRawMessageType || (t.Kind() == reflect.Interface && t.Name() == "" && t.NumMethod() == 0)
+}
+
+// writeJSONValue writes a interface{} or json.RawMessage value as JSON
+func (ctx *Ctx) writeJSONValue(goValue reflect.Value) bool {
+	if goValue.IsNil() || (goValue.Kind() == reflect.Slice && goValue.Len() == 0) {
+		ctx.writeNull()
+		return false
+	}
+
+	buf := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(goValue.Interface())
+	if err != nil {
+		ctx.writeNull()
+		return ctx.err(err.Error())
+	}
+	ctx.write(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}))
+	return false
+}
+
+// assignJSONValue sets goValue, a interface{} or json.RawMessage, to value
+func (ctx *Ctx) assignJSONValue(goValue *reflect.Value, value interface{}) (valueSet bool, criticalErr bool) {
+	if goValue.Type() == jsonRawMessageType {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return false, ctx.err(err.Error())
+		}
+		goValue.SetBytes(raw)
+		return true, false
+	}
+
+	if value == nil {
+		goValue.Set(reflect.Zero(goValue.Type()))
+	} else {
+		goValue.Set(reflect.ValueOf(value))
+	}
+	return true, false
+}
@@ -0,0 +1,103 @@
+package yarql
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestJSONQuery struct {
+	Meta     interface{}
+	List     []interface{}
+	Raw      json.RawMessage
+	RawPtr   *json.RawMessage
+	Empty    interface{}
+	EmptyRaw json.RawMessage
+}
+
+func (TestJSONQuery) ResolveEcho(args struct{ Value interface{} }) interface{} {
+	return args.Value
+}
+
+func (TestJSONQuery) ResolveEchoRaw(args struct{ Value json.RawMessage }) string {
+	return string(args.Value)
+}
+
+func (TestJSONQuery) ResolveIsNull(args struct{ Value interface{} }) bool {
+	return args.Value == nil
+}
+
+func TestJSONOutput(t *testing.T) {
+	raw := json.RawMessage(`{"b": [1, 2.5], "a": "<tag>"}`)
+	query := TestJSONQuery{
+		Meta: map[string]interface{}{
+			"name":  "foo",
+			"count": 2,
+			"tags":  []string{"a", "b"},
+		},
+		List:   []interface{}{1, "two", nil, true},
+		Raw:    raw,
+		RawPtr: &raw,
+	}
+
+	res := bytecodeParseAndExpectNoErrs(t, `{meta list raw rawPtr empty emptyRaw}`, query, M{})
+	a.Equal(t, `{"meta":{"count":2,"name":"foo","tags":["a","b"]},"list":[1,"two",null,true],"raw":{"b":[1,2.5],"a":"<tag>"},"rawPtr":{"b":[1,2.5],"a":"<tag>"},"empty":null,"emptyRaw":null}`, res)
+
+	_, errs := bytecodeParseAndExpectErrs(t, `{meta {name}}`, query, M{})
+	a.Equal(t, 1, len(errs))
+}
+
+func TestJSONInput(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"object", `{echo(value: {a: [1, "b", true, null], c: {d: ENUM}})}`, ``, `{"echo":{"a":[1,"b",true,null],"c":{"d":"ENUM"}}}`},
+		{"float", `{echo(value: 1.5)}`, ``, `{"echo":1.5}`},
+		{"string", `{echo(value: "foo")}`, ``, `{"echo":"foo"}`},
+		{"null", `{isNull(value: null)}`, ``, `{"isNull":true}`},
+		{"raw", `{echoRaw(value: {b: 1, a: [true]})}`, ``, `{"echoRaw":"{\"a\":[true],\"b\":1}"}`},
+		{"variable", `query ($value: JSON) {echo(value: $value)}`, `{"value": {"a": [1, {"b": null}]}}`, `{"echo":{"a":[1,{"b":null}]}}`},
+		{"raw variable", `query ($value: JSON) {echoRaw(value: $value)}`, `{"value": {"b": 1,  "a": 2}}`, `{"echoRaw":"{\"b\":1,\"a\":2}"}`},
+		{"null variable", `query ($value: JSON) {isNull(value: $value)}`, `{"value": null}`, `{"isNull":true}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			res := bytecodeParseAndExpectNoErrs(t, testCase.query, TestJSONQuery{}, M{}, opts)
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+
+	opts := ResolveOptions{NoMeta: true, Variables: `{"value": "foo"}`}
+	_, errs := bytecodeParseAndExpectErrs(t, `query ($value: String) {echo(value: $value)}`, TestJSONQuery{}, M{}, opts)
+	a.Equal(t, 1, len(errs))
+}
+
+func TestJSONSchema(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestJSONQuery{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	expected := []string{
+		"scalar JSON @specifiedBy(url: \"https://www.rfc-editor.org/rfc/rfc8259\")\n",
+		"  meta: JSON\n",
+		"  list: [JSON]\n",
+		"  echo(value: JSON): JSON\n",
+	}
+	for _, part := range expected {
+		a.True(t, strings.Contains(sdl, part), part+"\n"+sdl)
+	}
+
+	// The JSON scalar is only part of schemas that use it
+	s = NewSchema()
+	err = s.Parse(struct{ Foo string }{}, M{}, nil)
+	a.NoError(t, err)
+	a.False(t, strings.Contains(s.SDL(), "JSON"))
+}
@@ -0,0 +1,142 @@
+package yarql
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+
+	h "github.com/mjarkk/yarql/helpers"
+)
+
+// GraphQL Int values are 32-bit while go integers are often 64-bit
+// With the StrictInt schema option values outside of the Int range result in an error instead of silently being send to the client
+// 64-bit integers can be exposed as the Long scalar, serialized as string, using the Int64AsLong schema option or the gq:",long" struct tag
+
+// defaultLongScalarName is the name of the Long scalar if the LongScalarName schema option is not set
+const defaultLongScalarName = "Long"
+
+// longQLType returns the scalar used for Long values
+func (s *Schema) longQLType() *qlType {
+	res := s.definedScalars[s.longScalarName]
+	return &res
+}
+
+// defineLongScalar adds the Long scalar to the schema
+func (c *parseCtx) defineLongScalar() {
+	name := c.schema.longScalarName
+	if _, ok := c.schema.definedScalars[name]; ok {
+		return
+	}
+	if c.schema.definedScalars == nil {
+		c.schema.definedScalars = map[string]qlType{}
+	}
+	c.schema.definedScalars[name] = qlType{
+		Kind:        typeKindScalar,
+		Name:        h.StrPtr(name),
+		Description: h.StrPtr("The " + name + " scalar type represents a 64-bit integer, serialized as string. Expects a string or number"),
+	}
+}
+
+// isIntKind returns true for all go integer kinds
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isInt64Kind returns true for the go integer kinds that are mapped to Long by the Int64AsLong schema option
+func isInt64Kind(kind reflect.Kind) bool {
+	return kind == reflect.Int64 || kind == reflect.Uint64
+}
+
+// applyLongTag marks the integer value of a struct field tagged with gq:",long" as Long
+func (c *parseCtx) applyLongTag(item *obj, tag fieldTag) error {
+	if !tag.long {
+		return nil
+	}
+	for item.valueType == valueTypePtr || (item.valueType == valueTypeArray && item.mapEntryType == nil) {
+		item = item.innerContent
+	}
+	if item.valueType != valueTypeData || !isIntKind(item.dataValueType) || item.isID {
+		return errors.New("long can only be set on integer values")
+	}
+	item.isLong = true
+	c.defineLongScalar()
+	return nil
+}
+
+// applyInputLongTag marks the integer input of a struct field tagged with gq:",long" as Long
+func (c *parseCtx) applyInputLongTag(in *input, tag fieldTag) error {
+	if !tag.long {
+		return nil
+	}
+	for in.elem != nil && (in.kind == reflect.Ptr || in.kind == reflect.Slice || in.kind == reflect.Array || in.isOptional) {
+		in = in.elem
+	}
+	if !isIntKind(in.kind) || in.isEnum || in.isID || in.isText {
+		return errors.New("long can only be set on integer values")
+	}
+	in.isLong = true
+	c.defineLongScalar()
+	return nil
+}
+
+// intOutOfRange returns true if the integer goValue doesn't fit in a graphql Int
+func intOutOfRange(goValue reflect.Value, kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int64:
+		value := goValue.Int()
+		return value < math.MinInt32 || value > math.MaxInt32
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return goValue.Uint() > math.MaxInt32
+	default:
+		return false
+	}
+}
+
+// strictIntErr returns an error if the StrictInt schema option is set and a input value doesn't fit in a graphql Int
+func (ctx *Ctx) strictIntErr(valueStructure *input, value int64) bool {
+	if !ctx.schema.strictInt || valueStructure.isLong || valueStructure.isID {
+		return false
+	}
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return ctx.errf("Int cannot represent non 32-bit signed integer value: %d", value)
+	}
+	return false
+}
+
+// strictUintErr is the unsigned version of strictIntErr
+func (ctx *Ctx) strictUintErr(valueStructure *input, value uint64) bool {
+	if !ctx.schema.strictInt || valueStructure.isLong || valueStructure.isID {
+		return false
+	}
+	if value > math.MaxInt32 {
+		return ctx.errf("Int cannot represent non 32-bit signed integer value: %d", value)
+	}
+	return false
+}
+
+// assignLongValue binds a number or numeric string to a Long input
+func (ctx *Ctx) assignLongValue(goValue *reflect.Value, value string) bool {
+	switch goValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, err := strconv.ParseInt(value, 10, goValue.Type().Bits())
+		if err != nil {
+			return ctx.err("cannot assign " + strconv.Quote(value) + " to " + ctx.schema.longScalarName + " value, expected a integer within the range of a " + goValue.Type().String())
+		}
+		goValue.SetInt(intValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintValue, err := strconv.ParseUint(value, 10, goValue.Type().Bits())
+		if err != nil {
+			return ctx.err("cannot assign " + strconv.Quote(value) + " to " + ctx.schema.longScalarName + " value, expected a integer within the range of a " + goValue.Type().String())
+		}
+		goValue.SetUint(uintValue)
+	default:
+		return ctx.err("internal error: cannot assign to this " + ctx.schema.longScalarName + " field")
+	}
+	return false
+}
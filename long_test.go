@@ -0,0 +1,191 @@
+package yarql
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestLongQuery struct {
+	Small    int32
+	Big      int64
+	BigU     uint64
+	Count    int    `gq:",long"`
+	Counts   []*int `gq:",long"`
+	BigID    int64  `gq:",id"`
+	Normal   int
+	Overflow int
+}
+
+func (TestLongQuery) ResolveEcho(args struct{ Value int64 }) int64 {
+	return args.Value
+}
+
+func (TestLongQuery) ResolveEchoU(args struct{ Value *uint64 }) uint64 {
+	if args.Value == nil {
+		return 0
+	}
+	return *args.Value
+}
+
+func (TestLongQuery) ResolveEchoInt(args struct{ Value int }) int {
+	return args.Value
+}
+
+func (TestLongQuery) ResolveEchoTagged(args struct {
+	Value int `gq:",long"`
+}) string {
+	return strconv.Itoa(args.Value)
+}
+
+var testLongCount = 5
+
+var testLongData = TestLongQuery{
+	Small:    math.MaxInt32,
+	Big:      math.MinInt64,
+	BigU:     math.MaxUint64,
+	Count:    10,
+	Counts:   []*int{&testLongCount, nil},
+	BigID:    math.MaxInt64,
+	Normal:   42,
+	Overflow: math.MaxInt32 + 1,
+}
+
+func TestLongDefault(t *testing.T) {
+	// Without options 64-bit values are send as is
+	res := bytecodeParseAndExpectNoErrs(t, `{big bigU count counts overflow echo(value: 9007199254740993)}`, testLongData, M{})
+	a.Equal(t, `{"big":-9223372036854775808,"bigU":18446744073709551615,"count":"10","counts":["5",null],"overflow":2147483648,"echo":9007199254740993}`, res)
+}
+
+func TestLongStrictInt(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(testLongData, M{}, &SchemaOptions{StrictInt: true})
+	a.NoError(t, err)
+
+	errs := s.Resolve([]byte(`{small normal count bigID}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"small":2147483647,"normal":42,"count":"10","bigID":"9223372036854775807"}`, string(s.Result))
+
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+	}{
+		{"int field", `{overflow}`, ``},
+		{"int64 field", `{big}`, ``},
+		{"uint64 field", `{bigU}`, ``},
+		{"argument", `{echoInt(value: 2147483648)}`, ``},
+		{"negative argument", `{echoInt(value: -2147483649)}`, ``},
+		{"uint64 argument", `{echoU(value: 2147483648)}`, ``},
+		{"variable", `query ($value: Int) {echoInt(value: $value)}`, `{"value": 2147483648}`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := s.Resolve([]byte(testCase.query), ResolveOptions{NoMeta: true, Variables: testCase.variables})
+			a.Equal(t, 1, len(errs))
+			a.True(t, strings.Contains(errs[0].Error(), "Int cannot represent non 32-bit signed integer value"), errs[0].Error())
+		})
+	}
+
+	errs = s.Resolve([]byte(`{echoInt(value: -2147483648) echoTagged(value: 2147483648)}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"echoInt":-2147483648,"echoTagged":"2147483648"}`, string(s.Result))
+}
+
+func TestLongInt64AsLong(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(testLongData, M{}, &SchemaOptions{Int64AsLong: true, StrictInt: true})
+	a.NoError(t, err)
+
+	errs := s.Resolve([]byte(`{big bigU small bigID}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"big":"-9223372036854775808","bigU":"18446744073709551615","small":2147483647,"bigID":"9223372036854775807"}`, string(s.Result))
+
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"above 2^53", `{echo(value: 9007199254740993)}`, ``, `{"echo":"9007199254740993"}`},
+		{"string", `{echo(value: "-9223372036854775808")}`, ``, `{"echo":"-9223372036854775808"}`},
+		{"uint64", `{echoU(value: "18446744073709551615")}`, ``, `{"echoU":"18446744073709551615"}`},
+		{"string variable", `query ($value: Long) {echo(value: $value)}`, `{"value": "123"}`, `{"echo":"123"}`},
+		{"number variable", `query ($value: Long) {echo(value: $value)}`, `{"value": 123}`, `{"echo":"123"}`},
+		{"uint64 variable", `query ($value: Long) {echoU(value: $value)}`, `{"value": 18446744073709551615}`, `{"echoU":"18446744073709551615"}`},
+		{"int variable", `query ($value: Int) {echo(value: $value)}`, `{"value": 1}`, `{"echo":"1"}`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := s.Resolve([]byte(testCase.query), ResolveOptions{NoMeta: true, Variables: testCase.variables})
+			for _, err := range errs {
+				t.Fatal(err)
+			}
+			a.Equal(t, testCase.expected, string(s.Result))
+		})
+	}
+
+	invalid := []struct {
+		name      string
+		query     string
+		variables string
+	}{
+		{"not a number", `{echo(value: "abc")}`, ``},
+		{"float", `{echo(value: 1.5)}`, ``},
+		{"negative uint64", `{echoU(value: "-1")}`, ``},
+		{"overflow", `{echo(value: "9223372036854775808")}`, ``},
+		{"wrong variable type", `query ($value: String) {echo(value: $value)}`, `{"value": "1"}`},
+		{"wrong variable value", `query ($value: Long) {echo(value: $value)}`, `{"value": true}`},
+	}
+	for _, testCase := range invalid {
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := s.Resolve([]byte(testCase.query), ResolveOptions{NoMeta: true, Variables: testCase.variables})
+			a.Equal(t, 1, len(errs))
+		})
+	}
+}
+
+func TestLongSchema(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestLongQuery{}, M{}, &SchemaOptions{Int64AsLong: true, LongScalarName: "BigInt"})
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	expected := []string{
+		"scalar BigInt\n",
+		"  big: BigInt!\n",
+		"  bigID: ID!\n",
+		"  count: BigInt!\n",
+		"  counts: [BigInt]\n",
+		"  echo(value: BigInt!): BigInt!\n",
+		"  echoInt(value: Int!): Int!\n",
+		"  echoTagged(value: BigInt!): String!\n",
+		"  small: Int!\n",
+	}
+	for _, part := range expected {
+		a.True(t, strings.Contains(sdl, part), part+"\n"+sdl)
+	}
+
+	// The Long scalar is only part of schemas that use it
+	s = NewSchema()
+	err = s.Parse(struct{ Foo int64 }{}, M{}, nil)
+	a.NoError(t, err)
+	a.False(t, strings.Contains(s.SDL(), "Long"))
+
+	err = NewSchema().Parse(struct {
+		Foo string `gq:",long"`
+	}{}, M{}, nil)
+	a.Error(t, err)
+
+	err = NewSchema().Parse(struct{ Foo int64 }{}, M{}, &SchemaOptions{LongScalarName: "Int"})
+	a.Error(t, err)
+}
@@ -0,0 +1,205 @@
+package yarql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Maps are exposed as a list of entry objects with a key and value field
+// The name of the entry type is generated from the key and value type, for example:
+//   map[string]int    -> [StringIntEntry]   with key: String! and value: Int!
+//   map[int][]*User   -> [IntUserListEntry] with key: Int!    and value: [User]
+// As input the map is a list of entry input objects, named like the output type with a Input suffix
+
+// mapEntryGoType returns the struct type used to resolve and bind the entries of a map
+func mapEntryGoType(t reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Key", Type: t.Key()},
+		{Name: "Value", Type: t.Elem()},
+	})
+}
+
+// mapEntryTypeName generates the name of a map entry type based on the graphql types of the key and value
+func mapEntryTypeName(key *qlType, value *qlType) string {
+	return qlTypeBaseName(key) + qlTypeBaseName(value) + "Entry"
+}
+
+// qlTypeBaseName returns the name of the named type inside t with a List suffix for every list wrapper
+func qlTypeBaseName(t *qlType) string {
+	suffix := ""
+	for t.Kind == typeKindList || t.Kind == typeKindNonNull {
+		if t.Kind == typeKindList {
+			suffix += "List"
+		}
+		t = t.OfType
+	}
+	if t.Name == nil {
+		return "Unknown" + suffix
+	}
+	return *t.Name + suffix
+}
+
+func (c *parseCtx) checkMap(t reflect.Type, hasIDTag bool) (*obj, error) {
+	if hasIDTag {
+		return nil, errors.New("maps cannot have ID attribute")
+	}
+
+	key, err := c.check(t.Key(), false)
+	if err != nil {
+		return nil, err
+	}
+	if key.valueType != valueTypeData && key.valueType != valueTypeEnum {
+		return nil, fmt.Errorf("unsupported map key type %s, map keys must be a string, number, boolean or enum", t.Key().String())
+	}
+	key.qlFieldName = []byte("key")
+	key.goFieldName = "Key"
+	key.structFieldIdx = 0
+
+	value, err := c.check(t.Elem(), false)
+	if err != nil {
+		return nil, err
+	}
+	value.qlFieldName = []byte("value")
+	value.goFieldName = "Value"
+	value.structFieldIdx = 1
+
+	keyType := wrapQLTypeInNonNull(c.schema.objToQLType(key))
+	valueType := wrapQLTypeInNonNull(c.schema.objToQLType(value))
+	name := mapEntryTypeName(keyType, valueType)
+
+	entry := &obj{
+		valueType:     valueTypeObj,
+		typeName:      name,
+		typeNameBytes: []byte(name),
+		goTypeName:    name,
+		objContents: map[uint32]*obj{
+			getObjKey(key.qlFieldName):   key,
+			getObjKey(value.qlFieldName): value,
+		},
+		mapEntryType: mapEntryGoType(t),
+	}
+
+	existing, ok := c.schema.types.Get(name)
+	if !ok {
+		c.schema.types[name] = entry
+	} else if existing.mapEntryType == nil {
+		return nil, fmt.Errorf("cannot generate map entry type %s for %s, a type with the same name already exists", name, t.String())
+	} else {
+		existingKeyType := wrapQLTypeInNonNull(c.schema.objToQLType(existing.objContents[getObjKey(key.qlFieldName)]))
+		existingValueType := wrapQLTypeInNonNull(c.schema.objToQLType(existing.objContents[getObjKey(value.qlFieldName)]))
+		if sdlTypeReference(*existingKeyType) != sdlTypeReference(*keyType) || sdlTypeReference(*existingValueType) != sdlTypeReference(*valueType) {
+			return nil, fmt.Errorf("cannot generate map entry type %s for %s, another map with different key or value nullability already uses this name", name, t.String())
+		}
+	}
+
+	// The entry is resolved using its own obj as the go types of maps with the same entry type can differ (int and int64 for example)
+	return &obj{
+		valueType:    valueTypeArray,
+		innerContent: entry,
+		mapEntryType: entry.mapEntryType,
+	}, nil
+}
+
+func (c *parseCtx) checkMapInput(t reflect.Type) (input, error) {
+	res := input{
+		kind:   reflect.Map,
+		goType: mapEntryGoType(t),
+	}
+
+	key, err := c.checkFunctionInput(t.Key(), false)
+	if err != nil {
+		return res, err
+	}
+	if key.isID || key.isTime || key.isFile || key.isAny || key.isJSON || key.isText || key.isBase64 || key.elem != nil || key.kind == reflect.Struct {
+		return res, fmt.Errorf("unsupported map key type %s, map keys must be a string, number, boolean or enum", t.Key().String())
+	}
+	key.goFieldIdx = 0
+	key.gqFieldName = "key"
+
+	value, err := c.checkFunctionInput(t.Elem(), false)
+	if err != nil {
+		return res, err
+	}
+	value.goFieldIdx = 1
+	value.gqFieldName = "value"
+
+	keyType := wrapQLTypeInNonNull(c.schema.inputToQLType(&key))
+	valueType := wrapQLTypeInNonNull(c.schema.inputToQLType(&value))
+	name := mapEntryTypeName(keyType, valueType) + "Input"
+
+	existing, ok := c.schema.inTypes[name]
+	if !ok {
+		c.schema.inTypes[name] = &input{
+			kind:       reflect.Struct,
+			structName: name,
+			structContent: map[string]input{
+				"key":   key,
+				"value": value,
+			},
+			goType: res.goType,
+		}
+	} else {
+		// The go types of generated entries are unnamed structs while user defined input types are always named
+		if existing.goType == nil || existing.goType.Name() != "" {
+			return res, fmt.Errorf("cannot generate map entry input type %s for %s, a input type with the same name already exists", name, t.String())
+		}
+		existingKey := existing.structContent["key"]
+		existingValue := existing.structContent["value"]
+		if sdlTypeReference(*wrapQLTypeInNonNull(c.schema.inputToQLType(&existingKey))) != sdlTypeReference(*keyType) || sdlTypeReference(*wrapQLTypeInNonNull(c.schema.inputToQLType(&existingValue))) != sdlTypeReference(*valueType) {
+			return res, fmt.Errorf("cannot generate map entry input type %s for %s, another map with different key or value nullability already uses this name", name, t.String())
+		}
+	}
+
+	res.elem = &input{
+		kind:             reflect.Struct,
+		structName:       name,
+		isStructPointers: true,
+	}
+	return res, nil
+}
+
+// mapEntries returns a slice with the entries of a map sorted by key
+func mapEntries(goValue reflect.Value, entryType reflect.Type) reflect.Value {
+	keys := goValue.MapKeys()
+	sortMapKeys(keys)
+
+	entries := reflect.MakeSlice(reflect.SliceOf(entryType), len(keys), len(keys))
+	for i, key := range keys {
+		entry := entries.Index(i)
+		entry.Field(0).Set(key)
+		entry.Field(1).Set(goValue.MapIndex(key))
+	}
+	return entries
+}
+
+// setMapEntries sets goValue to a new map with the entries of a slice of map entry structs
+func setMapEntries(goValue *reflect.Value, entries reflect.Value) {
+	res := reflect.MakeMapWithSize(goValue.Type(), entries.Len())
+	for i := 0; i < entries.Len(); i++ {
+		entry := entries.Index(i)
+		res.SetMapIndex(entry.Field(0), entry.Field(1))
+	}
+	goValue.Set(res)
+}
+
+func sortMapKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(a int, b int) bool {
+		keyA, keyB := keys[a], keys[b]
+		switch keyA.Kind() {
+		case reflect.String:
+			return keyA.String() < keyB.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return keyA.Int() < keyB.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return keyA.Uint() < keyB.Uint()
+		case reflect.Float32, reflect.Float64:
+			return keyA.Float() < keyB.Float()
+		case reflect.Bool:
+			return !keyA.Bool() && keyB.Bool()
+		default:
+			return false
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package yarql
+
+import (
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestMapsUser struct {
+	Name string
+}
+
+func (u TestMapsUser) ResolveShout() string {
+	return strings.ToUpper(u.Name)
+}
+
+type TestMapsQuery struct {
+	Scores map[string]int
+	Counts map[int64]int64
+	Users  map[uint][]*TestMapsUser
+	Nested map[string]map[bool]string
+	Empty  map[string]int
+}
+
+func (TestMapsQuery) ResolveSum(args struct{ Values map[string]int }) int {
+	sum := 0
+	for _, value := range args.Values {
+		sum += value
+	}
+	return sum
+}
+
+var testMapsData = TestMapsQuery{
+	Scores: map[string]int{"b": 2, "a": 1, "c": 3},
+	Counts: map[int64]int64{10: 1, -5: 2},
+	Users: map[uint][]*TestMapsUser{
+		2: {{Name: "b"}, nil},
+		1: {{Name: "a"}},
+	},
+	Nested: map[string]map[bool]string{"x": {true: "yes", false: "no"}},
+}
+
+func TestMapsOutput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"string keys", `{scores {key value}}`, `{"scores":[{"key":"a","value":1},{"key":"b","value":2},{"key":"c","value":3}]}`},
+		{"int keys", `{counts {key value}}`, `{"counts":[{"key":-5,"value":2},{"key":10,"value":1}]}`},
+		{"list values", `{users {key value {name shout}}}`, `{"users":[{"key":1,"value":[{"name":"a","shout":"A"}]},{"key":2,"value":[{"name":"b","shout":"B"},null]}]}`},
+		{"nested maps", `{nested {key value {key value}}}`, `{"nested":[{"key":"x","value":[{"key":false,"value":"no"},{"key":true,"value":"yes"}]}]}`},
+		{"nil map", `{empty {key}}`, `{"empty":null}`},
+		{"typename", `{scores {__typename}}`, `{"scores":[{"__typename":"StringIntEntry"},{"__typename":"StringIntEntry"},{"__typename":"StringIntEntry"}]}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			res := bytecodeParseAndExpectNoErrs(t, testCase.query, testMapsData, M{})
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestMapsInput(t *testing.T) {
+	res := bytecodeParseAndExpectNoErrs(t, `{sum(values: [{key: "a", value: 1}, {key: "b", value: 2}])}`, TestMapsQuery{}, M{})
+	a.Equal(t, `{"sum":3}`, res)
+
+	res = bytecodeParseAndExpectNoErrs(t, `query ($values: [StringIntEntryInput!]) {sum(values: $values)}`, TestMapsQuery{}, M{}, ResolveOptions{
+		NoMeta:    true,
+		Variables: `{"values": [{"key": "a", "value": 5}, {"key": "b", "value": 6}]}`,
+	})
+	a.Equal(t, `{"sum":11}`, res)
+
+	_, errs := bytecodeParseAndExpectErrs(t, `{sum(values: [{key: "a", other: 1}])}`, TestMapsQuery{}, M{})
+	a.Equal(t, 1, len(errs))
+}
+
+type TestMapsRating uint8
+
+const (
+	TestMapsRatingBad TestMapsRating = iota
+	TestMapsRatingGood
+)
+
+type TestMapsEnumQuery struct {
+	Ratings map[TestMapsRating]string
+}
+
+func (TestMapsEnumQuery) ResolveEcho(args struct{ Values map[TestMapsRating]*string }) map[TestMapsRating]string {
+	res := map[TestMapsRating]string{}
+	for key, value := range args.Values {
+		if value == nil {
+			res[key] = "null"
+		} else {
+			res[key] = *value
+		}
+	}
+	return res
+}
+
+func TestMapsEnumKeys(t *testing.T) {
+	s := NewSchema()
+	_, err := s.RegisterEnum(map[string]TestMapsRating{
+		"BAD":  TestMapsRatingBad,
+		"GOOD": TestMapsRatingGood,
+	})
+	a.NoError(t, err)
+
+	query := `{
+		ratings {key value}
+		echo(values: [{key: GOOD, value: "yes"}, {key: BAD, value: null}]) {key value}
+	}`
+	res, errs := bytecodeParse(t, s, query, TestMapsEnumQuery{
+		Ratings: map[TestMapsRating]string{TestMapsRatingGood: "👍", TestMapsRatingBad: "👎"},
+	}, M{})
+	for _, err := range errs {
+		panic(err)
+	}
+	a.Equal(t, `{"ratings":[{"key":"BAD","value":"👎"},{"key":"GOOD","value":"👍"}],"echo":[{"key":"BAD","value":"null"},{"key":"GOOD","value":"yes"}]}`, res)
+
+	sdl := s.SDL()
+	a.True(t, strings.Contains(sdl, "type TestMapsRatingStringEntry {\n  key: TestMapsRating!\n  value: String!\n}\n"), sdl)
+	a.True(t, strings.Contains(sdl, "input TestMapsRatingStringEntryInput {\n  key: TestMapsRating!\n  value: String\n}\n"), sdl)
+}
+
+func TestMapsSDL(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestMapsQuery{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	expected := []string{
+		"  scores: [StringIntEntry!]\n",
+		"  users: [IntTestMapsUserListEntry!]\n",
+		"  nested: [StringBooleanStringEntryListEntry!]\n",
+		"  sum(values: [StringIntEntryInput!]): Int!\n",
+		"type StringIntEntry {\n  key: String!\n  value: Int!\n}\n",
+		"type IntTestMapsUserListEntry {\n  key: Int!\n  value: [TestMapsUser]\n}\n",
+		"type StringBooleanStringEntryListEntry {\n  key: String!\n  value: [BooleanStringEntry!]\n}\n",
+		"input StringIntEntryInput {\n  key: String!\n  value: Int!\n}\n",
+	}
+	for _, part := range expected {
+		a.True(t, strings.Contains(sdl, part), part+"\n"+sdl)
+	}
+}
+
+type TestMapsInvalidKey struct {
+	Values map[TestMapsUser]int
+}
+
+type TestMapsNullabilityConflict struct {
+	A map[string]int
+	B map[string]*int
+}
+
+type TestMapsNameConflict struct {
+	A              map[string]int
+	StringIntEntry StringIntEntry
+}
+
+type StringIntEntry struct {
+	Foo string
+}
+
+func TestMapsInvalid(t *testing.T) {
+	err := NewSchema().Parse(TestMapsInvalidKey{}, M{}, nil)
+	a.Error(t, err)
+	a.True(t, strings.Contains(err.Error(), "unsupported map key type"), err.Error())
+
+	err = NewSchema().Parse(TestMapsNullabilityConflict{}, M{}, nil)
+	a.Error(t, err)
+
+	err = NewSchema().Parse(TestMapsNameConflict{}, M{}, nil)
+	a.Error(t, err)
+
+	// Different go types with the same graphql types share the entry type
+	err = NewSchema().Parse(struct {
+		A map[string]int
+		B map[string]int64
+	}{}, M{}, nil)
+	a.NoError(t, err)
+}
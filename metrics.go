@@ -0,0 +1,43 @@
+package yarql
+
+import "time"
+
+// Metrics is called by the executor to collect metrics about the resolved operations
+// Set it using ResolveOptions.Metrics, the metrics package contains an in memory implementation
+// that can be exported in the prometheus text format.
+//
+// The same Metrics value is used for concurrent operations so implementations must be safe for concurrent use
+type Metrics interface {
+	// Operation is called after every operation
+	Operation(operation OperationMetrics)
+	// Resolver is called after every resolved field
+	Resolver(resolver ResolverMetrics)
+	// BytecodeCache is called for every query that is big enough to be cached, hit is true if the parsed bytecode was reused
+	BytecodeCache(hit bool)
+}
+
+// OperationMetrics contains the metrics of a single operation
+type OperationMetrics struct {
+	// Name is the name of the operation, empty for anonymous operations
+	Name string
+	// Type is query, mutation or subscription, empty if no operation was executed
+	Type string
+	// Parsing is the time it took to parse the query
+	Parsing time.Duration
+	// Execution is the time it took to resolve the operation after parsing
+	Execution time.Duration
+	// Errors is the number of errors in the response
+	Errors int
+}
+
+// ResolverMetrics contains the metrics of a single resolved field
+type ResolverMetrics struct {
+	// TypeName is the name of the type the field is defined on
+	TypeName string
+	// FieldName is the name of the field in the schema
+	FieldName string
+	// Duration is the time it took to resolve the field including its sub fields
+	Duration time.Duration
+	// Errors is the number of errors of this field, errors of sub fields are not included
+	Errors int
+}
@@ -0,0 +1,258 @@
+// Package metrics contains a dependency free in memory implementation of yarql.Metrics
+// The collected metrics can be exported in the prometheus text exposition format
+//
+// Example:
+//   m := metrics.New()
+//   http.Handle("/graphql", yarql.NewHTTPHandler(schema, yarql.HTTPHandlerOptions{Metrics: m}))
+//   http.Handle("/metrics", m.Handler())
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mjarkk/yarql"
+)
+
+// DefaultBuckets are the default histogram buckets in seconds used for the parsing and execution durations
+var DefaultBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DefaultMaxOperationNames is the default max number of distinct operation names, see Options.MaxOperationNames
+const DefaultMaxOperationNames = 100
+
+// OtherOperationName is the operation name used for operations once the max number of distinct operation names is reached
+const OtherOperationName = "other"
+
+// Memory collects the metrics in memory, it implements yarql.Metrics and is safe for concurrent use
+type Memory struct {
+	// Updated using atomic operations, these are kept at the start of the struct so they are 64-bit aligned
+	cacheHits   uint64
+	cacheMisses uint64
+
+	// resolvers contains a *resolverCounters per ResolverKey, the counters are updated using atomic operations so
+	// resolving fields doesn't have to wait for a lock
+	resolvers sync.Map
+
+	lock              sync.Mutex // locks operations
+	buckets           []float64
+	maxOperationNames int
+	operationNames    map[string]bool
+	operations        map[OperationKey]*OperationStats
+}
+
+// OperationKey identifies an operation
+type OperationKey struct {
+	Name string
+	Type string
+}
+
+// OperationStats are the collected metrics of an operation
+type OperationStats struct {
+	Count     uint64
+	Errors    uint64 // number of operations with errors
+	Parsing   Histogram
+	Execution Histogram
+}
+
+// ResolverKey identifies a field resolver
+type ResolverKey struct {
+	TypeName  string
+	FieldName string
+}
+
+// ResolverStats are the collected metrics of a field resolver
+type ResolverStats struct {
+	Calls    uint64
+	Errors   uint64 // number of calls with errors
+	Duration time.Duration
+}
+
+// resolverCounters are the counters of ResolverStats updated using atomic operations
+type resolverCounters struct {
+	calls    uint64
+	errors   uint64
+	duration int64
+}
+
+// Histogram counts observed durations in buckets
+type Histogram struct {
+	// Buckets are the upper bounds of the buckets in seconds
+	Buckets []float64
+	// Counts contains the number of observations per bucket, the counts are not cumulative
+	// The last entry counts the observations bigger than the last bucket
+	Counts []uint64
+	// Sum is the sum of all observations in seconds
+	Sum float64
+	// Count is the number of observations
+	Count uint64
+}
+
+// Options are options for NewWithOptions
+type Options struct {
+	// Buckets are the histogram buckets in seconds, defaults to DefaultBuckets
+	Buckets []float64
+
+	// MaxOperationNames is the max number of distinct operation names, defaults to DefaultMaxOperationNames
+	// The operation name is set by the client, once the limit is reached operations with a new name are counted as
+	// OtherOperationName so clients can't grow the memory usage and number of prometheus series without bounds
+	// Set to -1 to disable the limit
+	MaxOperationNames int
+}
+
+// New returns a new Memory metrics collector
+func New() *Memory {
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions returns a new Memory metrics collector with custom options
+func NewWithOptions(options Options) *Memory {
+	buckets := options.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	maxOperationNames := options.MaxOperationNames
+	if maxOperationNames == 0 {
+		maxOperationNames = DefaultMaxOperationNames
+	}
+	return &Memory{
+		buckets:           buckets,
+		maxOperationNames: maxOperationNames,
+		operationNames:    map[string]bool{},
+		operations:        map[OperationKey]*OperationStats{},
+	}
+}
+
+func (m *Memory) newHistogram() Histogram {
+	return Histogram{
+		Buckets: m.buckets,
+		Counts:  make([]uint64, len(m.buckets)+1),
+	}
+}
+
+func (h *Histogram) observe(duration time.Duration) {
+	seconds := duration.Seconds()
+	idx := len(h.Buckets)
+	for i, bucket := range h.Buckets {
+		if seconds <= bucket {
+			idx = i
+			break
+		}
+	}
+	h.Counts[idx]++
+	h.Sum += seconds
+	h.Count++
+}
+
+func (h Histogram) copy() Histogram {
+	h.Counts = append([]uint64{}, h.Counts...)
+	return h
+}
+
+// Operation implements yarql.Metrics
+func (m *Memory) Operation(operation yarql.OperationMetrics) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	name := operation.Name
+	if !m.operationNames[name] {
+		if m.maxOperationNames >= 0 && len(m.operationNames) >= m.maxOperationNames {
+			name = OtherOperationName
+		} else {
+			m.operationNames[name] = true
+		}
+	}
+
+	key := OperationKey{Name: name, Type: operation.Type}
+	stats, ok := m.operations[key]
+	if !ok {
+		stats = &OperationStats{
+			Parsing:   m.newHistogram(),
+			Execution: m.newHistogram(),
+		}
+		m.operations[key] = stats
+	}
+	stats.Count++
+	if operation.Errors > 0 {
+		stats.Errors++
+	}
+	stats.Parsing.observe(operation.Parsing)
+	stats.Execution.observe(operation.Execution)
+}
+
+// Resolver implements yarql.Metrics
+func (m *Memory) Resolver(resolver yarql.ResolverMetrics) {
+	key := ResolverKey{TypeName: resolver.TypeName, FieldName: resolver.FieldName}
+	value, ok := m.resolvers.Load(key)
+	if !ok {
+		value, _ = m.resolvers.LoadOrStore(key, &resolverCounters{})
+	}
+	counters := value.(*resolverCounters)
+
+	atomic.AddUint64(&counters.calls, 1)
+	if resolver.Errors > 0 {
+		atomic.AddUint64(&counters.errors, 1)
+	}
+	atomic.AddInt64(&counters.duration, int64(resolver.Duration))
+}
+
+// BytecodeCache implements yarql.Metrics
+func (m *Memory) BytecodeCache(hit bool) {
+	if hit {
+		atomic.AddUint64(&m.cacheHits, 1)
+	} else {
+		atomic.AddUint64(&m.cacheMisses, 1)
+	}
+}
+
+// Operations returns a copy of the collected operation metrics
+func (m *Memory) Operations() map[OperationKey]OperationStats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	res := make(map[OperationKey]OperationStats, len(m.operations))
+	for key, stats := range m.operations {
+		res[key] = OperationStats{
+			Count:     stats.Count,
+			Errors:    stats.Errors,
+			Parsing:   stats.Parsing.copy(),
+			Execution: stats.Execution.copy(),
+		}
+	}
+	return res
+}
+
+// Resolvers returns a copy of the collected resolver metrics
+func (m *Memory) Resolvers() map[ResolverKey]ResolverStats {
+	res := map[ResolverKey]ResolverStats{}
+	m.resolvers.Range(func(key, value interface{}) bool {
+		counters := value.(*resolverCounters)
+		res[key.(ResolverKey)] = ResolverStats{
+			Calls:    atomic.LoadUint64(&counters.calls),
+			Errors:   atomic.LoadUint64(&counters.errors),
+			Duration: time.Duration(atomic.LoadInt64(&counters.duration)),
+		}
+		return true
+	})
+	return res
+}
+
+// BytecodeCacheStats returns the number of bytecode cache hits and misses
+func (m *Memory) BytecodeCacheStats() (hits uint64, misses uint64) {
+	return atomic.LoadUint64(&m.cacheHits), atomic.LoadUint64(&m.cacheMisses)
+}
+
+// Reset removes all collected metrics
+func (m *Memory) Reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.operationNames = map[string]bool{}
+	m.operations = map[OperationKey]*OperationStats{}
+	m.resolvers.Range(func(key, value interface{}) bool {
+		m.resolvers.Delete(key)
+		return true
+	})
+	atomic.StoreUint64(&m.cacheHits, 0)
+	atomic.StoreUint64(&m.cacheMisses, 0)
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mjarkk/yarql"
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type testUser struct {
+	Name string
+}
+
+func (testUser) ResolveFriends() ([]testUser, error) {
+	return nil, errors.New("friends are private")
+}
+
+type testQuery struct {
+	Users []testUser
+}
+
+type testMethods struct{}
+
+func newTestSchema(t *testing.T) *yarql.Schema {
+	s := yarql.NewSchema()
+	err := s.Parse(testQuery{Users: []testUser{{Name: "a"}, {Name: "b"}}}, testMethods{}, nil)
+	a.NoError(t, err)
+	return s
+}
+
+func TestMemory(t *testing.T) {
+	m := New()
+	s := newTestSchema(t)
+
+	for i := 0; i < 2; i++ {
+		errs := s.Resolve([]byte(`query GetUsers {users {name}}`), yarql.ResolveOptions{Metrics: m})
+		a.Equal(t, 0, len(errs))
+	}
+	errs := s.Resolve([]byte(`{users {friends {name}}}`), yarql.ResolveOptions{Metrics: m})
+	a.Equal(t, 2, len(errs))
+
+	operations := m.Operations()
+	a.Equal(t, 2, len(operations))
+	getUsers := operations[OperationKey{Name: "GetUsers", Type: "query"}]
+	a.Equal(t, uint64(2), getUsers.Count)
+	a.Equal(t, uint64(0), getUsers.Errors)
+	a.Equal(t, uint64(2), getUsers.Parsing.Count)
+	a.Equal(t, uint64(2), getUsers.Execution.Count)
+	anonymous := operations[OperationKey{Name: "", Type: "query"}]
+	a.Equal(t, uint64(1), anonymous.Count)
+	a.Equal(t, uint64(1), anonymous.Errors)
+
+	resolvers := m.Resolvers()
+	a.Equal(t, uint64(3), resolvers[ResolverKey{TypeName: "testQuery", FieldName: "users"}].Calls)
+	a.Equal(t, uint64(0), resolvers[ResolverKey{TypeName: "testQuery", FieldName: "users"}].Errors)
+	a.Equal(t, uint64(4), resolvers[ResolverKey{TypeName: "testUser", FieldName: "name"}].Calls)
+	a.Equal(t, uint64(2), resolvers[ResolverKey{TypeName: "testUser", FieldName: "friends"}].Calls)
+	a.Equal(t, uint64(2), resolvers[ResolverKey{TypeName: "testUser", FieldName: "friends"}].Errors)
+
+	m.Reset()
+	a.Equal(t, 0, len(m.Operations()))
+	a.Equal(t, 0, len(m.Resolvers()))
+}
+
+func TestMemoryMaxOperationNames(t *testing.T) {
+	m := NewWithOptions(Options{MaxOperationNames: 2})
+	for _, name := range []string{"A", "B", "C", "D", "A"} {
+		m.Operation(yarql.OperationMetrics{Name: name, Type: "query"})
+	}
+
+	operations := m.Operations()
+	a.Equal(t, 3, len(operations))
+	a.Equal(t, uint64(2), operations[OperationKey{Name: "A", Type: "query"}].Count)
+	a.Equal(t, uint64(1), operations[OperationKey{Name: "B", Type: "query"}].Count)
+	a.Equal(t, uint64(2), operations[OperationKey{Name: OtherOperationName, Type: "query"}].Count)
+
+	m = NewWithOptions(Options{MaxOperationNames: -1})
+	for i := 0; i < DefaultMaxOperationNames+1; i++ {
+		m.Operation(yarql.OperationMetrics{Name: strconv.Itoa(i), Type: "query"})
+	}
+	a.Equal(t, DefaultMaxOperationNames+1, len(m.Operations()))
+}
+
+func TestMemoryConcurrentResolvers(t *testing.T) {
+	m := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Resolver(yarql.ResolverMetrics{TypeName: "User", FieldName: "name", Duration: time.Millisecond, Errors: j % 2})
+				m.BytecodeCache(j%2 == 0)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := m.Resolvers()[ResolverKey{TypeName: "User", FieldName: "name"}]
+	a.Equal(t, uint64(800), stats.Calls)
+	a.Equal(t, uint64(400), stats.Errors)
+	a.Equal(t, 800*time.Millisecond, stats.Duration)
+	hits, misses := m.BytecodeCacheStats()
+	a.Equal(t, uint64(400), hits)
+	a.Equal(t, uint64(400), misses)
+}
+
+func TestMemoryBytecodeCache(t *testing.T) {
+	m := New()
+	s := newTestSchema(t)
+
+	// Only big queries are cached
+	query := `{users {name}}` + strings.Repeat(" ", 300)
+	for i := 0; i < 3; i++ {
+		errs := s.Resolve([]byte(query), yarql.ResolveOptions{Metrics: m})
+		a.Equal(t, 0, len(errs))
+	}
+	s.Resolve([]byte(`{users {name}}`), yarql.ResolveOptions{Metrics: m})
+
+	hits, misses := m.BytecodeCacheStats()
+	a.Equal(t, uint64(2), hits)
+	a.Equal(t, uint64(1), misses)
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewWithOptions(Options{Buckets: []float64{0.1, 1}}).newHistogram()
+	h.observe(50 * time.Millisecond)
+	h.observe(100 * time.Millisecond)
+	h.observe(500 * time.Millisecond)
+	h.observe(2 * time.Second)
+
+	a.Equal(t, []uint64{2, 1, 1}, h.Counts)
+	a.Equal(t, uint64(4), h.Count)
+	a.Equal(t, 2.65, h.Sum)
+}
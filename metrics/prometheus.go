@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusContentType is the content type of the prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler returns a http.Handler that responds with the metrics in the prometheus text exposition format
+func (m *Memory) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", PrometheusContentType)
+		m.WritePrometheus(w)
+	})
+}
+
+// WritePrometheus writes the metrics to w in the prometheus text exposition format
+// https://prometheus.io/docs/instrumenting/exposition_formats/
+func (m *Memory) WritePrometheus(w io.Writer) error {
+	operations := m.Operations()
+	resolvers := m.Resolvers()
+	hits, misses := m.BytecodeCacheStats()
+
+	operationKeys := make([]OperationKey, 0, len(operations))
+	for key := range operations {
+		operationKeys = append(operationKeys, key)
+	}
+	sort.Slice(operationKeys, func(i, j int) bool {
+		if operationKeys[i].Type != operationKeys[j].Type {
+			return operationKeys[i].Type < operationKeys[j].Type
+		}
+		return operationKeys[i].Name < operationKeys[j].Name
+	})
+
+	resolverKeys := make([]ResolverKey, 0, len(resolvers))
+	for key := range resolvers {
+		resolverKeys = append(resolverKeys, key)
+	}
+	sort.Slice(resolverKeys, func(i, j int) bool {
+		if resolverKeys[i].TypeName != resolverKeys[j].TypeName {
+			return resolverKeys[i].TypeName < resolverKeys[j].TypeName
+		}
+		return resolverKeys[i].FieldName < resolverKeys[j].FieldName
+	})
+
+	p := &promWriter{w: bufio.NewWriter(w)}
+
+	p.header("yarql_operations_total", "counter", "Number of resolved operations")
+	for _, key := range operationKeys {
+		p.sample("yarql_operations_total", operationLabels(key), formatUint(operations[key].Count))
+	}
+	p.header("yarql_operation_errors_total", "counter", "Number of resolved operations with errors")
+	for _, key := range operationKeys {
+		p.sample("yarql_operation_errors_total", operationLabels(key), formatUint(operations[key].Errors))
+	}
+	p.header("yarql_operation_parse_duration_seconds", "histogram", "Time spent parsing the query of an operation")
+	for _, key := range operationKeys {
+		p.histogram("yarql_operation_parse_duration_seconds", operationLabels(key), operations[key].Parsing)
+	}
+	p.header("yarql_operation_execution_duration_seconds", "histogram", "Time spent resolving an operation after parsing")
+	for _, key := range operationKeys {
+		p.histogram("yarql_operation_execution_duration_seconds", operationLabels(key), operations[key].Execution)
+	}
+
+	p.header("yarql_resolver_calls_total", "counter", "Number of resolved fields")
+	for _, key := range resolverKeys {
+		p.sample("yarql_resolver_calls_total", resolverLabels(key), formatUint(resolvers[key].Calls))
+	}
+	p.header("yarql_resolver_errors_total", "counter", "Number of resolved fields with errors")
+	for _, key := range resolverKeys {
+		p.sample("yarql_resolver_errors_total", resolverLabels(key), formatUint(resolvers[key].Errors))
+	}
+	p.header("yarql_resolver_duration_seconds_total", "counter", "Total time spent resolving fields including their sub fields")
+	for _, key := range resolverKeys {
+		p.sample("yarql_resolver_duration_seconds_total", resolverLabels(key), formatFloat(resolvers[key].Duration.Seconds()))
+	}
+
+	p.header("yarql_bytecode_cache_lookups_total", "counter", "Number of bytecode cache lookups")
+	p.sample("yarql_bytecode_cache_lookups_total", [][2]string{{"result", "hit"}}, formatUint(hits))
+	p.sample("yarql_bytecode_cache_lookups_total", [][2]string{{"result", "miss"}}, formatUint(misses))
+
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
+
+func operationLabels(key OperationKey) [][2]string {
+	return [][2]string{{"operation", key.Name}, {"type", key.Type}}
+}
+
+func resolverLabels(key ResolverKey) [][2]string {
+	return [][2]string{{"type", key.TypeName}, {"field", key.FieldName}}
+}
+
+// promWriter writes prometheus metrics, after the first error nothing is written
+type promWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (p *promWriter) write(s string) {
+	if p.err == nil {
+		_, p.err = p.w.WriteString(s)
+	}
+}
+
+func (p *promWriter) header(name string, kind string, help string) {
+	p.write("# HELP " + name + " " + help + "\n# TYPE " + name + " " + kind + "\n")
+}
+
+func (p *promWriter) sample(name string, labels [][2]string, value string) {
+	p.write(name)
+	if len(labels) > 0 {
+		p.write("{")
+		for i, label := range labels {
+			if i > 0 {
+				p.write(",")
+			}
+			p.write(label[0] + `="` + escapeLabelValue(label[1]) + `"`)
+		}
+		p.write("}")
+	}
+	p.write(" " + value + "\n")
+}
+
+func (p *promWriter) histogram(name string, labels [][2]string, h Histogram) {
+	var cumulative uint64
+	for i, bucket := range h.Buckets {
+		cumulative += h.Counts[i]
+		p.sample(name+"_bucket", append(labels, [2]string{"le", formatFloat(bucket)}), formatUint(cumulative))
+	}
+	p.sample(name+"_bucket", append(labels, [2]string{"le", "+Inf"}), formatUint(h.Count))
+	p.sample(name+"_sum", labels, formatFloat(h.Sum))
+	p.sample(name+"_count", labels, formatUint(h.Count))
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func formatUint(value uint64) string {
+	return strconv.FormatUint(value, 10)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mjarkk/yarql"
+	a "github.com/mjarkk/yarql/assert"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	m := NewWithOptions(Options{Buckets: []float64{0.1, 1}})
+	m.Operation(yarql.OperationMetrics{Name: `Get"Users`, Type: "query", Parsing: time.Millisecond, Execution: 500 * time.Millisecond, Errors: 1})
+	m.Resolver(yarql.ResolverMetrics{TypeName: "User", FieldName: "name", Duration: time.Second})
+	m.Resolver(yarql.ResolverMetrics{TypeName: "User", FieldName: "name", Duration: time.Second, Errors: 2})
+	m.BytecodeCache(true)
+
+	var out bytes.Buffer
+	err := m.WritePrometheus(&out)
+	a.NoError(t, err)
+
+	expected := `# HELP yarql_operations_total Number of resolved operations
+# TYPE yarql_operations_total counter
+yarql_operations_total{operation="Get\"Users",type="query"} 1
+# HELP yarql_operation_errors_total Number of resolved operations with errors
+# TYPE yarql_operation_errors_total counter
+yarql_operation_errors_total{operation="Get\"Users",type="query"} 1
+# HELP yarql_operation_parse_duration_seconds Time spent parsing the query of an operation
+# TYPE yarql_operation_parse_duration_seconds histogram
+yarql_operation_parse_duration_seconds_bucket{operation="Get\"Users",type="query",le="0.1"} 1
+yarql_operation_parse_duration_seconds_bucket{operation="Get\"Users",type="query",le="1"} 1
+yarql_operation_parse_duration_seconds_bucket{operation="Get\"Users",type="query",le="+Inf"} 1
+yarql_operation_parse_duration_seconds_sum{operation="Get\"Users",type="query"} 0.001
+yarql_operation_parse_duration_seconds_count{operation="Get\"Users",type="query"} 1
+# HELP yarql_operation_execution_duration_seconds Time spent resolving an operation after parsing
+# TYPE yarql_operation_execution_duration_seconds histogram
+yarql_operation_execution_duration_seconds_bucket{operation="Get\"Users",type="query",le="0.1"} 0
+yarql_operation_execution_duration_seconds_bucket{operation="Get\"Users",type="query",le="1"} 1
+yarql_operation_execution_duration_seconds_bucket{operation="Get\"Users",type="query",le="+Inf"} 1
+yarql_operation_execution_duration_seconds_sum{operation="Get\"Users",type="query"} 0.5
+yarql_operation_execution_duration_seconds_count{operation="Get\"Users",type="query"} 1
+# HELP yarql_resolver_calls_total Number of resolved fields
+# TYPE yarql_resolver_calls_total counter
+yarql_resolver_calls_total{type="User",field="name"} 2
+# HELP yarql_resolver_errors_total Number of resolved fields with errors
+# TYPE yarql_resolver_errors_total counter
+yarql_resolver_errors_total{type="User",field="name"} 1
+# HELP yarql_resolver_duration_seconds_total Total time spent resolving fields including their sub fields
+# TYPE yarql_resolver_duration_seconds_total counter
+yarql_resolver_duration_seconds_total{type="User",field="name"} 2
+# HELP yarql_bytecode_cache_lookups_total Number of bytecode cache lookups
+# TYPE yarql_bytecode_cache_lookups_total counter
+yarql_bytecode_cache_lookups_total{result="hit"} 1
+yarql_bytecode_cache_lookups_total{result="miss"} 0
+`
+	a.Equal(t, expected, out.String())
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	m := New()
+	s := newTestSchema(t)
+	handler := yarql.NewHTTPHandler(s, yarql.HTTPHandlerOptions{Metrics: m})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "query GetUsers {users {name}}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	a.Equal(t, PrometheusContentType, w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	a.True(t, strings.Contains(body, `yarql_operations_total{operation="GetUsers",type="query"} 1`+"\n"), body)
+	a.True(t, strings.Contains(body, `yarql_resolver_calls_total{type="testUser",field="name"} 2`+"\n"), body)
+}
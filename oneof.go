@@ -0,0 +1,77 @@
+package yarql
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// OneOf is embedded in a input struct to mark it as a oneOf input object
+// Exactly one field of a oneOf input object must be set to a non null value, this makes it possible to accept one of multiple inputs
+//
+// Example:
+//   type UserBy struct {
+//     yarql.OneOf
+//     ID    *string `gq:",id"`
+//     Email *string
+//   }
+//
+// All fields must be nullable and cannot have a default value
+// A input type can also be marked as oneOf using (*Schema).SetTypeOptions
+type OneOf struct{}
+
+var oneOfType = reflect.TypeOf(OneOf{})
+
+// hasOneOfMarker returns true if the struct t embeds OneOf
+func hasOneOfMarker(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == oneOfType {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOneOfInput returns an error if a field of the oneOf input object in is not nullable or has a default value
+func (s *Schema) checkOneOfInput(in *input) error {
+	for name, field := range in.structContent {
+		if _, isNonNull := s.inputToQLType(&field); isNonNull {
+			return fmt.Errorf("field %s of oneOf input %s must be nullable, use a pointer", name, in.structName)
+		}
+		if field.defaultValue != nil {
+			return fmt.Errorf("field %s of oneOf input %s cannot have a default value", name, in.structName)
+		}
+	}
+	return nil
+}
+
+// isNullInputValue returns true if the bound value of a nullable input is null
+func isNullInputValue(goValue reflect.Value, in *input) bool {
+	if in.isOptional {
+		optional := goValue.Field(optionalFieldIdx).Interface().(Optional)
+		return !optional.Set || optional.Null
+	}
+	if in.isJSON && goValue.Type() == jsonRawMessageType {
+		return goValue.IsNil() || bytes.Equal(goValue.Bytes(), []byte("null"))
+	}
+	switch goValue.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return goValue.IsNil()
+	default:
+		return false
+	}
+}
+
+// checkOneOfValue returns an error if not exactly one field of a oneOf input object is set to a non null value
+// keys is the amount of fields provided and key is the name of the last provided field
+func (ctx *Ctx) checkOneOfValue(goValue *reflect.Value, valueStructure *input, keys int, key string) bool {
+	if keys != 1 {
+		return ctx.errf("exactly one field must be set for oneOf input %s but got %d", valueStructure.structName, keys)
+	}
+	field := valueStructure.structContent[key]
+	if isNullInputValue(inputField(*goValue, &field), &field) {
+		return ctx.errf("field %s of oneOf input %s cannot be null", key, valueStructure.structName)
+	}
+	return false
+}
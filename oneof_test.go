@@ -0,0 +1,188 @@
+package yarql
+
+import (
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestOneOfUserBy struct {
+	OneOf
+	ID    *string `gq:"id,id"`
+	Email *string
+	Tags  []string
+	Name  OptionalString
+}
+
+type TestOneOfPet struct {
+	Cat *string
+	Dog *string
+}
+
+type TestOneOfQuery struct{}
+
+func (TestOneOfQuery) ResolveUser(args struct{ By TestOneOfUserBy }) string {
+	switch {
+	case args.By.ID != nil:
+		return "id=" + *args.By.ID
+	case args.By.Email != nil:
+		return "email=" + *args.By.Email
+	case args.By.Tags != nil:
+		return "tags=" + strings.Join(args.By.Tags, ",")
+	default:
+		return "name=" + args.By.Name.Value
+	}
+}
+
+func (TestOneOfQuery) ResolvePet(args struct{ Pet TestOneOfPet }) string {
+	if args.Pet.Cat != nil {
+		return "cat"
+	}
+	return "dog"
+}
+
+func TestOneOfInput(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"id", `{user(by: {id: "1"})}`, ``, `{"user":"id=1"}`},
+		{"email", `{user(by: {email: "a@b.c"})}`, ``, `{"user":"email=a@b.c"}`},
+		{"empty list", `{user(by: {tags: []})}`, ``, `{"user":"tags="}`},
+		{"optional field", `{user(by: {name: "foo"})}`, ``, `{"user":"name=foo"}`},
+		{"variable field", `query ($email: String!) {user(by: {email: $email})}`, `{"email": "x@y.z"}`, `{"user":"email=x@y.z"}`},
+		{"variable", `query ($by: TestOneOfUserBy!) {user(by: $by)}`, `{"by": {"id": "2"}}`, `{"user":"id=2"}`},
+		{"variable list", `query ($by: TestOneOfUserBy!) {user(by: $by)}`, `{"by": {"tags": ["a", "b"]}}`, `{"user":"tags=a,b"}`},
+		{"not oneOf", `{pet(pet: {cat: "tom", dog: null})}`, ``, `{"pet":"cat"}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			res := bytecodeParseAndExpectNoErrs(t, testCase.query, TestOneOfQuery{}, M{}, opts)
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestOneOfInputInvalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+	}{
+		{"no fields", `{user(by: {})}`, ``},
+		{"multiple fields", `{user(by: {id: "1", email: "a@b.c"})}`, ``},
+		{"null", `{user(by: {id: null})}`, ``},
+		{"null optional field", `{user(by: {name: null})}`, ``},
+		{"field and null", `{user(by: {id: "1", email: null})}`, ``},
+		{"null variable field", `query ($email: String) {user(by: {email: $email})}`, `{"email": null}`},
+		{"variable without fields", `query ($by: TestOneOfUserBy!) {user(by: $by)}`, `{"by": {}}`},
+		{"variable with multiple fields", `query ($by: TestOneOfUserBy!) {user(by: $by)}`, `{"by": {"id": "1", "email": "a@b.c"}}`},
+		{"variable with null", `query ($by: TestOneOfUserBy!) {user(by: $by)}`, `{"by": {"email": null}}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			_, errs := bytecodeParseAndExpectErrs(t, testCase.query, TestOneOfQuery{}, M{}, opts)
+			a.Equal(t, 1, len(errs))
+			a.True(t, strings.Contains(errs[0].Error(), "oneOf"), errs[0].Error())
+		})
+	}
+}
+
+func TestOneOfIntrospection(t *testing.T) {
+	query := `{
+		a: __type(name: "TestOneOfUserBy") {isOneOf}
+		b: __type(name: "TestOneOfPet") {isOneOf}
+		c: __type(name: "String") {isOneOf}
+	}`
+	res := bytecodeParseAndExpectNoErrs(t, query, TestOneOfQuery{}, M{})
+	a.Equal(t, `{"a":{"isOneOf":true},"b":{"isOneOf":false},"c":{"isOneOf":null}}`, res)
+}
+
+func TestOneOfTypeOptions(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestOneOfQuery{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	a.True(t, strings.Contains(sdl, "input TestOneOfUserBy @oneOf {\n"), sdl)
+	a.True(t, strings.Contains(sdl, "input TestOneOfPet {\n"), sdl)
+
+	// Mark a input type as oneOf after parsing
+	err = s.SetTypeOptions("TestOneOfPet", TypeOptions{OneOf: true})
+	a.NoError(t, err)
+	a.True(t, strings.Contains(s.SDL(), "input TestOneOfPet @oneOf {\n"))
+	errs := s.Resolve([]byte(`{pet(pet: {cat: "tom", dog: null})}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 1, len(errs))
+
+	err = s.SetTypeOptions("TestOneOfQuery", TypeOptions{OneOf: true})
+	a.Error(t, err)
+}
+
+func TestOneOfInvalid(t *testing.T) {
+	type NonNullField struct {
+		OneOf
+		ID string
+	}
+	type DefaultField struct {
+		OneOf
+		ID *string `gqDefault:"\"1\""`
+	}
+
+	err := NewSchema().Parse(struct {
+		A func(args struct{ Value NonNullField }) string
+	}{}, M{}, nil)
+	a.Error(t, err)
+
+	err = NewSchema().Parse(struct {
+		A func(args struct{ Value DefaultField }) string
+	}{}, M{}, nil)
+	a.Error(t, err)
+
+	err = NewSchema().Parse(struct {
+		A func(args struct {
+			OneOf
+			ID *string
+		}) string
+	}{}, M{}, nil)
+	a.Error(t, err)
+}
+
+func TestOneOfSDL(t *testing.T) {
+	sdl := `type Mutation
+type TestOneOfQuery {
+  pet(pet: TestOneOfPet!): String!
+  user(by: TestOneOfUserBy!): String!
+}
+input TestOneOfPet @oneOf {
+  cat: String
+  dog: String
+}
+input TestOneOfUserBy %s {
+  email: String
+  id: ID
+  name: String
+  tags: [String!]
+}
+schema {
+  query: TestOneOfQuery
+  mutation: Mutation
+}
+`
+	s := NewSchema()
+	err := s.ParseWithSDL([]byte(strings.Replace(sdl, "%s", "@oneOf", 1)), TestOneOfQuery{}, M{})
+	a.NoError(t, err)
+	errs := s.Resolve([]byte(`{pet(pet: {cat: "tom", dog: "rex"})}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 1, len(errs))
+
+	// The go type is marked as oneOf so the SDL must also mark it
+	err = NewSchema().ParseWithSDL([]byte(strings.Replace(sdl, "%s", "", 1)), TestOneOfQuery{}, M{})
+	a.Error(t, err)
+	a.True(t, strings.Contains(err.Error(), "oneOf"), err.Error())
+}
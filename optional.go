@@ -0,0 +1,78 @@
+package yarql
+
+import (
+	"reflect"
+	"time"
+)
+
+// Optional is embedded in input structs to tell an omitted input apart from an input explicitly set to null
+// The struct must only contain the embedded Optional followed by a Value field:
+//
+//   type OptionalUser struct {
+//     yarql.Optional
+//     Value User
+//   }
+//
+// In the schema the input is the nullable version of the Value type
+type Optional struct {
+	// Set is true if the input was provided, also if the provided value was null
+	Set bool
+	// Null is true if the input was explicitly set to null
+	Null bool
+}
+
+// OptionalString is a optional String input
+type OptionalString struct {
+	Optional
+	Value string
+}
+
+// OptionalInt is a optional Int input
+type OptionalInt struct {
+	Optional
+	Value int
+}
+
+// OptionalFloat is a optional Float input
+type OptionalFloat struct {
+	Optional
+	Value float64
+}
+
+// OptionalBool is a optional Boolean input
+type OptionalBool struct {
+	Optional
+	Value bool
+}
+
+// OptionalTime is a optional Time input
+type OptionalTime struct {
+	Optional
+	Value time.Time
+}
+
+var optionalType = reflect.TypeOf(Optional{})
+
+const (
+	optionalFieldIdx      = 0
+	optionalValueFieldIdx = 1
+)
+
+// isOptionalType returns true if t is a struct with an embedded Optional followed by a Value field
+func isOptionalType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return false
+	}
+	optionalField := t.Field(optionalFieldIdx)
+	valueField := t.Field(optionalValueFieldIdx)
+	return optionalField.Anonymous && optionalField.Type == optionalType && valueField.Name == "Value"
+}
+
+// bindOptionalValue marks a Optional input as set and binds the value using bind if the value is not null
+func bindOptionalValue(goValue *reflect.Value, valueStructure *input, isNull bool, bind func(goValue *reflect.Value, input *input) bool) bool {
+	valueField := goValue.Field(optionalValueFieldIdx)
+	// An explicit value also overwrites the default value
+	valueField.Set(reflect.Zero(valueField.Type()))
+	goValue.Field(optionalFieldIdx).Set(reflect.ValueOf(Optional{Set: true, Null: isNull}))
+	return bind(&valueField, valueStructure.elem)
+}
@@ -0,0 +1,130 @@
+package yarql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestOptionalTags struct {
+	Optional
+	Value []string
+}
+
+type TestOptionalPatch struct {
+	Title OptionalString
+	Done  OptionalBool
+	Count OptionalInt `gqDefault:"5"`
+}
+
+type TestOptionalQuery struct{}
+
+func describeOptional(name string, optional Optional, value interface{}) string {
+	if !optional.Set {
+		return name + "=unset"
+	}
+	if optional.Null {
+		return name + "=null"
+	}
+	return fmt.Sprintf("%s=%v", name, value)
+}
+
+func (TestOptionalQuery) ResolveDescribe(args struct {
+	Name  OptionalString
+	Tags  TestOptionalTags
+	Patch *TestOptionalPatch
+}) string {
+	res := []string{
+		describeOptional("name", args.Name.Optional, args.Name.Value),
+		describeOptional("tags", args.Tags.Optional, args.Tags.Value),
+	}
+	if args.Patch != nil {
+		res = append(
+			res,
+			describeOptional("title", args.Patch.Title.Optional, args.Patch.Title.Value),
+			describeOptional("done", args.Patch.Done.Optional, args.Patch.Done.Value),
+			describeOptional("count", args.Patch.Count.Optional, args.Patch.Count.Value),
+		)
+	}
+	return strings.Join(res, " ")
+}
+
+func (TestOptionalQuery) ResolveId(args struct {
+	ID OptionalInt `gq:"id,id"`
+}) string {
+	return describeOptional("id", args.ID.Optional, args.ID.Value)
+}
+
+func TestOptionalInput(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"unset", `{describe}`, ``, `{"describe":"name=unset tags=unset"}`},
+		{"null", `{describe(name: null, tags: null)}`, ``, `{"describe":"name=null tags=null"}`},
+		{"value", `{describe(name: "foo", tags: ["a", "b"])}`, ``, `{"describe":"name=foo tags=[a b]"}`},
+		{"input object", `{describe(patch: {title: null, done: false})}`, ``, `{"describe":"name=unset tags=unset title=null done=false count=5"}`},
+		{"input object null overwrites default", `{describe(patch: {count: null})}`, ``, `{"describe":"name=unset tags=unset title=unset done=unset count=null"}`},
+		{"id", `{id(id: "10")}`, ``, `{"id":"id=10"}`},
+		{"unset variable", `query ($name: String) {describe(name: $name)}`, ``, `{"describe":"name=unset tags=unset"}`},
+		{"null variable", `query ($name: String) {describe(name: $name)}`, `{"name": null}`, `{"describe":"name=null tags=unset"}`},
+		{"variable", `query ($name: String) {describe(name: $name)}`, `{"name": "bar"}`, `{"describe":"name=bar tags=unset"}`},
+		{"variable default", `query ($name: String = "baz") {describe(name: $name)}`, ``, `{"describe":"name=baz tags=unset"}`},
+		{"list variable", `query ($tags: [String!]) {describe(tags: $tags)}`, `{"tags": ["c"]}`, `{"describe":"name=unset tags=[c]"}`},
+		{"input object variable", `query ($patch: TestOptionalPatch) {describe(patch: $patch)}`, `{"patch": {"title": null, "count": 1}}`, `{"describe":"name=unset tags=unset title=null done=unset count=1"}`},
+		{"id variable", `query ($id: ID) {id(id: $id)}`, `{"id": "2"}`, `{"id":"id=2"}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			res := bytecodeParseAndExpectNoErrs(t, testCase.query, TestOptionalQuery{}, M{}, opts)
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestOptionalInputInvalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+	}{
+		{"wrong type", `{describe(name: 10)}`, ``},
+		{"wrong list item type", `{describe(tags: [1])}`, ``},
+		{"wrong variable type", `query ($name: Int) {describe(name: $name)}`, `{"name": 1}`},
+		{"wrong variable value", `query ($name: String) {describe(name: $name)}`, `{"name": 1}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			_, errs := bytecodeParseAndExpectErrs(t, testCase.query, TestOptionalQuery{}, M{}, opts)
+			a.Equal(t, 1, len(errs))
+		})
+	}
+}
+
+func TestOptionalSchema(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestOptionalQuery{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	expected := []string{
+		"  describe(name: String, patch: TestOptionalPatch, tags: [String!]): String!\n",
+		"  id(id: ID): String!\n",
+		"  count: Int = 5\n",
+		"  done: Boolean\n",
+		"  title: String\n",
+	}
+	for _, part := range expected {
+		a.True(t, strings.Contains(sdl, part), part+"\n"+sdl)
+	}
+	a.False(t, strings.Contains(sdl, "OptionalString"))
+	a.False(t, strings.Contains(sdl, "TestOptionalTags"))
+}
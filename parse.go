@@ -2,6 +2,7 @@ package yarql
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"hash/fnv"
@@ -10,6 +11,10 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/mjarkk/yarql/bytecode"
+	"github.com/mjarkk/yarql/helpers"
+	"github.com/valyala/fastjson"
 )
 
 // AttrIsID can be added to a method response to make it a ID field
@@ -55,6 +60,16 @@ type Schema struct {
 	MaxDepth          uint8 // Default 255
 	definedEnums      []enum
 	definedDirectives map[DirectiveLocation][]*Directive
+	registry          *typeRegistry // types registered using (*Schema).Implements and (*Schema).TypeRename
+	definedEntities   []*Entity
+	definedScalars    map[string]qlType
+	timeLayout        string
+	strictInt         bool
+	int64AsLong       bool
+	longScalarName    string
+	resolveType       ResolveTypeFunc
+	federation        *federation // only set if federation is enabled
+	usesJSON          bool        // the JSON scalar is only part of the schema if it's used
 	ctx               *Ctx
 
 	// Zero alloc variables
@@ -78,6 +93,9 @@ const (
 	valueTypeTime
 	valueTypeInterfaceRef
 	valueTypeInterface
+	valueTypeJSON
+	valueTypeText
+	valueTypeBase64
 )
 
 // TODO Maybe add a pointer to the opj if valueType == valueTypeObjRef || valueType == valueTypeInterfaceRef
@@ -91,7 +109,16 @@ type obj struct {
 	goPkgPath     string
 	qlFieldName   []byte
 	hidden        bool
+	isServiceSDL  bool // the federation _service field, rejected if ResolveOptions.DisableServiceSDL is set
 	isID          bool
+	isLong        bool // Value type == valueTypeData, a integer exposed as Long
+
+	// Documentation of the type or field, shown in introspection and the SDL
+	description       string
+	deprecationReason *string // not nil if the field is deprecated
+
+	// The type of the field as defined in the SDL, only set if parsed using ParseWithSDL
+	sdlType *bytecode.TypeReference
 
 	// Value type == valueTypeObj || valueTypeInterface
 	objContents map[uint32]*obj
@@ -101,21 +128,47 @@ type obj struct {
 
 	// Value is inside struct
 	structFieldIdx int
+	goFieldName    string
+	embeddedPath   []int // the field indexes of the embedded structs a promoted field or method is defined in
 
 	// Value type == valueTypeArray || type == valueTypePtr
 	innerContent *obj
 
-	// Value type == valueTypeData
+	// Value type == valueTypeArray and the go value is a map, or the obj is a generated map entry type
+	// The struct with a Key and Value field used to resolve the map entries
+	mapEntryType reflect.Type
+
+	// Value type == valueTypeArray and the go value is a slice of relay.Edge, or the obj is a generated connection or edge type
+	// The struct with a Cursor and Node field used to resolve the edges
+	edgeType reflect.Type
+
+	// Value type == valueTypeData, valueTypeArray or valueTypeBase64
 	dataValueType reflect.Kind
 
+	// Value type == valueTypeText, the scalar the text is exposed as, empty for String
+	scalarName string
+
+	// Value type == valueTypeTime, the layout set using gqTimeLayout
+	timeLayout string
+
 	// Value type == valueTypeMethod
-	method *objMethod
+	method  *objMethod
+	timeout time.Duration // max duration the method may take, 0 = no timeout
 
 	// Value type == valueTypeEnum
 	enumTypeIndex int
 
 	// Value type == valueTypeInterface || valueTypeObj
 	implementations []*obj
+
+	// Value type == valueTypeInterface, the interface is a union without fields like _Entity
+	isUnion bool
+
+	// Value type == valueTypeInterface, the interfaces implemented by this interface
+	interfaces []*obj
+
+	// Directives applied to the type or field like @key(fields: "id"), only used by federation
+	appliedDirectives []string
 }
 
 func getObjKey(key []byte) uint32 {
@@ -155,6 +208,9 @@ type objMethod struct {
 	goFunctionName string
 	goType         reflect.Type
 
+	// The type method has a pointer receiver, the index of the method is the index in the method set of the pointer type
+	pointerReceiver bool
+
 	ins        []baseInput             // The real function inputs
 	inFields   map[string]referToInput // Contains all the fields of all the ins
 	checkedIns bool                    // are the ins checked yet
@@ -162,6 +218,8 @@ type objMethod struct {
 	outNr      int
 	outType    obj
 	errorOutNr *int
+
+	hasDefaults bool // one or more of the inFields has a default value
 }
 
 type inputMap map[string]*input
@@ -180,22 +238,50 @@ type input struct {
 	isID          bool
 	isFile        bool
 	isTime        bool
+	isLong        bool // a integer exposed as Long
+	isAny         bool // a federation Representation
+	isJSON        bool // a interface{} or json.RawMessage
+	isText        bool // implements encoding.TextUnmarshaler
+	isBase64      bool // a []byte or [N]byte tagged with gq:",base64"
+	isOptional    bool // a struct embedding Optional, elem is the Value field
+
+	// isText, the scalar the text is exposed as, empty for String
+	scalarName string
 
-	goFieldIdx  int
-	gqFieldName string
+	// isTime, the layout set using gqTimeLayout
+	timeLayout string
+
+	goFieldIdx   int
+	gqFieldName  string
+	embeddedPath []int // the field indexes of the embedded structs a promoted input field is defined in
+
+	// Documentation of the input type or input field, shown in introspection and the SDL
+	description string
+
+	// The default value of an input field or argument
+	// defaultValue is the value as graphql literal, defaultJSON is used to bind the value
+	defaultValue *string
+	defaultJSON  *fastjson.Value
+
+	// The type of the argument or input field as defined in the SDL, only set if parsed using ParseWithSDL
+	sdlType *bytecode.TypeReference
 
 	// kind == Slice, Array or Ptr
 	elem *input
 
 	// kind == struct
 	isStructPointers bool
+	isOneOf          bool // marked as oneOf input object, exactly one field must be set
 	structName       string
 	structContent    map[string]input
+	hasDefaults      bool         // one or more of the structContent fields has a default value
+	goType           reflect.Type // only set on the entries of Schema.inTypes and on maps, where it's the entry struct the list items are bound to
 }
 
 type baseInput struct {
-	isCtx  bool
-	goType *reflect.Type
+	isCtx     bool
+	isContext bool // is a context.Context
+	goType    *reflect.Type
 }
 
 // SchemaOptions are options for creating a new schema
@@ -204,13 +290,46 @@ type SchemaOptions struct {
 	noMethodEqualToQueryChecks bool
 
 	SkipGraphqlTypesInjection bool
+
+	// Federation makes the schema a apollo federation v2 subgraph
+	// This adds the _service and _entities fields to the query root, entities can be registered using (*Schema).RegisterEntity
+	Federation bool
+
+	// TimeLayout is the layout used to format and parse time.Time values, see time.Layout
+	// Layout constants of the time package can be used by name, for example RFC3339
+	// Defaults to 2006-01-02T15:04:05.000Z where inputs may also be formatted as RFC 3339
+	TimeLayout string
+
+	// StrictInt makes Int outputs and inputs outside of the 32-bit range of a graphql Int result in an error
+	StrictInt bool
+
+	// Int64AsLong exposes int64 and uint64 values as the Long scalar, serialized as string
+	// Single fields can also be exposed as Long using the gq:",long" struct tag
+	Int64AsLong bool
+
+	// LongScalarName is the name of the Long scalar, for example BigInt
+	// Defaults to Long
+	LongScalarName string
+
+	// ResolveType is called for interface values that can't be matched by go type to a type implementing the interface
+	ResolveType ResolveTypeFunc
 }
 
 type parseCtx struct {
 	schema             *Schema
+	registry           *typeRegistry // see (*parseCtx).typeRegistry
 	unknownTypesCount  int
 	unknownInputsCount int
 	parsedMethods      []*objMethod
+	defaultValues      []defaultValueCheck
+}
+
+// defaultValueCheck is a default value that is checked after parsing the schema
+// This can only be done after parsing as all enums and input types need to be known
+type defaultValueCheck struct {
+	fieldName string
+	goType    reflect.Type
+	input     input
 }
 
 // NewSchema creates a new schema wherevia you can define the graphql types and make queries
@@ -223,6 +342,7 @@ func NewSchema() *Schema {
 		graphqlObjFields:  map[string][]qlField{},
 		definedEnums:      []enum{},
 		definedDirectives: map[DirectiveLocation][]*Directive{},
+		registry:          newTypeRegistry(),
 		Result:            make([]byte, 16384),
 	}
 
@@ -300,6 +420,26 @@ func (s *Schema) Parse(queries interface{}, methods interface{}, options *Schema
 		parsedMethods: []*objMethod{},
 	}
 
+	s.longScalarName = defaultLongScalarName
+	if options != nil {
+		s.timeLayout = resolveTimeLayout(options.TimeLayout)
+		s.strictInt = options.StrictInt
+		s.int64AsLong = options.Int64AsLong
+		s.resolveType = options.ResolveType
+		if options.LongScalarName != "" {
+			if builtinScalars[options.LongScalarName] || sdlKnownScalar(options.LongScalarName) || builtinTextScalars[options.LongScalarName].Name != nil {
+				return fmt.Errorf("cannot use %s as LongScalarName, a scalar with the same name already exists", options.LongScalarName)
+			}
+			s.longScalarName = options.LongScalarName
+		}
+	}
+
+	if options != nil && options.Federation {
+		s.federation = &federation{entities: map[string]*Entity{}}
+	} else if len(s.definedEntities) > 0 {
+		return errors.New("entities can only be registered if the Federation schema option is set")
+	}
+
 	obj, err := ctx.check(reflect.TypeOf(queries), false)
 	if err != nil {
 		return err
@@ -330,6 +470,13 @@ func (s *Schema) Parse(queries interface{}, methods interface{}, options *Schema
 		s.injectQLTypes(ctx)
 	}
 
+	if s.federation != nil {
+		err = s.injectFederation(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, method := range ctx.parsedMethods {
 		err = ctx.checkFunctionIns(method)
 		if err != nil {
@@ -350,12 +497,46 @@ func (s *Schema) Parse(queries interface{}, methods interface{}, options *Schema
 		}
 	}
 
+	err = s.checkScalarNames()
+	if err != nil {
+		return err
+	}
+
 	s.ctx = newCtx(s)
+
+	err = ctx.checkDefaultValues()
+	if err != nil {
+		return err
+	}
+
 	s.parsed = true
 
 	return nil
 }
 
+// checkDefaultValues binds all default values to their go type to make sure they are valid
+func (c *parseCtx) checkDefaultValues() error {
+	for _, check := range c.defaultValues {
+		err := c.schema.checkDefaultValue(check)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) checkDefaultValue(check defaultValueCheck) error {
+	ctx := s.ctx
+	goValue := reflect.New(check.goType).Elem()
+	ctx.bindJSONToValue(&goValue, &check.input, check.input.defaultJSON)
+	if len(ctx.query.Errors) > 0 {
+		err := ctx.query.Errors[0]
+		ctx.query.Errors = ctx.query.Errors[:0]
+		return fmt.Errorf("%s: invalid default value %s, %s", check.fieldName, *check.input.defaultValue, err.Error())
+	}
+	return nil
+}
+
 func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 	res := obj{
 		typeNameBytes: []byte(t.Name()),
@@ -369,17 +550,40 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 		return &res, nil
 	}
 
+	if isJSONType(t) {
+		if hasIDTag {
+			return nil, errors.New("JSON values cannot have ID attribute")
+		}
+		c.schema.usesJSON = true
+		res.valueType = valueTypeJSON
+		return &res, nil
+	}
+
+	if _, enum := c.schema.getEnum(t); enum == nil && isTextMarshaler(t) {
+		res.valueType = valueTypeText
+		res.scalarName = builtinTextScalarTypes[t]
+		if hasIDTag {
+			res.scalarName = "ID"
+		} else if res.scalarName != "" {
+			err := c.defineScalar(res.scalarName)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &res, nil
+	}
+
 	switch t.Kind() {
 	case reflect.Struct:
+		if isRelayConnection(t) {
+			return c.checkConnection(t, hasIDTag)
+		}
 		if hasIDTag {
 			return nil, errors.New("structs cannot have ID attribute")
 		}
 		if res.typeName != "" {
-			newName, ok := renamedTypes[res.typeName]
-			if ok {
-				res.typeName = newName
-				res.typeNameBytes = []byte(newName)
-			}
+			res.typeName = c.typeRegistry().typeName(t)
+			res.typeNameBytes = []byte(res.typeName)
 
 			v, ok := c.schema.types.Get(res.typeName)
 			if ok {
@@ -391,7 +595,8 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 				return &res, nil
 			}
 
-			implementations := structImplementsMap[t.Name()]
+			registry := c.typeRegistry()
+			implementations := registry.withParentInterfaces(registry.structInterfaces[typeKey(t)])
 			for _, implementation := range implementations {
 				impl, err := c.check(implementation, false)
 				if err != nil {
@@ -411,13 +616,17 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 		typesInner := c.schema.types
 		typesInner[res.typeName] = &res
 		c.schema.types = typesInner
-		c.checkStructFieldRecursive(t, &res)
+		err := c.checkStructFieldRecursive(t, &res)
+		if err != nil {
+			return nil, err
+		}
 	case reflect.Array, reflect.Slice, reflect.Ptr:
 		isPtr := t.Kind() == reflect.Ptr
 		if isPtr {
 			res.valueType = valueTypePtr
 		} else {
 			res.valueType = valueTypeArray
+			res.dataValueType = t.Kind()
 		}
 
 		obj, err := c.check(t.Elem(), hasIDTag && isPtr)
@@ -433,11 +642,9 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 			return nil, errors.New("inline interfaces not allowed")
 		}
 
-		newName, ok := renamedTypes[res.typeName]
-		if ok {
-			res.typeName = newName
-			res.typeNameBytes = []byte(newName)
-		}
+		registry := c.typeRegistry()
+		res.typeName = registry.typeName(t)
+		res.typeNameBytes = []byte(res.typeName)
 
 		v, ok := c.schema.interfaces.Get(res.typeName)
 		if ok {
@@ -463,8 +670,24 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 			methodPkgName = "inline interface"
 		}
 
-		typesThatImplementInterface, ok := implementationMap[t.Name()]
-		if !ok {
+		for _, parent := range registry.parentInterfaces(t) {
+			obj, err := c.check(parent, false)
+			if err != nil {
+				return nil, err
+			}
+			res.interfaces = append(res.interfaces, obj)
+		}
+
+		// The possible types also contain the implementations of the interfaces implementing this interface
+		typesThatImplementInterface := append([]reflect.Type{}, registry.implementations[typeKey(t)]...)
+		for _, child := range registry.childInterfaces(t) {
+			for _, childImplementation := range registry.implementations[typeKey(child)] {
+				if !containsType(typesThatImplementInterface, childImplementation) {
+					typesThatImplementInterface = append(typesThatImplementInterface, childImplementation)
+				}
+			}
+		}
+		if len(typesThatImplementInterface) == 0 {
 			return nil, errors.New("cannot register a interface without explicit implementations")
 		}
 		for _, interfaceType := range typesThatImplementInterface {
@@ -474,7 +697,7 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 			if interfaceType.Name() == "" {
 				return nil, fmt.Errorf("inline struct not allowed in (%s).Is(types...)", methodPkgName)
 			}
-			if !interfaceType.Implements(t) {
+			if !implementsInterface(interfaceType, t) {
 				return nil, fmt.Errorf("(%s).Is(types...): %s.%s does not implement %s", methodPkgName, interfaceType.PkgPath(), interfaceType.Name(), methodPkgName)
 			}
 
@@ -485,7 +708,9 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 
 			res.implementations = append(res.implementations, obj)
 		}
-	case reflect.Func, reflect.Map, reflect.Chan, reflect.Invalid, reflect.Uintptr, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+	case reflect.Map:
+		return c.checkMap(t, hasIDTag)
+	case reflect.Func, reflect.Chan, reflect.Invalid, reflect.Uintptr, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
 		return nil, fmt.Errorf("unsupported value type %s", t.Kind().String())
 	default:
 		enumIndex, enum := c.schema.getEnum(t)
@@ -502,29 +727,47 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 				if err != nil {
 					return nil, err
 				}
+			} else if c.schema.int64AsLong && isInt64Kind(res.dataValueType) {
+				res.isLong = true
+				c.defineLongScalar()
 			}
 		}
 	}
 
 	if res.valueType == valueTypeObj || res.valueType == valueTypeInterface {
-		for i := 0; i < t.NumMethod(); i++ {
-			method := t.Method(i)
-			methodObj, name, isID, err := c.checkFunction(method.Name, method.Type, true, false)
-			if err != nil {
-				return nil, err
-			} else if methodObj == nil {
-				continue
-			}
+		methodSets := []reflect.Type{t}
+		if t.Kind() == reflect.Struct {
+			// Methods with a pointer receiver are only part of the method set of the pointer type
+			methodSets = append(methodSets, reflect.PtrTo(t))
+		}
+		for _, methodSet := range methodSets {
+			pointerReceiver := methodSet != t
+			for i := 0; i < methodSet.NumMethod(); i++ {
+				method := methodSet.Method(i)
+				if _, isValueMethod := t.MethodByName(method.Name); pointerReceiver && isValueMethod {
+					// The method set of the pointer type also contains the methods with a value receiver
+					continue
+				}
 
-			qlFieldName := []byte(name)
-			res.objContents[getObjKey(qlFieldName)] = &obj{
-				qlFieldName:    qlFieldName,
-				valueType:      valueTypeMethod,
-				goPkgPath:      method.PkgPath,
-				goTypeName:     method.Name,
-				structFieldIdx: i,
-				method:         methodObj,
-				isID:           isID,
+				methodObj, name, isID, err := c.checkFunction(method.Name, method.Type, true, false)
+				if err != nil {
+					return nil, err
+				} else if methodObj == nil {
+					continue
+				}
+				methodObj.pointerReceiver = pointerReceiver
+
+				qlFieldName := []byte(name)
+				res.objContents[getObjKey(qlFieldName)] = &obj{
+					qlFieldName:    qlFieldName,
+					valueType:      valueTypeMethod,
+					goPkgPath:      method.PkgPath,
+					goTypeName:     method.Name,
+					structFieldIdx: i,
+					embeddedPath:   promotedMethodPath(t, method.Name),
+					method:         methodObj,
+					isID:           isID,
+				}
 			}
 		}
 
@@ -539,16 +782,12 @@ func (c *parseCtx) check(t reflect.Type, hasIDTag bool) (*obj, error) {
 	return &res, nil
 }
 
-func (c *parseCtx) checkStructFieldRecursive(t reflect.Type, res *obj) {
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		if field.Anonymous {
-			c.checkStructFieldRecursive(field.Type, res)
-		}
-
-		customName, obj, err := c.checkStructField(field, i)
+func (c *parseCtx) checkStructFieldRecursive(t reflect.Type, res *obj) error {
+	for _, structField := range structFields(t) {
+		field := structField.field
+		customName, obj, err := c.checkStructField(field, structField.idx)
 		if err != nil {
-			return
+			return err
 		}
 		if obj != nil {
 			name := formatGoNameToQL(field.Name)
@@ -556,31 +795,64 @@ func (c *parseCtx) checkStructFieldRecursive(t reflect.Type, res *obj) {
 				name = *customName
 			}
 			obj.qlFieldName = []byte(name)
+			obj.embeddedPath = structField.embeddedPath
 
 			res.objContents[getObjKey(obj.qlFieldName)] = obj
 		}
 	}
+	return nil
 }
 
 func (c *parseCtx) checkStructField(field reflect.StructField, idx int) (customName *string, obj *obj, err error) {
-	if field.Anonymous {
+	tag, err := parseFieldTagGQ(&field)
+	if tag.ignore || err != nil {
+		return nil, nil, err
+	}
+	if field.Anonymous && tag.name == nil {
+		// Only embedded fields with a custom name are handled as field, the fields of other embedded structs are promoted
 		return nil, nil, nil
 	}
+	customName = tag.name
 
-	var ignore, isID bool
-	customName, ignore, isID, err = parseFieldTagGQ(&field)
-	if ignore || err != nil {
-		return nil, nil, err
+	if tag.defaultValue != nil {
+		return nil, nil, fmt.Errorf("%s: default values can only be set on input fields", field.Name)
+	}
+	if len(tag.federationDirectives) > 0 && c.schema.federation == nil {
+		return nil, nil, fmt.Errorf("%s: federation directives can only be used if the Federation schema option is set", field.Name)
 	}
 
 	if field.Type.Kind() == reflect.Func {
-		obj, err = c.checkStructFieldFunc(field.Name, field.Type, isID, idx)
+		if tag.scalar != "" || tag.base64 || tag.long {
+			return nil, nil, fmt.Errorf("%s: scalar, base64 and long cannot be set on func fields", field.Name)
+		}
+		obj, err = c.checkStructFieldFunc(field.Name, field.Type, tag.isID, idx)
+		if obj != nil {
+			obj.timeout = tag.timeout
+		}
+	} else if tag.timeout != 0 {
+		return nil, nil, fmt.Errorf("%s: timeout can only be set on func fields", field.Name)
 	} else {
-		obj, err = c.check(field.Type, isID)
+		obj, err = c.check(field.Type, tag.isID)
+		if err == nil {
+			err = c.applyScalarTag(obj, tag)
+			if err == nil {
+				err = applyTimeLayoutTag(obj, tag)
+			}
+			if err == nil {
+				err = c.applyLongTag(obj, tag)
+			}
+			if err != nil {
+				err = fmt.Errorf("%s: %s", field.Name, err.Error())
+			}
+		}
 	}
 
 	if obj != nil {
 		obj.structFieldIdx = idx
+		obj.goFieldName = field.Name
+		obj.description = tag.description
+		obj.deprecationReason = tag.deprecationReason
+		obj.appliedDirectives = tag.federationDirectives
 	}
 	return
 }
@@ -602,6 +874,7 @@ func (c *parseCtx) checkStructFieldFunc(fieldName string, goType reflect.Type, h
 }
 
 var ctxType = reflect.TypeOf(Ctx{})
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 
 func isCtx(t reflect.Type) bool {
 	return t.Kind() == reflect.Struct && ctxType.Name() == t.Name() && ctxType.PkgPath() == t.PkgPath()
@@ -612,42 +885,158 @@ func (c *parseCtx) checkFunctionInputStruct(field *reflect.StructField, idx int)
 		return fmt.Errorf("%s, struct field: %s", err.Error(), field.Name)
 	}
 
-	if field.Anonymous {
-		// skip field
-		return res, true, nil
-	}
-
-	newName, ignore, isID, err := parseFieldTagGQ(field)
-	if ignore {
+	tag, err := parseFieldTagGQ(field)
+	if tag.ignore {
 		// skip field
 		return res, true, nil
 	}
 	if err != nil {
 		return res, false, wrapErr(err)
 	}
+	if field.Anonymous && tag.name == nil {
+		// skip field, the fields of embedded structs are promoted
+		return res, true, nil
+	}
+	if tag.timeout != 0 {
+		return res, false, wrapErr(errors.New("timeout cannot be set on input fields"))
+	}
+	if tag.deprecationReason != nil {
+		return res, false, wrapErr(errors.New("input fields cannot be deprecated"))
+	}
+	if len(tag.federationDirectives) > 0 {
+		return res, false, wrapErr(errors.New("federation directives cannot be set on input fields"))
+	}
 
 	qlFieldName := formatGoNameToQL(field.Name)
-	if newName != nil {
-		qlFieldName = *newName
+	if tag.name != nil {
+		qlFieldName = *tag.name
 	}
 
-	res, err = c.checkFunctionInput(field.Type, isID)
+	res, err = c.checkFunctionInput(field.Type, tag.isID)
+	if err != nil {
+		return input{}, false, wrapErr(err)
+	}
+	err = c.applyInputScalarTag(&res, tag)
+	if err == nil {
+		err = applyInputTimeLayoutTag(&res, tag)
+	}
+	if err == nil {
+		err = c.applyInputLongTag(&res, tag)
+	}
 	if err != nil {
 		return input{}, false, wrapErr(err)
 	}
 
 	res.goFieldIdx = idx
 	res.gqFieldName = qlFieldName
+	res.description = tag.description
+
+	if tag.defaultValue != nil {
+		literal, defaultJSON, err := parseDefaultValue(*tag.defaultValue)
+		if err != nil {
+			return input{}, false, wrapErr(err)
+		}
+		res.defaultValue = &literal
+		res.defaultJSON = defaultJSON
+
+		c.defaultValues = append(c.defaultValues, defaultValueCheck{
+			fieldName: field.Name,
+			goType:    field.Type,
+			input:     res,
+		})
+	}
 
 	return
 }
 
+// parseDefaultValue parses a default value written as graphql literal
+// returns the formatted literal and the value as JSON
+func parseDefaultValue(value string) (literal string, jsonValue *fastjson.Value, err error) {
+	parsedValue, err := bytecode.ParseValue([]byte(value))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid default value %s, %s", value, err.Error())
+	}
+
+	jsonValue, err = fastjson.ParseBytes(graphqlValueToJSON(*parsedValue, nil))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid default value %s, %s", value, err.Error())
+	}
+	return parsedValue.String(), jsonValue, nil
+}
+
+// graphqlValueToJSON converts a graphql value into JSON, enum values are converted into strings
+func graphqlValueToJSON(value bytecode.Value, res []byte) []byte {
+	switch value.Kind {
+	case bytecode.ValueString, bytecode.ValueEnum:
+		helpers.StringToJSON(value.Value, &res)
+	case bytecode.ValueNull:
+		res = append(res, "null"...)
+	case bytecode.ValueList:
+		res = append(res, '[')
+		for idx, item := range value.List {
+			if idx > 0 {
+				res = append(res, ',')
+			}
+			res = graphqlValueToJSON(item, res)
+		}
+		res = append(res, ']')
+	case bytecode.ValueObject:
+		res = append(res, '{')
+		for idx, field := range value.Fields {
+			if idx > 0 {
+				res = append(res, ',')
+			}
+			helpers.StringToJSON(field.Name, &res)
+			res = append(res, ':')
+			res = graphqlValueToJSON(field.Value, res)
+		}
+		res = append(res, '}')
+	default:
+		// Int, Float and Boolean values are equal in graphql and JSON
+		res = append(res, value.Value...)
+	}
+	return res
+}
+
 func (c *parseCtx) checkFunctionInput(t reflect.Type, hasIDTag bool) (input, error) {
 	kind := t.Kind()
 	res := input{
 		kind: kind,
 	}
 
+	if isJSONType(t) {
+		if hasIDTag {
+			return res, errors.New("JSON values cannot have ID attribute")
+		}
+		c.schema.usesJSON = true
+		res.isJSON = true
+		return res, nil
+	}
+
+	if isOptionalType(t) {
+		value, err := c.checkFunctionInput(t.Field(optionalValueFieldIdx).Type, hasIDTag)
+		if err != nil {
+			return res, err
+		}
+		res.isOptional = true
+		res.elem = &value
+		return res, nil
+	}
+
+	if _, enum := c.schema.getEnum(t); enum == nil && isTextUnmarshaler(t) {
+		res.isText = true
+		res.scalarName = builtinTextScalarTypes[t]
+		if hasIDTag {
+			res.scalarName = "ID"
+		} else if res.scalarName != "" {
+			err := c.defineScalar(res.scalarName)
+			if err != nil {
+				return res, err
+			}
+		}
+		return res, nil
+	}
+
 	switch kind {
 	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
 		enumIndex, enum := c.schema.getEnum(t)
@@ -660,6 +1049,9 @@ func (c *parseCtx) checkFunctionInput(t reflect.Type, hasIDTag bool) (input, err
 			if err != nil {
 				return res, err
 			}
+		} else if c.schema.int64AsLong && isInt64Kind(kind) {
+			res.isLong = true
+			c.defineLongScalar()
 		}
 	case reflect.Ptr:
 		if t.AssignableTo(reflect.TypeOf(&multipart.FileHeader{})) {
@@ -693,10 +1085,7 @@ func (c *parseCtx) checkFunctionInput(t reflect.Type, hasIDTag bool) (input, err
 			c.unknownInputsCount++
 			structName = "__UnknownInput" + strconv.Itoa(c.unknownInputsCount)
 		} else {
-			newStructName, ok := renamedTypes[structName]
-			if ok {
-				structName = newStructName
-			}
+			structName = c.typeRegistry().typeName(t)
 			_, equalTypeExist := c.schema.types[structName]
 			if equalTypeExist {
 				// types and inputs with the same name are not allowed in graphql, add __input as suffix
@@ -712,16 +1101,31 @@ func (c *parseCtx) checkFunctionInput(t reflect.Type, hasIDTag bool) (input, err
 
 			res.structName = structName
 			res.structContent = map[string]input{}
-			for i := 0; i < t.NumField(); i++ {
-				field := t.Field(i)
-				input, skip, err := c.checkFunctionInputStruct(&field, i)
+			res.goType = t
+			for _, structField := range structFields(t) {
+				input, skip, err := c.checkFunctionInputStruct(&structField.field, structField.idx)
 				if skip {
 					continue
 				}
+				if err == nil {
+					err = checkEmbeddedInputPath(t, structField.embeddedPath)
+				}
 				if err != nil {
 					return res, err
 				}
+				input.embeddedPath = structField.embeddedPath
 				res.structContent[input.gqFieldName] = input
+				if input.defaultJSON != nil {
+					res.hasDefaults = true
+				}
+			}
+
+			if hasOneOfMarker(t) {
+				res.isOneOf = true
+				err := c.schema.checkOneOfInput(&res)
+				if err != nil {
+					return res, err
+				}
 			}
 		}
 
@@ -730,7 +1134,16 @@ func (c *parseCtx) checkFunctionInput(t reflect.Type, hasIDTag bool) (input, err
 			structName:       structName,
 			isStructPointers: true,
 		}, nil
-	case reflect.Map, reflect.Func:
+	case reflect.Map:
+		if t == representationType && c.schema.federation != nil {
+			res.isAny = true
+			return res, nil
+		}
+		if hasIDTag {
+			return res, errors.New("maps cannot have ID attribute")
+		}
+		return c.checkMapInput(t)
+	case reflect.Func:
 		// TODO: maybe we can do something with these
 		fallthrough
 	default:
@@ -842,24 +1255,35 @@ func (c *parseCtx) checkFunctionIns(method *objMethod) error {
 		typeKind := goType.Kind()
 		if typeKind == reflect.Ptr && isCtx(goType.Elem()) {
 			input.isCtx = true
+		} else if goType == contextType {
+			input.isContext = true
 		} else if isCtx(goType) {
 			return fmt.Errorf("%s ctx argument must be a pointer", method.goFunctionName)
 		} else if typeKind == reflect.Struct {
+			if hasOneOfMarker(goType) {
+				return fmt.Errorf("%s arguments cannot be oneOf, use a oneOf input object as argument", method.goFunctionName)
+			}
 			input.goType = &goType
-			for i := 0; i < goType.NumField(); i++ {
-				field := goType.Field(i)
-				input, skip, err := c.checkFunctionInputStruct(&field, i)
+			for _, structField := range structFields(goType) {
+				input, skip, err := c.checkFunctionInputStruct(&structField.field, structField.idx)
 				if skip {
 					continue
 				}
+				if err == nil {
+					err = checkEmbeddedInputPath(goType, structField.embeddedPath)
+				}
 				if err != nil {
-					return fmt.Errorf("%s, type %s (#%d)", err.Error(), goType.Name(), i)
+					return fmt.Errorf("%s, type %s (#%d)", err.Error(), goType.Name(), structField.idx)
 				}
+				input.embeddedPath = structField.embeddedPath
 
 				method.inFields[input.gqFieldName] = referToInput{
 					inputIdx: iInList,
 					input:    input,
 				}
+				if input.defaultJSON != nil {
+					method.hasDefaults = true
+				}
 			}
 		} else {
 			return fmt.Errorf("invalid struct item type %s (#%d)", goType.Name(), i)
@@ -885,11 +1309,57 @@ func formatGoNameToQL(input string) string {
 	return string(bytes.ToLower([]byte{input[0]})) + input[1:]
 }
 
-func parseFieldTagGQ(field *reflect.StructField) (newName *string, ignore bool, isID bool, err error) {
-	val, ok := field.Tag.Lookup("gq")
-	if !ok {
+// fieldTag contains the parsed contents of the gq struct tags
+type fieldTag struct {
+	name    *string
+	ignore  bool
+	isID    bool
+	timeout time.Duration
+	scalar  string // gq:",scalar=Money", expose a text value as named scalar
+	base64  bool   // gq:",base64", expose a []byte or [N]byte as base64 encoded String
+	long    bool   // gq:",long", expose a integer as Long
+
+	// Federation directives, gq:",external" and gq:",shareable" are combined with gqRequires:"weight" and gqProvides:"name"
+	external  bool
+	shareable bool
+
+	timeLayout string // gqTimeLayout:"RFC3339" or gqTimeLayout:"2006-01-02 15:04"
+
+	description       string  // gqDescription:"The name of the user"
+	deprecationReason *string // gqDeprecated:"Use fullName" or gqDeprecated:""
+	defaultValue      *string // gqDefault:"10"
+
+	// The federation directives of the field, see external and shareable
+	federationDirectives []string
+}
+
+// defaultDeprecationReason is the reason used when a field is deprecated without a reason
+// https://spec.graphql.org/October2021/#sec--deprecated
+const defaultDeprecationReason = "No longer supported"
+
+func parseFieldTagGQ(field *reflect.StructField) (tag fieldTag, err error) {
+	tag.description = field.Tag.Get("gqDescription")
+	if reason, ok := field.Tag.Lookup("gqDeprecated"); ok {
+		if reason == "" {
+			reason = defaultDeprecationReason
+		}
+		tag.deprecationReason = &reason
+	}
+	if defaultValue, ok := field.Tag.Lookup("gqDefault"); ok {
+		tag.defaultValue = &defaultValue
+	}
+	tag.timeLayout = resolveTimeLayout(field.Tag.Get("gqTimeLayout"))
+
+	err = parseGQTag(field.Tag.Get("gq"), &tag)
+	if err != nil || tag.ignore {
 		return
 	}
+	tag.federationDirectives = federationFieldDirectives(tag.external, tag.shareable, field.Tag.Get("gqRequires"), field.Tag.Get("gqProvides"))
+	return
+}
+
+// parseGQTag parses the name and modifiers of the gq tag into tag
+func parseGQTag(val string, tag *fieldTag) (err error) {
 	if val == "" {
 		return
 	}
@@ -898,17 +1368,43 @@ func parseFieldTagGQ(field *reflect.StructField) (newName *string, ignore bool,
 	nameArg := strings.TrimSpace(args[0])
 	if nameArg != "" {
 		if nameArg == "-" {
-			ignore = true
+			tag.ignore = true
 			return
 		}
 		err = validGraphQlName([]byte(nameArg))
-		newName = &nameArg
+		tag.name = &nameArg
 	}
 
 	for _, modifier := range args[1:] {
-		switch strings.ToLower(strings.TrimSpace(modifier)) {
+		modifier = strings.TrimSpace(modifier)
+		key, value := modifier, ""
+		if idx := strings.IndexByte(modifier, '='); idx != -1 {
+			key, value = modifier[:idx], strings.TrimSpace(modifier[idx+1:])
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
 		case "id":
-			isID = true
+			tag.isID = true
+		case "timeout":
+			tag.timeout, err = time.ParseDuration(value)
+			if err != nil || tag.timeout <= 0 {
+				err = fmt.Errorf("invalid gq timeout %s, expected a positive duration like 500ms", value)
+				return
+			}
+		case "scalar":
+			if value == "" {
+				err = errors.New("gq scalar requires a scalar name like scalar=Money")
+				return
+			}
+			tag.scalar = value
+		case "base64":
+			tag.base64 = true
+		case "long":
+			tag.long = true
+		case "external":
+			tag.external = true
+		case "shareable":
+			tag.shareable = true
 		default:
 			err = fmt.Errorf("unknown field tag gq argument: %s", modifier)
 			return
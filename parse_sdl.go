@@ -0,0 +1,757 @@
+package yarql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mjarkk/yarql/bytecode"
+	"github.com/valyala/fastjson"
+)
+
+// SDLErrors is returned by (*Schema).ParseWithSDL and contains every problem found while binding the SDL
+// Every error is a bytecode.ErrorWLocation, the line is 0 if the problem is only in the go code
+type SDLErrors []error
+
+// Error implements the error interface
+func (errs SDLErrors) Error() string {
+	messages := make([]string, len(errs))
+	for idx, err := range errs {
+		errWLocation, ok := err.(bytecode.ErrorWLocation)
+		if ok && errWLocation.Line > 0 {
+			messages[idx] = fmt.Sprintf("%d:%d: %s", errWLocation.Line, errWLocation.Column, err.Error())
+		} else {
+			messages[idx] = err.Error()
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
+// ParseWithSDL parses a schema written in the GraphQL schema definition language and binds it to the go resolvers
+//
+// Every field in the SDL must have a matching struct field or Resolve method in go and every go field must be defined in the SDL,
+// the go types must be named equal to the SDL types except for the query and mutation root.
+// Descriptions, deprecations and default values are taken from the SDL, the gqDescription, gqDeprecated and gqDefault tags are ignored.
+//
+// Output fields may be non null in go where the SDL allows null, input fields may be nullable in go where the SDL requires a value.
+// A String or Int field in go can be bound to an ID in the SDL.
+//
+// Example:
+//   //go:embed schema.graphql
+//   var sdl []byte
+//
+//   err := schema.ParseWithSDL(sdl, QueryRoot{}, MutationRoot{})
+func (s *Schema) ParseWithSDL(sdl []byte, queries interface{}, methods interface{}) error {
+	doc, errs := bytecode.ParseSDL(sdl)
+	if len(errs) > 0 {
+		return SDLErrors(errs)
+	}
+
+	err := s.Parse(queries, methods, nil)
+	if err != nil {
+		return err
+	}
+
+	binder := &sdlBinder{
+		schema:      s,
+		doc:         doc,
+		definitions: map[string]*bytecode.TypeDefinition{},
+		bound:       map[string]bool{},
+	}
+	binder.bind()
+	if len(binder.errs) > 0 {
+		s.parsed = false
+		return binder.errs
+	}
+
+	s.clearIntrospectionCache()
+	return nil
+}
+
+type sdlBinder struct {
+	schema      *Schema
+	doc         *bytecode.TypeSystemDocument
+	definitions map[string]*bytecode.TypeDefinition
+	bound       map[string]bool // the SDL types that have a go type
+	defaults    []sdlDefaultValueCheck
+	errs        SDLErrors
+}
+
+type sdlDefaultValueCheck struct {
+	defaultValueCheck
+	location bytecode.Location
+}
+
+func (b *sdlBinder) err(location bytecode.Location, format string, args ...interface{}) {
+	b.errs = append(b.errs, bytecode.ErrorWLocation{
+		Err:    fmt.Errorf(format, args...),
+		Line:   location.Line,
+		Column: location.Column,
+	})
+}
+
+func (b *sdlBinder) bind() {
+	s := b.schema
+
+	for idx := range b.doc.Types {
+		definition := &b.doc.Types[idx]
+		if _, ok := b.definitions[definition.Name]; ok {
+			b.err(definition.Location, "type %s is defined more than once", definition.Name)
+			continue
+		}
+		b.definitions[definition.Name] = definition
+	}
+
+	if !b.bindRoots() {
+		return
+	}
+
+	for _, name := range sortedObjNames(s.types) {
+		if strings.HasPrefix(name, "__") {
+			// Introspection types and inline structs, inline structs are reported by the fields using them
+			continue
+		}
+		goObj := s.types[name]
+		if goObj == s.rootMethod && !b.bound[name] {
+			// The SDL has no mutation type, this is checked by bindRoots
+			continue
+		}
+		definition := b.lookup(name, bytecode.TypeDefinitionObject, goObj.goPkgPath+"."+goObj.goTypeName)
+		if definition != nil {
+			b.bindObject(goObj, definition)
+		}
+	}
+
+	for _, name := range sortedObjNames(s.interfaces) {
+		goObj := s.interfaces[name]
+		definition := b.lookup(name, bytecode.TypeDefinitionInterface, goObj.goPkgPath+"."+goObj.goTypeName)
+		if definition != nil {
+			b.bindObject(goObj, definition)
+		}
+	}
+
+	inTypeNames := make([]string, 0, len(s.inTypes))
+	for name := range s.inTypes {
+		inTypeNames = append(inTypeNames, name)
+	}
+	sort.Strings(inTypeNames)
+	for _, name := range inTypeNames {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		inType := s.inTypes[name]
+		definition := b.lookup(name, bytecode.TypeDefinitionInputObject, inType.goType.PkgPath()+"."+inType.goType.Name())
+		if definition != nil {
+			b.bindInputObject(inType, definition)
+		}
+	}
+
+	for idx := range s.definedEnums {
+		enum := &s.definedEnums[idx]
+		if strings.HasPrefix(enum.typeName, "__") {
+			continue
+		}
+		definition := b.lookup(enum.typeName, bytecode.TypeDefinitionEnum, enum.contentType.PkgPath()+"."+enum.contentType.Name())
+		if definition != nil {
+			b.bindEnum(enum, definition)
+		}
+	}
+
+	for _, definition := range b.doc.Types {
+		if b.bound[definition.Name] {
+			continue
+		}
+		switch {
+		case definition.Kind == bytecode.TypeDefinitionScalar && b.knownScalar(definition.Name):
+			b.checkDirectives(definition.Directives, "specifiedBy")
+		case definition.Kind == bytecode.TypeDefinitionScalar:
+			b.err(definition.Location, "custom scalar %s is not supported", definition.Name)
+		case definition.Kind == bytecode.TypeDefinitionUnion:
+			b.err(definition.Location, "union %s is not supported, use an interface instead", definition.Name)
+		default:
+			b.err(definition.Location, "%s %s is defined in the SDL but not used by the go resolvers", definition.Kind, definition.Name)
+		}
+	}
+
+	b.bindDirectives()
+
+	if len(b.errs) > 0 {
+		// Default values can only be checked if the types are correct
+		return
+	}
+	for _, check := range b.defaults {
+		err := s.checkDefaultValue(check.defaultValueCheck)
+		if err != nil {
+			b.err(check.location, "%s", err.Error())
+		}
+	}
+}
+
+// bindRoots binds the query and mutation type of the SDL to the go roots, the go roots are renamed to the SDL names
+func (b *sdlBinder) bindRoots() bool {
+	s := b.schema
+
+	queryName := "Query"
+	mutationName := "Mutation"
+	location := bytecode.Location{Line: 1}
+	if b.doc.Schema != nil {
+		queryName = b.doc.Schema.Query
+		mutationName = b.doc.Schema.Mutation
+		location = b.doc.Schema.Location
+		b.checkDirectives(b.doc.Schema.Directives)
+		if b.doc.Schema.Subscription != "" {
+			b.err(location, "subscriptions are not supported")
+		}
+	}
+
+	query, ok := b.definitions[queryName]
+	if !ok {
+		b.err(location, "the SDL has no %s type", queryName)
+		return false
+	}
+	if query.Kind != bytecode.TypeDefinitionObject {
+		b.err(query.Location, "the query root %s must be an object type", queryName)
+		return false
+	}
+	if !b.renameRoot(s.rootQuery, queryName, query.Location) {
+		return false
+	}
+
+	mutation, ok := b.definitions[mutationName]
+	if !ok || mutationName == "" {
+		for _, field := range s.rootMethod.objContents {
+			if !field.hidden {
+				b.err(location, "the go mutation root %s has fields but the SDL has no mutation type", s.rootMethod.goTypeName)
+				return false
+			}
+		}
+		return true
+	}
+	if mutation.Kind != bytecode.TypeDefinitionObject {
+		b.err(mutation.Location, "the mutation root %s must be an object type", mutationName)
+		return false
+	}
+	return b.renameRoot(s.rootMethod, mutationName, mutation.Location)
+}
+
+func (b *sdlBinder) renameRoot(root *obj, name string, location bytecode.Location) bool {
+	s := b.schema
+	b.bound[name] = true
+
+	oldName := root.typeName
+	if oldName == name {
+		return true
+	}
+	if _, ok := s.types[name]; ok {
+		b.err(location, "cannot bind %s to the go root %s, the go type %s also uses the name %s", name, root.goTypeName, s.types[name].goTypeName, name)
+		return false
+	}
+
+	delete(s.types, oldName)
+	s.types[name] = root
+	root.typeName = name
+	root.typeNameBytes = []byte(name)
+
+	rename := func(item *obj) {
+		if (item.valueType == valueTypeObjRef) && item.typeName == oldName {
+			item.typeName = name
+			item.typeNameBytes = []byte(name)
+		}
+	}
+	for _, item := range s.types {
+		walkObj(item, rename)
+	}
+	for _, item := range s.interfaces {
+		walkObj(item, rename)
+	}
+	return true
+}
+
+// walkObj calls fn for item and every obj inside of it
+func walkObj(item *obj, fn func(*obj)) {
+	fn(item)
+	if item.innerContent != nil {
+		walkObj(item.innerContent, fn)
+	}
+	if item.method != nil {
+		walkObj(&item.method.outType, fn)
+	}
+	for _, field := range item.objContents {
+		walkObj(field, fn)
+	}
+	for _, implementation := range item.implementations {
+		walkObj(implementation, fn)
+	}
+}
+
+// lookup returns the SDL definition for a go type
+func (b *sdlBinder) lookup(name string, kind bytecode.TypeDefinitionKind, goName string) *bytecode.TypeDefinition {
+	b.bound[name] = true
+
+	definition, ok := b.definitions[name]
+	if !ok {
+		b.err(bytecode.Location{}, "%s %s (%s) is not defined in the SDL", kind, name, goName)
+		return nil
+	}
+	if definition.Kind != kind {
+		b.err(definition.Location, "%s is defined as %s in the SDL but as %s in go (%s)", name, definition.Kind, kind, goName)
+		return nil
+	}
+	return definition
+}
+
+func (b *sdlBinder) bindObject(goObj *obj, definition *bytecode.TypeDefinition) {
+	goObj.description = definition.Description
+	b.checkDirectives(definition.Directives)
+
+	// The implementations of a interface are the types implementing it, the interfaces implemented by the interface are in interfaces
+	implemented := goObj.implementations
+	kind := "type"
+	if goObj.valueType == valueTypeInterface {
+		implemented = goObj.interfaces
+		kind = "interface"
+	}
+	goInterfaces := make([]string, len(implemented))
+	for idx, implementation := range implemented {
+		goInterfaces[idx] = implementation.typeName
+	}
+	sdlInterfaces := append([]string{}, definition.Interfaces...)
+	sort.Strings(goInterfaces)
+	sort.Strings(sdlInterfaces)
+	if strings.Join(goInterfaces, " & ") != strings.Join(sdlInterfaces, " & ") {
+		b.err(definition.Location, "%s %s implements %s in the SDL but %s in go", kind, definition.Name, formatInterfaceList(sdlInterfaces), formatInterfaceList(goInterfaces))
+	}
+
+	sdlFields := map[string]bool{}
+	for _, field := range definition.Fields {
+		sdlFields[field.Name] = true
+		path := definition.Name + "." + field.Name
+
+		goField, ok := goObj.objContents[getObjKey([]byte(field.Name))]
+		if !ok || goField.hidden {
+			goName := strings.ToUpper(field.Name[:1]) + field.Name[1:]
+			b.err(field.Location, "field %s has no go binding, add the struct field %s or the method Resolve%s to %s", path, goName, goName, goObj.goTypeName)
+			continue
+		}
+		b.bindField(goObj, goField, field, path)
+	}
+
+	for _, goField := range sortedObjFields(goObj) {
+		if goField.hidden || sdlFields[string(goField.qlFieldName)] {
+			continue
+		}
+		b.err(definition.Location, "go field %s.%s is not defined in the SDL type %s, add it to the SDL or ignore it using gq:\"-\"", goObj.goTypeName, goFieldName(goField), definition.Name)
+	}
+}
+
+func (b *sdlBinder) bindField(goObj *obj, goField *obj, field bytecode.FieldDefinition, path string) {
+	goField.description = field.Description
+	goField.deprecationReason = nil
+	b.checkDirectives(field.Directives, "deprecated")
+	if deprecated := bytecode.FindDirective(field.Directives, "deprecated"); deprecated != nil {
+		reason := defaultDeprecationReason
+		if value := deprecated.Argument("reason"); value != nil {
+			reason = value.Value
+		}
+		goField.deprecationReason = &reason
+	}
+
+	goPath := goObj.goTypeName + "." + goFieldName(goField)
+	if b.typeExists(field.Type, field.Location) {
+		goType := sdlTypeReference(*wrapQLTypeInNonNull(b.schema.objToQLType(goField)))
+		if b.outputTypeMatches(field.Type, goField) {
+			sdlType := field.Type
+			goField.sdlType = &sdlType
+		} else {
+			b.err(field.Location, "field %s has type %s in the SDL but %s resolves to %s", path, field.Type.String(), goPath, goType)
+		}
+	}
+
+	if goField.valueType != valueTypeMethod {
+		if len(field.Arguments) > 0 {
+			b.err(field.Location, "field %s has arguments in the SDL but %s is not a method", path, goPath)
+		}
+		return
+	}
+	b.bindArguments(goField.method, field.Arguments, path, goPath, field.Location)
+}
+
+func (b *sdlBinder) bindArguments(method *objMethod, arguments []bytecode.InputValueDefinition, path string, goPath string, location bytecode.Location) {
+	sdlArguments := map[string]bool{}
+	for _, argument := range arguments {
+		sdlArguments[argument.Name] = true
+		argumentPath := path + "(" + argument.Name + ":)"
+
+		ref, ok := method.inFields[argument.Name]
+		if !ok {
+			goName := strings.ToUpper(argument.Name[:1]) + argument.Name[1:]
+			b.err(argument.Location, "argument %s has no go binding, add the field %s to the arguments of %s", argumentPath, goName, goPath)
+			continue
+		}
+
+		goType := inputStructField(*method.ins[ref.inputIdx].goType, &ref.input).Type
+		b.bindInputValue(&ref.input, argument, goType, argumentPath)
+		method.inFields[argument.Name] = ref
+	}
+
+	goArguments := make([]string, 0, len(method.inFields))
+	for name := range method.inFields {
+		goArguments = append(goArguments, name)
+	}
+	sort.Strings(goArguments)
+	for _, name := range goArguments {
+		if !sdlArguments[name] {
+			b.err(location, "go argument %s of %s is not defined in the SDL field %s", name, goPath, path)
+		}
+	}
+
+	method.hasDefaults = false
+	for _, ref := range method.inFields {
+		if ref.input.defaultJSON != nil {
+			method.hasDefaults = true
+		}
+	}
+}
+
+func (b *sdlBinder) bindInputObject(inType *input, definition *bytecode.TypeDefinition) {
+	inType.description = definition.Description
+	b.checkDirectives(definition.Directives, "oneOf")
+	isOneOf := bytecode.FindDirective(definition.Directives, "oneOf") != nil
+	if inType.isOneOf && !isOneOf {
+		b.err(definition.Location, "input %s is marked as oneOf in go but not in the SDL, add @oneOf to the input", definition.Name)
+	}
+	inType.isOneOf = isOneOf
+
+	sdlFields := map[string]bool{}
+	for _, field := range definition.InputFields {
+		sdlFields[field.Name] = true
+		path := definition.Name + "." + field.Name
+
+		goField, ok := inType.structContent[field.Name]
+		if !ok {
+			goName := strings.ToUpper(field.Name[:1]) + field.Name[1:]
+			b.err(field.Location, "input field %s has no go binding, add the field %s to %s", path, goName, inType.goType.Name())
+			continue
+		}
+
+		goType := inputStructField(inType.goType, &goField).Type
+		b.bindInputValue(&goField, field, goType, path)
+		inType.structContent[field.Name] = goField
+	}
+
+	goFields := make([]string, 0, len(inType.structContent))
+	for name := range inType.structContent {
+		goFields = append(goFields, name)
+	}
+	sort.Strings(goFields)
+	for _, name := range goFields {
+		if !sdlFields[name] {
+			goField := inType.structContent[name]
+			b.err(definition.Location, "go field %s.%s is not defined in the SDL input %s, add it to the SDL or ignore it using gq:\"-\"", inType.goType.Name(), inputStructField(inType.goType, &goField).Name, definition.Name)
+		}
+	}
+
+	inType.hasDefaults = false
+	for _, field := range inType.structContent {
+		if field.defaultJSON != nil {
+			inType.hasDefaults = true
+		}
+	}
+
+	if inType.isOneOf {
+		err := b.schema.checkOneOfInput(inType)
+		if err != nil {
+			b.err(definition.Location, "%s", err.Error())
+		}
+	}
+}
+
+func (b *sdlBinder) bindInputValue(in *input, definition bytecode.InputValueDefinition, goType reflect.Type, path string) {
+	in.description = definition.Description
+	b.checkDirectives(definition.Directives)
+
+	if b.typeExists(definition.Type, definition.Location) {
+		goTypeName := sdlTypeReference(*wrapQLTypeInNonNull(b.schema.inputToQLType(in)))
+		if b.inputTypeMatches(definition.Type, in) {
+			sdlType := definition.Type
+			in.sdlType = &sdlType
+		} else {
+			hint := ""
+			if !definition.Type.NonNull {
+				hint = ", use a pointer or slice in go to allow null"
+			}
+			b.err(definition.Location, "%s has type %s in the SDL but %s in go%s", path, definition.Type.String(), goTypeName, hint)
+		}
+	}
+
+	in.defaultValue = nil
+	in.defaultJSON = nil
+	if definition.DefaultValue == nil {
+		return
+	}
+
+	defaultJSON, err := fastjson.ParseBytes(graphqlValueToJSON(*definition.DefaultValue, nil))
+	if err != nil {
+		b.err(definition.Location, "%s: invalid default value, %s", path, err.Error())
+		return
+	}
+	literal := definition.DefaultValue.String()
+	in.defaultValue = &literal
+	in.defaultJSON = defaultJSON
+
+	b.defaults = append(b.defaults, sdlDefaultValueCheck{
+		defaultValueCheck: defaultValueCheck{
+			fieldName: path,
+			goType:    goType,
+			input:     *in,
+		},
+		location: definition.Location,
+	})
+}
+
+func (b *sdlBinder) bindEnum(enum *enum, definition *bytecode.TypeDefinition) {
+	b.checkDirectives(definition.Directives)
+
+	description := definition.Description
+	enum.qlType.Description = &description
+
+	goValues := map[string]bool{}
+	for _, entry := range enum.entries {
+		goValues[entry.key] = true
+	}
+
+	sdlValues := map[string]bool{}
+	values := []qlEnumValue{}
+	for _, value := range definition.EnumValues {
+		sdlValues[value.Name] = true
+		if !goValues[value.Name] {
+			b.err(value.Location, "enum value %s.%s is not registered in go", definition.Name, value.Name)
+			continue
+		}
+
+		b.checkDirectives(value.Directives, "deprecated")
+		description := value.Description
+		qlValue := qlEnumValue{
+			Name:        value.Name,
+			Description: &description,
+		}
+		if deprecated := bytecode.FindDirective(value.Directives, "deprecated"); deprecated != nil {
+			reason := defaultDeprecationReason
+			if value := deprecated.Argument("reason"); value != nil {
+				reason = value.Value
+			}
+			qlValue.IsDeprecated = true
+			qlValue.DeprecationReason = &reason
+		}
+		values = append(values, qlValue)
+	}
+	sort.Slice(values, func(a int, b int) bool { return values[a].Name < values[b].Name })
+
+	goKeys := make([]string, 0, len(enum.entries))
+	for _, entry := range enum.entries {
+		goKeys = append(goKeys, entry.key)
+	}
+	sort.Strings(goKeys)
+	for _, key := range goKeys {
+		if !sdlValues[key] {
+			b.err(definition.Location, "go enum value %s of %s is not defined in the SDL", key, enum.typeName)
+		}
+	}
+
+	enum.qlType.EnumValues = resolveEnumValues(values)
+}
+
+// bindDirectives checks if the directive definitions in the SDL match the directives registered using (*Schema).RegisterDirective
+func (b *sdlBinder) bindDirectives() {
+	goDirectives := map[string]*Directive{}
+	goDirectiveNames := []string{}
+	for _, directives := range b.schema.definedDirectives {
+		for _, directive := range directives {
+			if builtinDirectives[directive.Name] {
+				continue
+			}
+			if _, ok := goDirectives[directive.Name]; !ok {
+				goDirectiveNames = append(goDirectiveNames, directive.Name)
+			}
+			goDirectives[directive.Name] = directive
+		}
+	}
+	sort.Strings(goDirectiveNames)
+
+	sdlDirectives := map[string]bool{}
+	for _, definition := range b.doc.Directives {
+		if builtinDirectives[definition.Name] {
+			continue
+		}
+		sdlDirectives[definition.Name] = true
+
+		directive, ok := goDirectives[definition.Name]
+		if !ok {
+			b.err(definition.Location, "directive @%s is not registered in go", definition.Name)
+			continue
+		}
+		directive.Description = definition.Description
+
+		goLocations := make([]string, len(directive.Where))
+		for idx, location := range directive.Where {
+			goLocations[idx] = location.ToQlDirectiveLocation().String()
+		}
+		sdlLocations := append([]string{}, definition.Locations...)
+		sort.Strings(goLocations)
+		sort.Strings(sdlLocations)
+		if strings.Join(goLocations, " | ") != strings.Join(sdlLocations, " | ") {
+			b.err(definition.Location, "directive @%s is defined on %s in the SDL but on %s in go", definition.Name, strings.Join(sdlLocations, " | "), strings.Join(goLocations, " | "))
+		}
+		if definition.Repeatable {
+			b.err(definition.Location, "repeatable directives are not supported")
+		}
+
+		b.bindArguments(directive.parsedMethod, definition.Arguments, "@"+definition.Name, "directive @"+definition.Name, definition.Location)
+	}
+
+	for _, name := range goDirectiveNames {
+		if !sdlDirectives[name] {
+			b.err(bytecode.Location{}, "go directive @%s is not defined in the SDL", name)
+		}
+	}
+}
+
+// checkDirectives reports every directive used in the SDL that is not in allowed
+// Custom directives can only be used inside queries so the SDL can only contain the build in directives
+func (b *sdlBinder) checkDirectives(directives []bytecode.Directive, allowed ...string) {
+outer:
+	for _, directive := range directives {
+		for _, name := range allowed {
+			if directive.Name == name {
+				continue outer
+			}
+		}
+		b.err(directive.Location, "directive @%s is not supported here", directive.Name)
+	}
+}
+
+// typeExists reports an error if the named type of ref is not defined
+func (b *sdlBinder) typeExists(ref bytecode.TypeReference, location bytecode.Location) bool {
+	name := ref.NamedType()
+	if _, ok := b.definitions[name]; ok || builtinScalars[name] || b.knownScalar(name) {
+		return true
+	}
+	b.err(location, "unknown type %s", name)
+	return false
+}
+
+// outputTypeMatches returns true if the value of the go field is always valid for the SDL type
+// Go string and int values are marked as ID if the SDL expects an ID
+func (b *sdlBinder) outputTypeMatches(ref bytecode.TypeReference, item *obj) bool {
+	nonNull := true
+	for {
+		if item.valueType == valueTypeMethod {
+			if !item.method.isTypeMethod {
+				nonNull = false
+			}
+			item = &item.method.outType
+		} else if item.valueType == valueTypePtr {
+			nonNull = false
+			item = item.innerContent
+		} else {
+			break
+		}
+	}
+	if item.valueType == valueTypeArray || item.valueType == valueTypeInterfaceRef || item.valueType == valueTypeInterface || item.valueType == valueTypeJSON || (item.valueType == valueTypeBase64 && item.dataValueType == reflect.Slice) {
+		nonNull = false
+	}
+
+	if ref.NonNull && !nonNull {
+		return false
+	}
+	if ref.List != nil {
+		return item.valueType == valueTypeArray && b.outputTypeMatches(*ref.List, item.innerContent)
+	}
+	if item.valueType == valueTypeArray {
+		return false
+	}
+
+	if ref.Name == "ID" && item.valueType == valueTypeData && checkValidIDKind(item.dataValueType) == nil {
+		item.isID = true
+		return true
+	}
+	qlType, _ := b.schema.objToQLType(item)
+	return qlType != nil && qlType.Name != nil && *qlType.Name == ref.Name
+}
+
+// inputTypeMatches returns true if every value of the SDL type can be bound to the go input
+// Go string and int inputs are marked as ID if the SDL expects an ID
+func (b *sdlBinder) inputTypeMatches(ref bytecode.TypeReference, in *input) bool {
+	nonNull := true
+	for (in.kind == reflect.Ptr && !in.isFile) || in.isOptional {
+		nonNull = false
+		in = in.elem
+	}
+	isList := !in.isTime && !in.isJSON && !in.isText && !in.isBase64 && (in.kind == reflect.Slice || in.kind == reflect.Array)
+	if in.isFile || in.isJSON || (in.isBase64 && in.kind == reflect.Slice) || isList {
+		nonNull = false
+	}
+
+	if !ref.NonNull && nonNull {
+		return false
+	}
+	if ref.List != nil {
+		return isList && b.inputTypeMatches(*ref.List, in.elem)
+	}
+	if isList {
+		return false
+	}
+
+	if ref.Name == "ID" && !in.isEnum && !in.isTime && in.kind != reflect.Struct && checkValidIDKind(in.kind) == nil {
+		in.isID = true
+		return true
+	}
+	qlType, _ := b.schema.inputToQLType(in)
+	return qlType != nil && qlType.Name != nil && *qlType.Name == ref.Name
+}
+
+// sdlKnownScalar returns true for the scalars that are not part of the graphql spec but are supported by yarql
+func sdlKnownScalar(name string) bool {
+	_, ok := scalars[name]
+	return ok || name == "JSON"
+}
+
+// knownScalar returns true for the scalars supported by yarql and the scalars defined by text values
+func (b *sdlBinder) knownScalar(name string) bool {
+	_, ok := b.schema.definedScalars[name]
+	return ok || sdlKnownScalar(name)
+}
+
+func formatInterfaceList(interfaces []string) string {
+	if len(interfaces) == 0 {
+		return "nothing"
+	}
+	return strings.Join(interfaces, " & ")
+}
+
+// goFieldName returns the name of the struct field or method in go
+func goFieldName(item *obj) string {
+	if item.goFieldName != "" {
+		return item.goFieldName
+	}
+	return item.goTypeName
+}
+
+func sortedObjNames(t types) []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedObjFields(item *obj) []*obj {
+	fields := make([]*obj, 0, len(item.objContents))
+	for _, field := range item.objContents {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(a int, b int) bool { return string(fields[a].qlFieldName) < string(fields[b].qlFieldName) })
+	return fields
+}
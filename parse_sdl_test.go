@@ -0,0 +1,348 @@
+package yarql
+
+import (
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+	"github.com/mjarkk/yarql/bytecode"
+)
+
+type SDLBindTestStatus string
+
+type SDLBindTestEntity interface {
+	ResolveId() int
+}
+
+type SDLBindTestUser struct {
+	ID     int `gq:"id"`
+	Name   string
+	Status SDLBindTestStatus
+}
+
+func (u SDLBindTestUser) ResolveId() int {
+	return u.ID
+}
+
+type SDLBindTestUserFilter struct {
+	Name  *string
+	Limit int
+}
+
+type SDLBindTestQuery struct {
+	Users []SDLBindTestUser
+}
+
+func (SDLBindTestQuery) ResolveUser(args struct {
+	ID     int `gq:"id"`
+	Status *SDLBindTestStatus
+}) *SDLBindTestUser {
+	return &SDLBindTestUser{ID: args.ID, Name: "user", Status: *args.Status}
+}
+
+func (SDLBindTestQuery) ResolveSearch(args struct{ Filter SDLBindTestUserFilter }) []SDLBindTestUser {
+	name := "nobody"
+	if args.Filter.Name != nil {
+		name = *args.Filter.Name
+	}
+	res := []SDLBindTestUser{}
+	for i := 0; i < args.Filter.Limit; i++ {
+		res = append(res, SDLBindTestUser{ID: i, Name: name, Status: "ACTIVE"})
+	}
+	return res
+}
+
+type SDLBindTestMutation struct{}
+
+func (SDLBindTestMutation) ResolveRename(args struct {
+	ID   string `gq:"id"`
+	Name string
+}) SDLBindTestUser {
+	return SDLBindTestUser{Name: args.Name, Status: "ACTIVE"}
+}
+
+const sdlBindTestSchema = `"The root query"
+type Query {
+  "Search for users"
+  search(filter: SDLBindTestUserFilter!): [SDLBindTestUser!]
+  user(id: ID!, status: SDLBindTestStatus = ACTIVE): SDLBindTestUser
+  users: [SDLBindTestUser!] @deprecated(reason: "Use search")
+}
+
+type Mutation {
+  rename(id: ID!, name: String!): SDLBindTestUser!
+}
+
+interface SDLBindTestEntity {
+  id: Int!
+}
+
+"""
+A user
+of the app
+"""
+type SDLBindTestUser implements SDLBindTestEntity {
+  id: Int!
+  name: String
+  status: SDLBindTestStatus!
+}
+
+input SDLBindTestUserFilter {
+  limit: Int! = 2
+  "Only match users with this name"
+  name: String
+}
+
+enum SDLBindTestStatus {
+  ACTIVE
+  "The user is banned"
+  BANNED @deprecated
+}
+`
+
+func newSDLBindTestSchema() *Schema {
+	Implements((*SDLBindTestEntity)(nil), SDLBindTestUser{})
+
+	s := NewSchema()
+	s.RegisterEnum(map[string]SDLBindTestStatus{
+		"ACTIVE": "ACTIVE",
+		"BANNED": "BANNED",
+	})
+	return s
+}
+
+func TestParseWithSDL(t *testing.T) {
+	s := newSDLBindTestSchema()
+	err := s.ParseWithSDL([]byte(sdlBindTestSchema), SDLBindTestQuery{}, SDLBindTestMutation{})
+	a.NoError(t, err)
+
+	// The roots are renamed to the SDL names
+	a.Equal(t, "Query", s.rootQuery.typeName)
+	a.Equal(t, "Mutation", s.rootMethod.typeName)
+
+	// The SDL of the schema should contain every definition of the input
+	sdl := s.SDL()
+	for _, definition := range strings.Split(sdlBindTestSchema, "\n\n") {
+		a.True(t, strings.Contains(sdl, definition), definition)
+	}
+
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{`{user(id: 5) {id name status __typename}}`, `{"user":{"id":5,"name":"user","status":"ACTIVE","__typename":"SDLBindTestUser"}}`},
+		{`{user(id: "5", status: BANNED) {status}}`, `{"user":{"status":"BANNED"}}`},
+		{`{search(filter: {name: "bob"}) {id name}}`, `{"search":[{"id":0,"name":"bob"},{"id":1,"name":"bob"}]}`},
+		{`{search(filter: {limit: 1}) {name}}`, `{"search":[{"name":"nobody"}]}`},
+		{`{__typename}`, `{"__typename":"Query"}`},
+		{`mutation {rename(id: "1", name: "alice") {name}}`, `{"rename":{"name":"alice"}}`},
+		{`{__type(name: "SDLBindTestUser") {description}}`, `{"__type":{"description":"A user\nof the app"}}`},
+		{`{__type(name: "SDLBindTestStatus") {enumValues {name} all: enumValues(includeDeprecated: true) {name description isDeprecated}}}`, `{"__type":{"enumValues":[{"name":"ACTIVE"}],"all":[{"name":"ACTIVE","description":"","isDeprecated":false},{"name":"BANNED","description":"The user is banned","isDeprecated":true}]}}`},
+		{`{__type(name: "Query") {fields {name args {name type {kind ofType {name}}}}}}`, `{"__type":{"fields":[{"name":"search","args":[{"name":"filter","type":{"kind":"NON_NULL","ofType":{"name":"SDLBindTestUserFilter"}}}]},{"name":"user","args":[{"name":"id","type":{"kind":"NON_NULL","ofType":{"name":"ID"}}},{"name":"status","type":{"kind":"ENUM","ofType":null}}]}]}}`},
+	}
+
+	for _, testCase := range testCases {
+		errs := s.Resolve([]byte(testCase.query), ResolveOptions{NoMeta: true})
+		for _, err := range errs {
+			t.Fatal(testCase.query, err)
+		}
+		a.Equal(t, testCase.expected, string(s.Result), testCase.query)
+
+		// The copied schema should resolve the same
+		copied := s.Copy()
+		errs = copied.Resolve([]byte(testCase.query), ResolveOptions{NoMeta: true})
+		for _, err := range errs {
+			t.Fatal(testCase.query, err)
+		}
+		a.Equal(t, testCase.expected, string(copied.Result), testCase.query)
+	}
+}
+
+func TestParseWithSDLRoundTrip(t *testing.T) {
+	sdl := newSDLTestSchema(t).SDL()
+
+	Implements((*SDLTestNode)(nil), SDLTestPost{})
+	s := NewSchema()
+	_, err := s.RegisterEnum(map[string]SDLTestSortOrder{
+		"ASC":  SDLTestSortOrderAsc,
+		"DESC": SDLTestSortOrderDesc,
+	})
+	a.NoError(t, err)
+	err = s.RegisterDirective(Directive{
+		Name:   "uppercase",
+		Where:  []DirectiveLocation{DirectiveLocationField},
+		Method: func(args struct{ Enabled bool }) DirectiveModifier { return DirectiveModifier{} },
+	})
+	a.NoError(t, err)
+
+	err = s.ParseWithSDL([]byte(sdl), SDLTestQuery{}, SDLTestMutation{})
+	a.NoError(t, err)
+	a.Equal(t, sdl, s.SDL())
+}
+
+func TestParseWithSDLErrors(t *testing.T) {
+	// replace modifies the valid schema to create a invalid schema
+	replace := func(old, new string) string {
+		a.True(t, strings.Contains(sdlBindTestSchema, old), old)
+		return strings.Replace(sdlBindTestSchema, old, new, 1)
+	}
+
+	testCases := []struct {
+		name     string
+		sdl      string
+		line     uint
+		expected string
+	}{
+		{
+			"syntax error",
+			replace("type Mutation {", "type Mutation"),
+			10,
+			"expected a type system definition",
+		},
+		{
+			"missing go field",
+			replace("  users: [SDLBindTestUser!]", "  friends: [SDLBindTestUser!]\n  users: [SDLBindTestUser!]"),
+			6,
+			"field Query.friends has no go binding, add the struct field Friends or the method ResolveFriends to SDLBindTestQuery",
+		},
+		{
+			"missing SDL field",
+			replace("  search(filter: SDLBindTestUserFilter!): [SDLBindTestUser!]\n", ""),
+			2,
+			"go field SDLBindTestQuery.ResolveSearch is not defined in the SDL type Query",
+		},
+		{
+			"non null output bound to nullable go type",
+			replace("users: [SDLBindTestUser!]", "users: [SDLBindTestUser!]!"),
+			6,
+			"field Query.users has type [SDLBindTestUser!]! in the SDL but SDLBindTestQuery.Users resolves to [SDLBindTestUser!]",
+		},
+		{
+			"nullable input bound to non null go type",
+			replace("rename(id: ID!, name: String!)", "rename(id: ID!, name: String)"),
+			10,
+			"Mutation.rename(name:) has type String in the SDL but String! in go, use a pointer or slice in go to allow null",
+		},
+		{
+			"wrong scalar",
+			replace("  name: String\n  status", "  name: Int\n  status"),
+			23,
+			"field SDLBindTestUser.name has type Int in the SDL but SDLBindTestUser.Name resolves to String!",
+		},
+		{
+			"unknown type",
+			replace("status: SDLBindTestStatus!", "status: Statu!"),
+			24,
+			"unknown type Statu",
+		},
+		{
+			"missing go argument",
+			replace("rename(id: ID!, name: String!)", "rename(id: ID!, name: String!, force: Boolean)"),
+			10,
+			"argument Mutation.rename(force:) has no go binding",
+		},
+		{
+			"missing SDL argument",
+			replace("rename(id: ID!, name: String!)", "rename(id: ID!)"),
+			10,
+			"go argument name of SDLBindTestMutation.ResolveRename is not defined in the SDL field Mutation.rename",
+		},
+		{
+			"arguments on a struct field",
+			replace("users: [SDLBindTestUser!]", "users(first: Int): [SDLBindTestUser!]"),
+			6,
+			"field Query.users has arguments in the SDL but SDLBindTestQuery.Users is not a method",
+		},
+		{
+			"missing enum value",
+			replace("  BANNED @deprecated\n", "  BANNED @deprecated\n  DELETED\n"),
+			37,
+			"enum value SDLBindTestStatus.DELETED is not registered in go",
+		},
+		{
+			"missing interface",
+			replace("type SDLBindTestUser implements SDLBindTestEntity", "type SDLBindTestUser"),
+			21,
+			"type SDLBindTestUser implements nothing in the SDL but SDLBindTestEntity in go",
+		},
+		{
+			"kind mismatch",
+			replace("interface SDLBindTestEntity", "type SDLBindTestEntity"),
+			13,
+			"SDLBindTestEntity is defined as type in the SDL but as interface in go",
+		},
+		{
+			"unused SDL type",
+			sdlBindTestSchema + "\ntype Unused {\n  foo: String\n}\n",
+			39,
+			"type Unused is defined in the SDL but not used by the go resolvers",
+		},
+		{
+			"custom scalar",
+			sdlBindTestSchema + "\nscalar Email\n",
+			39,
+			"custom scalar Email is not supported",
+		},
+		{
+			"invalid default value",
+			replace("limit: Int! = 2", `limit: Int! = "two"`),
+			28,
+			"SDLBindTestUserFilter.limit: invalid default value",
+		},
+		{
+			"unknown directive",
+			replace("name: String\n  status", "name: String @external\n  status"),
+			23,
+			"directive @external is not supported here",
+		},
+		{
+			"missing query type",
+			strings.Replace(sdlBindTestSchema, "type Query", "type Queries", 1),
+			1,
+			"the SDL has no Query type",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := newSDLBindTestSchema().ParseWithSDL([]byte(testCase.sdl), SDLBindTestQuery{}, SDLBindTestMutation{})
+			a.Error(t, err)
+
+			errs, ok := err.(SDLErrors)
+			a.True(t, ok, "expected SDLErrors but got %T", err)
+			a.Equal(t, 1, len(errs), err.Error())
+
+			errWLocation := errs[0].(bytecode.ErrorWLocation)
+			a.True(t, strings.Contains(errWLocation.Error(), testCase.expected), errWLocation.Error())
+			a.Equal(t, testCase.line, errWLocation.Line, errWLocation.Error())
+		})
+	}
+}
+
+type SDLBindTestExtraType struct {
+	Users []SDLBindTestUser
+	Admin SDLBindTestAdmin
+}
+
+type SDLBindTestAdmin struct {
+	Name string
+}
+
+func TestParseWithSDLGoTypeNotInSDL(t *testing.T) {
+	sdl := `type Query {
+  admin: SDLBindTestAdmin!
+  users: [SDLBindTestUser!]
+}
+`
+	err := NewSchema().ParseWithSDL([]byte(sdl), SDLBindTestExtraType{}, M{})
+	a.Error(t, err)
+
+	msg := err.Error()
+	a.True(t, strings.Contains(msg, "type SDLBindTestAdmin (github.com/mjarkk/yarql.SDLBindTestAdmin) is not defined in the SDL"), msg)
+	a.True(t, strings.Contains(msg, "type SDLBindTestUser (github.com/mjarkk/yarql.SDLBindTestUser) is not defined in the SDL"), msg)
+
+	a.True(t, strings.Contains(msg, "2:2: unknown type SDLBindTestAdmin"), msg)
+
+	// All errors are reported at once, this includes the SDLBindTestEntity interface implemented by SDLBindTestUser
+	a.Equal(t, 5, len(err.(SDLErrors)), msg)
+}
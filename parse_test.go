@@ -3,6 +3,7 @@ package yarql
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	a "github.com/mjarkk/yarql/assert"
 )
@@ -130,7 +131,7 @@ func TestCheckStructTags(t *testing.T) {
 
 func TestCheckInvalidStruct(t *testing.T) {
 	_, err := newParseCtx().check(reflect.TypeOf(struct {
-		Foo interface{}
+		Foo interface{ Bar() }
 	}{}), false)
 	a.Error(t, err)
 
@@ -147,6 +148,98 @@ func TestCheckInvalidStruct(t *testing.T) {
 	a.Error(t, err)
 }
 
+type TestCheckFieldTimeoutTagData struct {
+	Foo func() string `gq:",timeout=1500ms"`
+}
+
+func TestCheckFieldTimeoutTag(t *testing.T) {
+	ctx := newParseCtx()
+	ref, err := ctx.check(reflect.TypeOf(TestCheckFieldTimeoutTagData{}), false)
+	a.NoError(t, err)
+	obj := ctx.schema.types[ref.typeName]
+	a.Equal(t, time.Millisecond*1500, obj.objContents[getObjKey([]byte("foo"))].timeout)
+
+	_, err = newParseCtx().check(reflect.TypeOf(struct {
+		Foo string `gq:",timeout=1s"`
+	}{}), false)
+	a.Error(t, err, "timeouts are only allowed on methods")
+
+	_, err = newParseCtx().check(reflect.TypeOf(struct {
+		Foo func() string `gq:",timeout=banana"`
+	}{}), false)
+	a.Error(t, err)
+
+	_, err = newParseCtx().check(reflect.TypeOf(struct {
+		Foo func() string `gq:",timeout=-1s"`
+	}{}), false)
+	a.Error(t, err)
+}
+
+type TestCheckFieldDescriptionTagsData struct {
+	Foo string `gqDescription:"The foo field"`
+	Bar string `gqDeprecated:""`
+	Baz string `gqDeprecated:"Use foo"`
+}
+
+func TestCheckFieldDescriptionTags(t *testing.T) {
+	ctx := newParseCtx()
+	ref, err := ctx.check(reflect.TypeOf(TestCheckFieldDescriptionTagsData{}), false)
+	a.NoError(t, err)
+	obj := ctx.schema.types[ref.typeName]
+
+	foo := obj.objContents[getObjKey([]byte("foo"))]
+	a.Equal(t, "The foo field", foo.description)
+	a.Nil(t, foo.deprecationReason)
+
+	bar := obj.objContents[getObjKey([]byte("bar"))]
+	a.NotNil(t, bar.deprecationReason)
+	a.Equal(t, defaultDeprecationReason, *bar.deprecationReason)
+
+	baz := obj.objContents[getObjKey([]byte("baz"))]
+	a.NotNil(t, baz.deprecationReason)
+	a.Equal(t, "Use foo", *baz.deprecationReason)
+
+	_, err = newParseCtx().check(reflect.TypeOf(struct {
+		Foo string `gqDefault:"\"foo\""`
+	}{}), false)
+	a.Error(t, err, "default values are only allowed on input fields")
+}
+
+type TestCheckDefaultValuesData struct{}
+
+func (TestCheckDefaultValuesData) ResolveFoo(args struct {
+	A int `gqDefault:"banana"`
+}) int {
+	return args.A
+}
+
+type TestCheckDefaultValuesSyntaxData struct{}
+
+func (TestCheckDefaultValuesSyntaxData) ResolveFoo(args struct {
+	A []int `gqDefault:"[1, 2"`
+}) int {
+	return 0
+}
+
+type TestCheckDeprecatedInputData struct{}
+
+func (TestCheckDeprecatedInputData) ResolveFoo(args struct {
+	A int `gqDeprecated:""`
+}) int {
+	return args.A
+}
+
+func TestCheckDefaultValues(t *testing.T) {
+	err := NewSchema().Parse(TestCheckDefaultValuesData{}, M{}, nil)
+	a.Error(t, err, "default value doesn't match the type of the field")
+
+	err = NewSchema().Parse(TestCheckDefaultValuesSyntaxData{}, M{}, nil)
+	a.Error(t, err, "default value is not a valid graphql value")
+
+	err = NewSchema().Parse(TestCheckDeprecatedInputData{}, M{}, nil)
+	a.Error(t, err, "input fields cannot be deprecated")
+}
+
 type TestCheckMethodsData struct{}
 
 func (TestCheckMethodsData) ResolveName(in struct{}) string {
@@ -0,0 +1,341 @@
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// FromSlice fills connection with the page of nodes selected by args
+// nodes must be a slice of nodes and connection a pointer to a connection struct, see the package docs.
+// The cursors are offset cursors and the optional TotalCount field is set to the length of nodes.
+func FromSlice(nodes interface{}, args ConnectionArgs, connection interface{}) error {
+	nodesValue, err := sliceValue(nodes)
+	if err != nil {
+		return err
+	}
+
+	return FromOffset(args, nodesValue.Len(), func(offset int, limit int) (interface{}, error) {
+		return nodesValue.Slice(offset, offset+limit).Interface(), nil
+	}, connection)
+}
+
+// OffsetLoader loads at most limit nodes starting at offset, the returned value must be a slice of nodes
+type OffsetLoader func(offset int, limit int) (nodes interface{}, err error)
+
+// FromOffset fills connection with the page of nodes selected by args using offset pagination
+// totalCount is the number of nodes in the full list, load is only called if the page contains nodes.
+// The optional TotalCount field of the connection is set to totalCount.
+//
+// Example:
+//   err := relay.FromOffset(args, countUsers(), func(offset, limit int) (interface{}, error) {
+//     return queryUsers("SELECT * FROM users ORDER BY id LIMIT ? OFFSET ?", limit, offset)
+//   }, &res)
+func FromOffset(args ConnectionArgs, totalCount int, load OffsetLoader, connection interface{}) error {
+	err := args.validate()
+	if err != nil {
+		return err
+	}
+	target, err := newConnectionTarget(connection)
+	if err != nil {
+		return err
+	}
+
+	start, end := 0, totalCount
+	if args.After != nil {
+		after, err := DecodeOffsetCursor(*args.After)
+		if err != nil {
+			return err
+		}
+		if after+1 > start {
+			start = after + 1
+		}
+	}
+	if args.Before != nil {
+		before, err := DecodeOffsetCursor(*args.Before)
+		if err != nil {
+			return err
+		}
+		if before < end {
+			end = before
+		}
+	}
+	if start > end {
+		start = end
+	}
+	if args.First != nil && end-start > *args.First {
+		end = start + *args.First
+	}
+	if args.Last != nil && end-start > *args.Last {
+		start = end - *args.Last
+	}
+
+	nodes := reflect.ValueOf([]interface{}{})
+	if end > start {
+		loaded, err := load(start, end-start)
+		if err != nil {
+			return err
+		}
+		nodes, err = sliceValue(loaded)
+		if err != nil {
+			return err
+		}
+		if nodes.Len() > end-start {
+			nodes = nodes.Slice(0, end-start)
+		}
+	}
+
+	cursors := make([]string, nodes.Len())
+	for idx := range cursors {
+		cursors[idx] = OffsetCursor(start + idx)
+	}
+
+	pageInfo := PageInfo{
+		HasPreviousPage: start > 0,
+		HasNextPage:     end < totalCount,
+	}
+	return target.fill(nodes, cursors, pageInfo, &totalCount)
+}
+
+// KeysetPage describes the nodes a KeysetLoader must load
+type KeysetPage struct {
+	// After and Before are the keys of the after and before cursors, nil if not set
+	After  *string
+	Before *string
+
+	// Limit is the max number of nodes to load, 0 means there is no limit
+	// This is one more than the first or last argument so the connection knows if there is a next or previous page
+	Limit int
+
+	// FromEnd is true if the last nodes before Before should be loaded instead of the first nodes after After
+	// The nodes must still be returned in the normal order
+	FromEnd bool
+}
+
+// KeysetLoader loads the nodes described by page, the returned value must be a slice of nodes
+type KeysetLoader func(page KeysetPage) (nodes interface{}, err error)
+
+// FromKeyset fills connection with the page of nodes selected by args using keyset pagination
+// key returns the unique sort key of a node, the cursors are created from this key and passed back to load as After or Before.
+// The optional TotalCount field of the connection is not set.
+//
+// Example:
+//   err := relay.FromKeyset(args, func(page relay.KeysetPage) (interface{}, error) {
+//     return queryUsers(page.After, page.Before, page.Limit, page.FromEnd)
+//   }, func(node interface{}) string {
+//     return strconv.Itoa(node.(User).ID)
+//   }, &res)
+func FromKeyset(args ConnectionArgs, load KeysetLoader, key func(node interface{}) string, connection interface{}) error {
+	err := args.validate()
+	if err != nil {
+		return err
+	}
+	target, err := newConnectionTarget(connection)
+	if err != nil {
+		return err
+	}
+
+	page := KeysetPage{}
+	if args.After != nil {
+		after, err := DecodeKeyCursor(*args.After)
+		if err != nil {
+			return err
+		}
+		page.After = &after
+	}
+	if args.Before != nil {
+		before, err := DecodeKeyCursor(*args.Before)
+		if err != nil {
+			return err
+		}
+		page.Before = &before
+	}
+	if args.First != nil {
+		page.Limit = *args.First + 1
+	} else if args.Last != nil {
+		page.Limit = *args.Last + 1
+		page.FromEnd = true
+	}
+
+	loaded, err := load(page)
+	if err != nil {
+		return err
+	}
+	nodes, err := sliceValue(loaded)
+	if err != nil {
+		return err
+	}
+
+	pageInfo := PageInfo{}
+	start, end := 0, nodes.Len()
+	if args.First != nil && end > *args.First {
+		end = *args.First
+		pageInfo.HasNextPage = true
+	}
+	if args.Last != nil && end-start > *args.Last {
+		start = end - *args.Last
+		pageInfo.HasPreviousPage = true
+	}
+	nodes = nodes.Slice(start, end)
+
+	cursors := make([]string, nodes.Len())
+	for idx := range cursors {
+		cursors[idx] = KeyCursor(key(nodes.Index(idx).Interface()))
+	}
+
+	return target.fill(nodes, cursors, pageInfo, nil)
+}
+
+func sliceValue(nodes interface{}) (reflect.Value, error) {
+	if nodes == nil {
+		return reflect.ValueOf([]interface{}{}), nil
+	}
+
+	value := reflect.ValueOf(nodes)
+	if value.Kind() != reflect.Slice {
+		return value, fmt.Errorf("nodes must be a slice, got %T", nodes)
+	}
+	return value, nil
+}
+
+// connectionTarget contains the fields of a connection struct
+type connectionTarget struct {
+	typeName   string
+	edges      reflect.Value
+	nodeType   reflect.Type // the type of the Node field if the connection embeds Connection, nil otherwise
+	pageInfo   reflect.Value
+	totalCount reflect.Value // invalid if the connection has no TotalCount field
+}
+
+var (
+	pageInfoType   = reflect.TypeOf(PageInfo{})
+	connectionType = reflect.TypeOf(Connection{})
+)
+
+func newConnectionTarget(connection interface{}) (*connectionTarget, error) {
+	value := reflect.ValueOf(connection)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("connection must be a pointer to a connection struct, got %T", connection)
+	}
+	value = value.Elem()
+
+	if value.NumField() > 0 && value.Type().Field(0).Anonymous && value.Type().Field(0).Type == connectionType {
+		node, ok := value.Type().FieldByName("Node")
+		if !ok {
+			return nil, fmt.Errorf("%s must have a Node field with the node type", value.Type().Name())
+		}
+		embedded := value.Field(0)
+		return &connectionTarget{
+			typeName:   value.Type().Name(),
+			edges:      embedded.FieldByName("Edges"),
+			nodeType:   node.Type,
+			pageInfo:   embedded.FieldByName("PageInfo"),
+			totalCount: embedded.FieldByName("TotalCount"),
+		}, nil
+	}
+
+	// A connection struct with its own edge type
+	res := &connectionTarget{
+		typeName:   value.Type().Name(),
+		edges:      value.FieldByName("Edges"),
+		pageInfo:   value.FieldByName("PageInfo"),
+		totalCount: value.FieldByName("TotalCount"),
+	}
+
+	if !res.edges.IsValid() || res.edges.Kind() != reflect.Slice || res.edges.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s must have a Edges field with a slice of edge structs", res.typeName)
+	}
+	edgeType := res.edges.Type().Elem()
+	if cursor, ok := edgeType.FieldByName("Cursor"); !ok || cursor.Type.Kind() != reflect.String {
+		return nil, fmt.Errorf("%s must have a Cursor string field", edgeType.Name())
+	}
+	if _, ok := edgeType.FieldByName("Node"); !ok {
+		return nil, fmt.Errorf("%s must have a Node field", edgeType.Name())
+	}
+
+	if !res.pageInfo.IsValid() || res.pageInfo.Type() != pageInfoType {
+		return nil, fmt.Errorf("%s must have a PageInfo field of type relay.PageInfo", res.typeName)
+	}
+
+	if res.totalCount.IsValid() {
+		kind := res.totalCount.Kind()
+		if kind != reflect.Int && (kind != reflect.Ptr || res.totalCount.Type().Elem().Kind() != reflect.Int) {
+			return nil, fmt.Errorf("the TotalCount field of %s must be a int or *int", res.typeName)
+		}
+	}
+
+	return res, nil
+}
+
+// fill sets the edges, page info and total count of the connection
+func (t *connectionTarget) fill(nodes reflect.Value, cursors []string, pageInfo PageInfo, totalCount *int) error {
+	edges := reflect.MakeSlice(t.edges.Type(), len(cursors), len(cursors))
+	for idx, cursor := range cursors {
+		edge := edges.Index(idx)
+		edge.FieldByName("Cursor").SetString(cursor)
+
+		if t.nodeType == nil {
+			err := setNode(edge.FieldByName("Node"), nodes.Index(idx))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The Node field of Edge is a interface, set it to a value of the node type of the connection
+		node := reflect.New(t.nodeType).Elem()
+		err := setNode(node, nodes.Index(idx))
+		if err != nil {
+			return err
+		}
+		edge.FieldByName("Node").Set(node)
+	}
+	t.edges.Set(edges)
+
+	if len(cursors) > 0 {
+		pageInfo.StartCursor = &cursors[0]
+		pageInfo.EndCursor = &cursors[len(cursors)-1]
+	}
+	t.pageInfo.Set(reflect.ValueOf(pageInfo))
+
+	if totalCount != nil && t.totalCount.IsValid() {
+		if t.totalCount.Kind() == reflect.Ptr {
+			count := reflect.New(t.totalCount.Type().Elem())
+			count.Elem().SetInt(int64(*totalCount))
+			t.totalCount.Set(count)
+		} else {
+			t.totalCount.SetInt(int64(*totalCount))
+		}
+	}
+
+	return nil
+}
+
+// setNode sets the Node field of an edge to node, pointers are added or removed if required
+func setNode(field reflect.Value, node reflect.Value) error {
+	if node.Kind() == reflect.Interface {
+		node = node.Elem()
+	}
+	if !node.IsValid() {
+		return errors.New("nodes cannot be nil")
+	}
+
+	fieldType := field.Type()
+	nodeType := node.Type()
+	switch {
+	case nodeType.AssignableTo(fieldType):
+		field.Set(node)
+	case nodeType.Kind() == reflect.Ptr && nodeType.Elem().AssignableTo(fieldType):
+		if node.IsNil() {
+			return errors.New("nodes cannot be nil")
+		}
+		field.Set(node.Elem())
+	case fieldType.Kind() == reflect.Ptr && nodeType.AssignableTo(fieldType.Elem()):
+		ptr := reflect.New(nodeType)
+		ptr.Elem().Set(node)
+		field.Set(ptr)
+	default:
+		return fmt.Errorf("cannot assign node of type %s to %s", nodeType.String(), fieldType.String())
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package relay
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mjarkk/yarql"
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestUser struct {
+	ID   int
+	Name string
+}
+
+type TestUserEdge struct {
+	Cursor string
+	Node   TestUser
+}
+
+type TestUserConnection struct {
+	Edges      []TestUserEdge
+	PageInfo   PageInfo
+	TotalCount *int
+}
+
+var testUsers = []TestUser{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}, {5, "e"}}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func nodeIDs(connection TestUserConnection) []int {
+	res := []int{}
+	for _, edge := range connection.Edges {
+		res = append(res, edge.Node.ID)
+	}
+	return res
+}
+
+func TestFromSlice(t *testing.T) {
+	options := []struct {
+		name            string
+		args            ConnectionArgs
+		ids             []int
+		hasPreviousPage bool
+		hasNextPage     bool
+	}{
+		{"all", ConnectionArgs{}, []int{1, 2, 3, 4, 5}, false, false},
+		{"first", ConnectionArgs{First: intPtr(2)}, []int{1, 2}, false, true},
+		{"first more than available", ConnectionArgs{First: intPtr(10)}, []int{1, 2, 3, 4, 5}, false, false},
+		{"first zero", ConnectionArgs{First: intPtr(0)}, []int{}, false, true},
+		{"first after", ConnectionArgs{First: intPtr(2), After: strPtr(OffsetCursor(1))}, []int{3, 4}, true, true},
+		{"after last node", ConnectionArgs{After: strPtr(OffsetCursor(4))}, []int{}, true, false},
+		{"last", ConnectionArgs{Last: intPtr(2)}, []int{4, 5}, true, false},
+		{"last before", ConnectionArgs{Last: intPtr(2), Before: strPtr(OffsetCursor(3))}, []int{2, 3}, true, true},
+		{"after and before", ConnectionArgs{After: strPtr(OffsetCursor(0)), Before: strPtr(OffsetCursor(3))}, []int{2, 3}, true, true},
+		{"before after after", ConnectionArgs{After: strPtr(OffsetCursor(3)), Before: strPtr(OffsetCursor(1))}, []int{}, true, true},
+		{"first and last", ConnectionArgs{First: intPtr(4), Last: intPtr(2)}, []int{3, 4}, true, true},
+	}
+
+	for _, option := range options {
+		var res TestUserConnection
+		err := FromSlice(testUsers, option.args, &res)
+		a.NoError(t, err, option.name)
+		a.Equal(t, option.ids, nodeIDs(res), option.name)
+		a.Equal(t, option.hasPreviousPage, res.PageInfo.HasPreviousPage, option.name)
+		a.Equal(t, option.hasNextPage, res.PageInfo.HasNextPage, option.name)
+		a.Equal(t, 5, *res.TotalCount, option.name)
+
+		if len(res.Edges) == 0 {
+			a.Nil(t, res.PageInfo.StartCursor, option.name)
+			a.Nil(t, res.PageInfo.EndCursor, option.name)
+		} else {
+			a.Equal(t, res.Edges[0].Cursor, *res.PageInfo.StartCursor, option.name)
+			a.Equal(t, res.Edges[len(res.Edges)-1].Cursor, *res.PageInfo.EndCursor, option.name)
+		}
+	}
+}
+
+func TestFromSliceContinuesFromCursor(t *testing.T) {
+	var firstPage TestUserConnection
+	a.NoError(t, FromSlice(testUsers, ConnectionArgs{First: intPtr(2)}, &firstPage))
+
+	var secondPage TestUserConnection
+	a.NoError(t, FromSlice(testUsers, ConnectionArgs{First: intPtr(2), After: firstPage.PageInfo.EndCursor}, &secondPage))
+	a.Equal(t, []int{3, 4}, nodeIDs(secondPage))
+
+	var previousPage TestUserConnection
+	a.NoError(t, FromSlice(testUsers, ConnectionArgs{Last: intPtr(2), Before: secondPage.PageInfo.StartCursor}, &previousPage))
+	a.Equal(t, []int{1, 2}, nodeIDs(previousPage))
+}
+
+func TestFromSliceErrors(t *testing.T) {
+	var res TestUserConnection
+	a.Error(t, FromSlice(testUsers, ConnectionArgs{First: intPtr(-1)}, &res))
+	a.Error(t, FromSlice(testUsers, ConnectionArgs{Last: intPtr(-1)}, &res))
+	a.Equal(t, ErrInvalidCursor, FromSlice(testUsers, ConnectionArgs{After: strPtr("not a cursor")}, &res))
+	a.Equal(t, ErrInvalidCursor, FromSlice(testUsers, ConnectionArgs{Before: strPtr(KeyCursor("1"))}, &res))
+	a.Error(t, FromSlice(testUsers[0], ConnectionArgs{}, &res))
+	a.Error(t, FromSlice([]string{"a"}, ConnectionArgs{}, &res))
+
+	// The connection must be a pointer to a valid connection type
+	a.Error(t, FromSlice(testUsers, ConnectionArgs{}, res))
+	a.Error(t, FromSlice(testUsers, ConnectionArgs{}, &TestUserEdge{}))
+	a.Error(t, FromSlice(testUsers, ConnectionArgs{}, &struct{ Edges []TestUserEdge }{}))
+	a.Error(t, FromSlice(testUsers, ConnectionArgs{}, &struct {
+		Edges    []struct{ Node TestUser }
+		PageInfo PageInfo
+	}{}))
+}
+
+func TestFromSliceNodePointers(t *testing.T) {
+	users := []*TestUser{&testUsers[0], &testUsers[1]}
+	var res TestUserConnection
+	a.NoError(t, FromSlice(users, ConnectionArgs{}, &res))
+	a.Equal(t, []int{1, 2}, nodeIDs(res))
+
+	var ptrRes struct {
+		Edges []struct {
+			Cursor string
+			Node   *TestUser
+		}
+		PageInfo   PageInfo
+		TotalCount int
+	}
+	a.NoError(t, FromSlice(testUsers, ConnectionArgs{First: intPtr(1)}, &ptrRes))
+	a.Equal(t, 1, ptrRes.Edges[0].Node.ID)
+	a.Equal(t, 5, ptrRes.TotalCount)
+
+	a.Error(t, FromSlice([]*TestUser{nil}, ConnectionArgs{}, &res))
+}
+
+func TestFromOffset(t *testing.T) {
+	calls := 0
+	load := func(offset int, limit int) (interface{}, error) {
+		calls++
+		a.Equal(t, 2, offset)
+		a.Equal(t, 2, limit)
+		return testUsers[offset : offset+limit], nil
+	}
+
+	var res TestUserConnection
+	err := FromOffset(ConnectionArgs{First: intPtr(2), After: strPtr(OffsetCursor(1))}, 100, load, &res)
+	a.NoError(t, err)
+	a.Equal(t, 1, calls)
+	a.Equal(t, []int{3, 4}, nodeIDs(res))
+	a.True(t, res.PageInfo.HasNextPage)
+	a.Equal(t, 100, *res.TotalCount)
+
+	// The loader is not called for empty pages
+	err = FromOffset(ConnectionArgs{First: intPtr(0)}, 100, load, &res)
+	a.NoError(t, err)
+	a.Equal(t, 1, calls)
+	a.Equal(t, 0, len(res.Edges))
+
+	err = FromOffset(ConnectionArgs{}, 100, func(offset int, limit int) (interface{}, error) {
+		return nil, errors.New("database is down")
+	}, &res)
+	a.Error(t, err)
+	a.Equal(t, "database is down", err.Error())
+}
+
+// testKeysetLoader loads the users sorted by ID like a database would
+func testKeysetLoader(t *testing.T, expectedPage KeysetPage) KeysetLoader {
+	return func(page KeysetPage) (interface{}, error) {
+		a.Equal(t, expectedPage, page)
+
+		res := []TestUser{}
+		for _, user := range testUsers {
+			key := strconv.Itoa(user.ID)
+			if page.After != nil && key <= *page.After {
+				continue
+			}
+			if page.Before != nil && key >= *page.Before {
+				continue
+			}
+			res = append(res, user)
+		}
+
+		if page.Limit > 0 && len(res) > page.Limit {
+			if page.FromEnd {
+				res = res[len(res)-page.Limit:]
+			} else {
+				res = res[:page.Limit]
+			}
+		}
+		return res, nil
+	}
+}
+
+func testUserKey(node interface{}) string {
+	return strconv.Itoa(node.(TestUser).ID)
+}
+
+func TestFromKeyset(t *testing.T) {
+	options := []struct {
+		name            string
+		args            ConnectionArgs
+		page            KeysetPage
+		ids             []int
+		hasPreviousPage bool
+		hasNextPage     bool
+	}{
+		{"all", ConnectionArgs{}, KeysetPage{}, []int{1, 2, 3, 4, 5}, false, false},
+		{"first", ConnectionArgs{First: intPtr(2)}, KeysetPage{Limit: 3}, []int{1, 2}, false, true},
+		{"first all", ConnectionArgs{First: intPtr(5)}, KeysetPage{Limit: 6}, []int{1, 2, 3, 4, 5}, false, false},
+		{"first after", ConnectionArgs{First: intPtr(2), After: strPtr(KeyCursor("3"))}, KeysetPage{After: strPtr("3"), Limit: 3}, []int{4, 5}, false, false},
+		{"last", ConnectionArgs{Last: intPtr(2)}, KeysetPage{Limit: 3, FromEnd: true}, []int{4, 5}, true, false},
+		{"last before", ConnectionArgs{Last: intPtr(1), Before: strPtr(KeyCursor("3"))}, KeysetPage{Before: strPtr("3"), Limit: 2, FromEnd: true}, []int{2}, true, false},
+		{"first and last", ConnectionArgs{First: intPtr(3), Last: intPtr(1)}, KeysetPage{Limit: 4}, []int{3}, true, true},
+	}
+
+	for _, option := range options {
+		var res TestUserConnection
+		err := FromKeyset(option.args, testKeysetLoader(t, option.page), testUserKey, &res)
+		a.NoError(t, err, option.name)
+		a.Equal(t, option.ids, nodeIDs(res), option.name)
+		a.Equal(t, option.hasPreviousPage, res.PageInfo.HasPreviousPage, option.name)
+		a.Equal(t, option.hasNextPage, res.PageInfo.HasNextPage, option.name)
+		a.Nil(t, res.TotalCount, option.name)
+
+		for _, edge := range res.Edges {
+			key, err := DecodeKeyCursor(edge.Cursor)
+			a.NoError(t, err, option.name)
+			a.Equal(t, strconv.Itoa(edge.Node.ID), key, option.name)
+		}
+	}
+
+	var res TestUserConnection
+	a.Equal(t, ErrInvalidCursor, FromKeyset(ConnectionArgs{After: strPtr(OffsetCursor(1))}, nil, testUserKey, &res))
+}
+
+type TestQueryRoot struct{}
+
+func (TestQueryRoot) ResolveUsers(args ConnectionArgs) (TestUserConnection, error) {
+	var res TestUserConnection
+	err := FromSlice(testUsers, args, &res)
+	return res, err
+}
+
+func TestConnectionSchema(t *testing.T) {
+	s := yarql.NewSchema()
+	err := s.Parse(TestQueryRoot{}, struct{ Foo string }{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	a.True(t, strings.Contains(sdl, "  users(after: String, before: String, first: Int, last: Int): TestUserConnection!\n"), sdl)
+	a.True(t, strings.Contains(sdl, "type PageInfo {\n  endCursor: String\n  hasNextPage: Boolean!\n  hasPreviousPage: Boolean!\n  startCursor: String\n}\n"), sdl)
+	a.True(t, strings.Contains(sdl, "type TestUserEdge {\n  cursor: String!\n  node: TestUser!\n}\n"), sdl)
+
+	query := `query ($after: String) {
+		users(first: 2, after: $after) {
+			totalCount
+			edges {node {name}}
+			pageInfo {hasNextPage endCursor}
+		}
+	}`
+	errs := s.Resolve([]byte(query), yarql.ResolveOptions{
+		NoMeta:    true,
+		Variables: `{"after": "` + OffsetCursor(2) + `"}`,
+	})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"users":{"totalCount":5,"edges":[{"node":{"name":"d"}},{"node":{"name":"e"}}],"pageInfo":{"hasNextPage":false,"endCursor":"`+OffsetCursor(4)+`"}}}`, string(s.Result))
+
+	errs = s.Resolve([]byte(`{users(first: -1) {totalCount}}`), yarql.ResolveOptions{NoMeta: true})
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "first cannot be negative", errs[0].Error())
+}
+
+type TestFriend struct {
+	Name string
+}
+
+func (TestFriend) ResolveFriends(args ConnectionArgs) (TestFriendConnection, error) {
+	var res TestFriendConnection
+	err := FromSlice([]*TestFriend{{Name: "c"}}, args, &res)
+	return res, err
+}
+
+type TestFriendConnection struct {
+	Connection
+	Node TestFriend
+}
+
+type TestGeneratedQueryRoot struct{}
+
+func (TestGeneratedQueryRoot) ResolveFriends(args ConnectionArgs) (TestFriendConnection, error) {
+	var res TestFriendConnection
+	err := FromSlice([]TestFriend{{Name: "a"}, {Name: "b"}}, args, &res)
+	return res, err
+}
+
+func TestFromSliceConnection(t *testing.T) {
+	var res TestFriendConnection
+	err := FromSlice([]*TestFriend{{Name: "a"}, {Name: "b"}}, ConnectionArgs{First: intPtr(1)}, &res)
+	a.NoError(t, err)
+	a.Equal(t, 1, len(res.Edges))
+	a.Equal(t, OffsetCursor(0), res.Edges[0].Cursor)
+	a.Equal(t, TestFriend{Name: "a"}, res.Edges[0].Node)
+	a.True(t, res.PageInfo.HasNextPage)
+	a.Equal(t, 2, *res.TotalCount)
+
+	var invalid struct{ Connection }
+	err = FromSlice(testUsers, ConnectionArgs{}, &invalid)
+	a.Error(t, err)
+}
+
+func TestGeneratedConnectionSchema(t *testing.T) {
+	s := yarql.NewSchema()
+	err := s.Parse(TestGeneratedQueryRoot{}, struct{ Foo string }{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	a.True(t, strings.Contains(sdl, "  friends(after: String, before: String, first: Int, last: Int): TestFriendConnection!\n"), sdl)
+	a.True(t, strings.Contains(sdl, "type TestFriendConnection {\n  edges: [TestFriendEdge!]\n  pageInfo: PageInfo!\n  totalCount: Int\n}\n"), sdl)
+	a.True(t, strings.Contains(sdl, "type TestFriendEdge {\n  cursor: String!\n  node: TestFriend!\n}\n"), sdl)
+
+	query := `{
+		friends(last: 1) {
+			totalCount
+			edges {cursor node {name friends {edges {node {name}}}}}
+			pageInfo {hasPreviousPage}
+		}
+	}`
+	errs := s.Resolve([]byte(query), yarql.ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"friends":{"totalCount":2,"edges":[{"cursor":"`+OffsetCursor(1)+`","node":{"name":"b","friends":{"edges":[{"node":{"name":"c"}}]}}}],"pageInfo":{"hasPreviousPage":true}}}`, string(s.Result))
+}
@@ -0,0 +1,117 @@
+// Package relay contains helpers to create relay cursor connections
+// https://relay.dev/graphql/connections.htm
+//
+// The package handles the connection arguments, the opaque cursors, selecting the page of nodes and filling the edges
+// and page info. A connection is a struct embedding Connection with a Node field that sets the node type,
+// yarql generates the UserConnection and UserEdge graphql types from it:
+//   type UserConnection struct {
+//     relay.Connection
+//     Node User
+//   }
+//
+//   func (QueryRoot) ResolveUsers(args relay.ConnectionArgs) (UserConnection, error) {
+//     var res UserConnection
+//     err := relay.FromSlice(users, args, &res)
+//     return res, err
+//   }
+package relay
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ConnectionArgs are the pagination arguments of a connection field
+type ConnectionArgs struct {
+	// First returns at most first nodes after the After cursor
+	First *int
+	// After is the cursor of the node after which nodes are returned
+	After *string
+	// Last returns at most last nodes before the Before cursor
+	Last *int
+	// Before is the cursor of the node before which nodes are returned
+	Before *string
+}
+
+// Connection contains the edges, page info and total count of a connection
+// Embed it in a struct with a Node field to create a connection of that node type, see the package docs
+type Connection struct {
+	Edges      []Edge
+	PageInfo   PageInfo
+	TotalCount *int
+}
+
+// Edge is a edge of a Connection, the node is of the type of the Node field of the connection struct
+// Note that yarql resolves edges using the index of the fields, keep Cursor and Node in this order
+type Edge struct {
+	Cursor string
+	Node   interface{}
+}
+
+// PageInfo contains the information about the current page of a connection
+type PageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+func (args ConnectionArgs) validate() error {
+	if args.First != nil && *args.First < 0 {
+		return errors.New("first cannot be negative")
+	}
+	if args.Last != nil && *args.Last < 0 {
+		return errors.New("last cannot be negative")
+	}
+	return nil
+}
+
+// ErrInvalidCursor is returned when the after or before argument is not a valid cursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	offsetCursorPrefix = "offset:"
+	keyCursorPrefix    = "key:"
+)
+
+func encodeCursor(prefix string, value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(prefix + value))
+}
+
+func decodeCursor(prefix string, cursor string) (string, error) {
+	value, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(value), prefix) {
+		return "", ErrInvalidCursor
+	}
+	return string(value[len(prefix):]), nil
+}
+
+// OffsetCursor returns the opaque cursor of the node at offset
+func OffsetCursor(offset int) string {
+	return encodeCursor(offsetCursorPrefix, strconv.Itoa(offset))
+}
+
+// DecodeOffsetCursor returns the offset of a cursor created by OffsetCursor
+func DecodeOffsetCursor(cursor string) (int, error) {
+	value, err := decodeCursor(offsetCursorPrefix, cursor)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return offset, nil
+}
+
+// KeyCursor returns the opaque cursor of a node identified by key, used for keyset pagination
+func KeyCursor(key string) string {
+	return encodeCursor(keyCursorPrefix, key)
+}
+
+// DecodeKeyCursor returns the key of a cursor created by KeyCursor
+func DecodeKeyCursor(cursor string) (string, error) {
+	return decodeCursor(keyCursorPrefix, cursor)
+}
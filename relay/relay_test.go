@@ -0,0 +1,38 @@
+package relay
+
+import (
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+func TestOffsetCursor(t *testing.T) {
+	for _, offset := range []int{0, 1, 1234} {
+		decoded, err := DecodeOffsetCursor(OffsetCursor(offset))
+		a.NoError(t, err)
+		a.Equal(t, offset, decoded)
+	}
+
+	for _, cursor := range []string{"", "1", "%%%", KeyCursor("1"), encodeCursor(offsetCursorPrefix, "-1"), encodeCursor(offsetCursorPrefix, "a")} {
+		_, err := DecodeOffsetCursor(cursor)
+		a.Equal(t, ErrInvalidCursor, err, cursor)
+	}
+}
+
+func TestKeyCursor(t *testing.T) {
+	for _, key := range []string{"", "1", "2021-01-01T00:00:00Z|42"} {
+		decoded, err := DecodeKeyCursor(KeyCursor(key))
+		a.NoError(t, err)
+		a.Equal(t, key, decoded)
+	}
+
+	_, err := DecodeKeyCursor(OffsetCursor(1))
+	a.Equal(t, ErrInvalidCursor, err)
+}
+
+func TestConnectionArgsValidate(t *testing.T) {
+	a.NoError(t, ConnectionArgs{}.validate())
+	a.NoError(t, ConnectionArgs{First: intPtr(0), Last: intPtr(0)}.validate())
+	a.Error(t, ConnectionArgs{First: intPtr(-1)}.validate())
+	a.Error(t, ConnectionArgs{Last: intPtr(-1)}.validate())
+}
@@ -6,10 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
+	"net/http"
 	"reflect"
 	"strconv"
-	"strings"
 	"time"
 	"unsafe"
 
@@ -25,13 +26,26 @@ type Ctx struct {
 	query                    bytecode.ParserCtx
 	charNr                   int
 	context                  *context.Context
+	hasDeadline              bool // the operation has a deadline set via ResolveOptions.Timeout
 	path                     []byte
 	getFormFile              func(key string) (*multipart.FileHeader, error) // Get form file to support file uploading
 	operatorHasArguments     bool
 	operatorArgumentsStartAt int
-	tracingEnabled           bool
-	tracing                  *tracer
-	prefRecordingStartTime   time.Time
+	tracers                  []Tracer      // the tracers of the current operation, empty if tracing is disabled
+	apolloTracer             *ApolloTracer // used for ResolveOptions.Tracing
+	operationKind            byte          // bytecode.OperatorQuery, OperatorMutation or OperatorSubscription, 0 if no operation is executed
+	operationName            []byte
+	metrics                  Metrics
+	writer                   io.Writer // if set the result is flushed to this writer while resolving
+	writerErr                error     // the error returned by writer, once set no more data is written
+	headers                  http.Header
+	noMutations              bool // reject mutation operations, used for GET requests
+	mutationRejected         bool // a mutation was rejected because of noMutations
+	errorsStart              int  // the offset of the errors in (*Schema).Result, only valid if no writer is used
+	requestFailed            bool // the request is invalid, for example because of a syntax error, a unknown field or invalid variables
+	introspectionDisabled    bool // reject the __schema and __type fields
+	serviceSDLDisabled       bool // reject the federation _service field
+	visibility               VisibilityFunc
 
 	rawVariables        string
 	variablesParsed     bool             // the rawVariables are parsed into variables
@@ -56,7 +70,7 @@ func newCtx(s *Schema) *Ctx {
 		reflectValues:          [256]reflect.Value{},
 		currentReflectValueIdx: 0,
 		variablesJSONParser:    &fastjson.Parser{},
-		tracing:                newTracer(),
+		apolloTracer:           NewApolloTracer(),
 	}
 	ctx.ctxReflection = reflect.ValueOf(ctx)
 	return ctx
@@ -66,6 +80,16 @@ func (ctx *Ctx) getGoValue() reflect.Value {
 	return ctx.reflectValues[ctx.currentReflectValueIdx]
 }
 
+// pointerTo returns a pointer to value, if value is not addressable a pointer to a copy of value is returned
+func pointerTo(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return value.Addr()
+	}
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr
+}
+
 func (ctx *Ctx) setNextGoValue(value reflect.Value) {
 	ctx.currentReflectValueIdx++
 	ctx.setGoValue(value)
@@ -104,6 +128,7 @@ func (ctx *Ctx) SetValue(key string, value interface{}) {
 }
 
 // GetContext returns the Go request context
+// If a Tracer is set this is the context returned by Tracer.FieldStart for the field that is being resolved
 func (ctx *Ctx) GetContext() context.Context {
 	if ctx.context == nil {
 		return nil
@@ -115,11 +140,24 @@ func (ctx *Ctx) GetContext() context.Context {
 func (ctx *Ctx) SetContext(newContext context.Context) {
 	if newContext == nil {
 		ctx.context = nil
-	} else if ctx == nil {
-		ctx.context = &newContext
 	} else {
-		*ctx.context = newContext
+		ctx.context = &newContext
+	}
+}
+
+// GetHeader returns the first value of a request header
+// Returns an empty string if the header is not set or the headers are unknown
+func (ctx *Ctx) GetHeader(key string) string {
+	if ctx.headers == nil {
+		return ""
 	}
+	return ctx.headers.Get(key)
+}
+
+// GetHeaders returns all request headers
+// Returns nil if the headers are unknown, for example when using (*Schema).Resolve without the Headers option
+func (ctx *Ctx) GetHeaders() http.Header {
+	return ctx.headers
 }
 
 // GetPath returns the graphql path to the current field json encoded
@@ -146,6 +184,35 @@ func (ctx *Ctx) writeQuoted(b []byte) {
 
 var nullBytes = []byte("null")
 
+// flushThreshold is the amount of bytes (*Schema).Result needs to contain before it's flushed to the writer
+const flushThreshold = 4096
+
+// flush writes the buffered result to the writer if there is one and the buffer is large enough
+// returns true if writing failed
+func (ctx *Ctx) flush(force bool) bool {
+	if ctx.writer == nil {
+		return false
+	}
+	if ctx.writerErr != nil {
+		return true
+	}
+	if !force && len(ctx.schema.Result) < flushThreshold {
+		return false
+	}
+
+	_, ctx.writerErr = ctx.writer.Write(ctx.schema.Result)
+	ctx.schema.Result = ctx.schema.Result[:0]
+	if ctx.writerErr != nil {
+		return true
+	}
+
+	flusher, ok := ctx.writer.(interface{ Flush() })
+	if ok {
+		flusher.Flush()
+	}
+	return false
+}
+
 func (ctx *Ctx) writeNull() {
 	ctx.write(nullBytes)
 }
@@ -159,11 +226,43 @@ type ResolveOptions struct {
 	GetFormFile    func(key string) (*multipart.FileHeader, error) // Get form file to support file uploading
 	Variables      string                                          // Expects valid JSON or empty string
 	Tracing        bool                                            // https://github.com/apollographql/apollo-tracing
+	Tracer         Tracer                                          // Receives the tracing events of the operation, see Tracer
+	Metrics        Metrics                                         // Collects metrics about the operation, see Metrics
+	Timeout        time.Duration                                   // Max duration of the full operation, 0 = no timeout
+	Headers        http.Header                                     // Request headers, resolvers can read them using (*Ctx).GetHeader
+
+	// DisableIntrospection rejects the __schema and __type fields for this request, __typename is still allowed
+	DisableIntrospection bool
+	// DisableServiceSDL rejects the federation _service field for this request
+	// The _service field returns the full SDL of the schema, set it together with DisableIntrospection for requests that don't come from the gateway
+	DisableServiceSDL bool
+	// Visibility hides types and fields for this request, see VisibilityFunc
+	Visibility VisibilityFunc
+
+	noMutations bool // reject mutations, set by the http handler for GET requests
 }
 
 // Resolve resolves a query and returns errors if any
 // The result json is written to (*Schema).Result
 func (s *Schema) Resolve(query []byte, opts ResolveOptions) []error {
+	return s.resolve(query, opts, nil)
+}
+
+// ResolveTo resolves a query and writes the result json to w
+// Unlike Resolve the result is not fully buffered in (*Schema).Result, it's flushed to w in small chunks while
+// resolving, this keeps the memory usage low for big responses
+// If w has a Flush method (like http.ResponseWriter) it is called after every chunk
+//
+// The returned error is the first error returned by w, once writing fails the rest of the query is not resolved
+func (s *Schema) ResolveTo(w io.Writer, query []byte, opts ResolveOptions) ([]error, error) {
+	errs := s.resolve(query, opts, w)
+	if s.ctx.writerErr == nil {
+		s.ctx.flush(true)
+	}
+	return errs, s.ctx.writerErr
+}
+
+func (s *Schema) resolve(query []byte, opts ResolveOptions, w io.Writer) []error {
 	if !s.parsed {
 		fmt.Println("CALL (*yarql.Schema).Parse() before resolve")
 		return []error{errors.New("invalid setup")}
@@ -173,20 +272,26 @@ func (s *Schema) Resolve(query []byte, opts ResolveOptions) []error {
 
 	ctx := s.ctx
 	*ctx = Ctx{
-		schema:                 ctx.schema,
-		query:                  ctx.query,
-		charNr:                 0,
-		context:                nil,
-		path:                   ctx.path[:0],
-		getFormFile:            opts.GetFormFile,
-		rawVariables:           opts.Variables,
-		variablesParsed:        false,
-		variablesJSONParser:    ctx.variablesJSONParser,
-		variables:              ctx.variables,
-		tracingEnabled:         opts.Tracing,
-		tracing:                ctx.tracing,
-		prefRecordingStartTime: ctx.prefRecordingStartTime,
-		ctxReflection:          ctx.ctxReflection,
+		schema:                ctx.schema,
+		query:                 ctx.query,
+		charNr:                0,
+		context:               nil,
+		path:                  ctx.path[:0],
+		getFormFile:           opts.GetFormFile,
+		rawVariables:          opts.Variables,
+		variablesParsed:       false,
+		variablesJSONParser:   ctx.variablesJSONParser,
+		variables:             ctx.variables,
+		tracers:               ctx.tracers[:0],
+		apolloTracer:          ctx.apolloTracer,
+		metrics:               opts.Metrics,
+		ctxReflection:         ctx.ctxReflection,
+		writer:                w,
+		headers:               opts.Headers,
+		noMutations:           opts.noMutations,
+		introspectionDisabled: opts.DisableIntrospection,
+		serviceSDLDisabled:    opts.DisableServiceSDL,
+		visibility:            opts.Visibility,
 
 		reflectValues:          ctx.reflectValues,
 		currentReflectValueIdx: 0,
@@ -195,12 +300,45 @@ func (s *Schema) Resolve(query []byte, opts ResolveOptions) []error {
 		values: opts.Values,
 	}
 	if opts.Tracing {
-		ctx.tracing.reset()
+		ctx.tracers = append(ctx.tracers, ctx.apolloTracer)
+	}
+	if multiTracer, ok := opts.Tracer.(MultiTracer); ok {
+		ctx.tracers = append(ctx.tracers, multiTracer...)
+	} else if opts.Tracer != nil {
+		ctx.tracers = append(ctx.tracers, opts.Tracer)
 	}
 	if opts.Context != nil {
 		ctx.context = &opts.Context
 	}
-	ctx.startTrace()
+	if opts.Timeout > 0 {
+		parent := opts.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		operationContext, cancel := context.WithTimeout(parent, opts.Timeout)
+		defer cancel()
+		ctx.context = &operationContext
+		ctx.hasDeadline = true
+	}
+	var startTime time.Time
+	if ctx.metrics != nil {
+		startTime = time.Now()
+	}
+	var tracingContext context.Context
+	if len(ctx.tracers) > 0 {
+		tracingContext = ctx.GetContext()
+		if tracingContext == nil {
+			tracingContext = context.Background()
+		}
+		for _, tracer := range ctx.tracers {
+			tracingContext = tracer.OperationStart(tracingContext)
+		}
+		// Resolvers and the spans of fields are children of the operation span
+		ctx.context = &tracingContext
+		for _, tracer := range ctx.tracers {
+			tracer.ParseStart(tracingContext)
+		}
+	}
 
 	ctx.query.Query = append(ctx.query.Query[:0], query...)
 
@@ -210,15 +348,16 @@ func (s *Schema) Resolve(query []byte, opts ResolveOptions) []error {
 		ctx.query.ParseQueryToBytecode(nil)
 	}
 
-	if ctx.tracingEnabled {
-		// finish parsing trace
-		ctx.finishTrace(func(offset, duration int64) {
-			ctx.tracing.Parsing.StartOffset = offset
-			ctx.tracing.Parsing.Duration = duration
-		})
-
-		// Set the validation prop
-		ctx.tracing.Validation.StartOffset = time.Now().Sub(ctx.prefRecordingStartTime).Nanoseconds()
+	var parsedTime time.Time
+	if ctx.metrics != nil {
+		parsedTime = time.Now()
+		if ctx.query.CacheLookup {
+			ctx.metrics.BytecodeCache(ctx.query.CacheHit)
+		}
+	}
+	for _, tracer := range ctx.tracers {
+		tracer.ParseEnd(tracingContext, ctx.query.Errors)
+		tracer.ValidationStart(tracingContext)
 	}
 
 	if !opts.NoMeta {
@@ -228,30 +367,57 @@ func (s *Schema) Resolve(query []byte, opts ResolveOptions) []error {
 	if len(ctx.query.Errors) == 0 {
 		ctx.charNr = ctx.query.TargetIdx
 		if ctx.charNr == -1 {
+			ctx.requestFailed = true
 			ctx.write([]byte("{}"))
 			if len(opts.OperatorTarget) > 0 {
 				ctx.err("no operator with name " + opts.OperatorTarget + " found")
 			} else {
 				ctx.err("no operator found")
 			}
+			for _, tracer := range ctx.tracers {
+				tracer.ValidationEnd(tracingContext, ctx.query.Errors)
+			}
 		} else {
+			for _, tracer := range ctx.tracers {
+				tracer.ValidationEnd(tracingContext, nil)
+			}
 			ctx.writeByte('{')
 			ctx.resolveOperation()
 			ctx.writeByte('}')
 		}
 	} else {
+		for _, tracer := range ctx.tracers {
+			tracer.ValidationEnd(tracingContext, nil)
+		}
+		ctx.requestFailed = true
 		ctx.write([]byte("{}"))
 	}
 
-	if !opts.NoMeta {
-		// TODO support custom extensions
+	if len(ctx.tracers) > 0 {
+		operation := ctx.operationInfo()
+		for _, tracer := range ctx.tracers {
+			tracer.OperationEnd(tracingContext, operation)
+		}
+	}
+	if ctx.metrics != nil {
+		ctx.metrics.Operation(OperationMetrics{
+			Name:      string(ctx.operationName),
+			Type:      ctx.operationType(),
+			Parsing:   parsedTime.Sub(startTime),
+			Execution: time.Since(parsedTime),
+			Errors:    len(ctx.query.Errors),
+		})
+	}
 
+	if !opts.NoMeta {
 		// Add errors to output
 		errsLen := len(ctx.query.Errors)
-		if errsLen == 0 && !ctx.tracingEnabled {
+		hasExtensions := ctx.hasTracerExtensions()
+		if errsLen == 0 && !hasExtensions {
 			ctx.write([]byte(`}`))
 		} else {
 			if errsLen != 0 {
+				ctx.errorsStart = len(ctx.schema.Result)
 				ctx.write([]byte(`,"errors":[`))
 				for i, err := range ctx.query.Errors {
 					if i > 0 {
@@ -279,15 +445,9 @@ func (s *Schema) Resolve(query []byte, opts ResolveOptions) []error {
 				ctx.writeByte(']')
 			}
 
-			if ctx.tracingEnabled {
-				ctx.write([]byte(`,"extensions":{"tracing":`))
-				ctx.tracing.finish()
-				tracingJSON, err := json.Marshal(ctx.tracing)
-				if err == nil {
-					ctx.write(tracingJSON)
-				} else {
-					ctx.writeNull()
-				}
+			if hasExtensions {
+				ctx.write([]byte(`,"extensions":{`))
+				ctx.writeTracerExtensions()
 				ctx.write([]byte{'}', '}'})
 			} else {
 				ctx.write([]byte(`,"extensions":{}}`))
@@ -295,6 +455,9 @@ func (s *Schema) Resolve(query []byte, opts ResolveOptions) []error {
 		}
 	}
 
+	// The introspection caches are also used outside of requests, for example by (*Schema).SDL
+	ctx.visibility = nil
+
 	return ctx.query.Errors
 }
 
@@ -347,6 +510,17 @@ func (ctx *Ctx) errf(msg string, args ...interface{}) bool {
 	return ctx.err(fmt.Sprintf(msg, args...))
 }
 
+// validationErr reports a error caused by a invalid query or invalid variables
+// These make the request fail, the http handler responds with a 4xx status code for them
+func (ctx *Ctx) validationErr(msg string) bool {
+	ctx.requestFailed = true
+	return ctx.err(msg)
+}
+
+func (ctx *Ctx) validationErrf(msg string, args ...interface{}) bool {
+	return ctx.validationErr(fmt.Sprintf(msg, args...))
+}
+
 func (ctx *Ctx) readUint32(startAt int) uint32 {
 	data := ctx.query.Res[startAt : startAt+4]
 	return uint32(data[0]) |
@@ -359,12 +533,19 @@ func (ctx *Ctx) resolveOperation() bool {
 	ctx.charNr += 2 // read 0, [ActionOperator], [kind]
 
 	kind := ctx.readInst()
+	ctx.operationKind = kind
 	switch kind {
 	case bytecode.OperatorQuery:
 		ctx.reflectValues[0] = ctx.schema.rootQueryValue
 	case bytecode.OperatorMutation:
+		if ctx.noMutations {
+			ctx.requestFailed = true
+			ctx.mutationRejected = true
+			return ctx.err("mutations can only be executed using a POST request")
+		}
 		ctx.reflectValues[0] = ctx.schema.rootMethodValue
 	case bytecode.OperatorSubscription:
+		ctx.requestFailed = true
 		return ctx.err("subscriptions are not supported")
 	}
 
@@ -372,15 +553,17 @@ func (ctx *Ctx) resolveOperation() bool {
 	directivesCount := ctx.readInst()
 	if directivesCount > 0 {
 		// TODO
-		return ctx.err("operation directives unsupported")
+		return ctx.validationErr("operation directives unsupported")
 	}
 
+	startOfName := ctx.charNr
 	for {
 		// Read name
 		if ctx.readInst() == 0 {
 			break
 		}
 	}
+	ctx.operationName = ctx.query.Res[startOfName : ctx.charNr-1]
 
 	if ctx.operatorHasArguments {
 		argumentsLen := ctx.readUint32(ctx.charNr)
@@ -412,6 +595,9 @@ func (ctx *Ctx) resolveSelectionSet(typeObj *obj, dept uint8, firstField *bool)
 			if !skipped {
 				*firstField = false
 			}
+			if ctx.flush(false) {
+				return true
+			}
 		case bytecode.ActionSpread:
 			criticalErr := ctx.resolveSpread(typeObj, dept, firstField)
 			if criticalErr {
@@ -458,7 +644,7 @@ func (ctx *Ctx) resolveSpread(typeObj *obj, dept uint8, firstField *bool) bool {
 	}
 
 	if isInline {
-		if !bytes.Equal(typeObj.typeNameBytes, name) {
+		if !typeConditionMatches(typeObj, name) {
 			ctx.charNr = nameStart + int(lenOfDirective) + 1
 			return false
 		}
@@ -490,7 +676,7 @@ func (ctx *Ctx) resolveSpread(typeObj *obj, dept uint8, firstField *bool) bool {
 				}
 			}
 
-			if !bytes.Equal(typeObj.typeNameBytes, ctx.query.Res[typeNameStart:typeNameEnd]) {
+			if !typeConditionMatches(typeObj, ctx.query.Res[typeNameStart:typeNameEnd]) {
 				ctx.charNr = nameStart + int(lenOfDirective) + 1
 				return false
 			}
@@ -501,12 +687,10 @@ func (ctx *Ctx) resolveSpread(typeObj *obj, dept uint8, firstField *bool) bool {
 		}
 	}
 
-	return ctx.err("fragment " + b2s(name) + " not defined")
+	return ctx.validationErr("fragment " + b2s(name) + " not defined")
 }
 
 func (ctx *Ctx) resolveField(typeObj *obj, dept uint8, addCommaBefore bool) (skipped bool, criticalErr bool) {
-	ctx.startTrace()
-
 	directivesCount := ctx.readInst()
 
 	fieldLen := ctx.readUint32(ctx.charNr)
@@ -569,51 +753,90 @@ func (ctx *Ctx) resolveField(typeObj *obj, dept uint8, addCommaBefore bool) (ski
 	fieldHasSelection := ctx.seekInst() != 'e'
 
 	typeObjField, ok := typeObj.objContents[nameKey]
+	if ok && typeObjField.hidden {
+		ok = !ctx.introspectionDisabled
+	} else if ok && typeObjField.isServiceSDL {
+		ok = !ctx.serviceSDLDisabled
+	} else if ok && ctx.visibility != nil {
+		ok = ctx.schema.objFieldVisible(typeObj, typeObjField)
+	}
 	if !ok {
 		name := b2s(ctx.query.Res[startOfName:endOfName])
 		if name == "__typename" {
 			if fieldHasSelection {
-				criticalErr = ctx.err("cannot have a selection set on this field")
+				criticalErr = ctx.validationErr("cannot have a selection set on this field")
 			} else {
 				ctx.writeQuoted(typeObj.typeNameBytes)
 			}
+		} else if typeObjField != nil && typeObjField.hidden {
+			ctx.writeNull()
+			criticalErr = ctx.errf("introspection is disabled, %s can't be queried", name)
+		} else if typeObjField != nil && typeObjField.isServiceSDL {
+			ctx.writeNull()
+			criticalErr = ctx.errf("the service SDL is disabled, %s can't be queried", name)
 		} else {
 			ctx.writeNull()
-			criticalErr = ctx.errf("%s does not exists on %s", name, typeObj.typeName)
+			criticalErr = ctx.validationErrf("%s does not exists on %s", name, typeObj.typeName)
 		}
+	} else if fieldOwner, ok := embeddedValue(ctx.getGoValue(), typeObjField.embeddedPath); !ok {
+		// The field is promoted from a nil embedded pointer
+		ctx.writeNull()
 	} else {
 		goValue := ctx.getGoValue()
 		if typeObjField.customObjValue != nil {
 			ctx.setNextGoValue(*typeObjField.customObjValue)
 		} else {
-			name := string(typeObjField.qlFieldName)
-			name = strings.ToUpper(name[:1]) + name[1:]
 			if typeObjField.valueType == valueTypeMethod && typeObjField.method.isTypeMethod {
-				ctx.setNextGoValue(goValue.MethodByName("Resolve" + name))
+				if typeObjField.method.pointerReceiver {
+					goValue = pointerTo(goValue)
+				}
+				ctx.setNextGoValue(goValue.Method(typeObjField.structFieldIdx))
 			} else {
-				ctx.setNextGoValue(goValue.FieldByName(name))
+				ctx.setNextGoValue(fieldOwner.Field(typeObjField.structFieldIdx))
+			}
+		}
+
+		var fieldInfo FieldInfo
+		var fieldStart time.Time
+		var parentContext *context.Context
+		var fieldContext context.Context
+		errsBefore := len(ctx.query.Errors)
+		if ctx.metrics != nil {
+			fieldStart = time.Now()
+		}
+		if len(ctx.tracers) > 0 {
+			fieldInfo = ctx.fieldInfo(typeObj, typeObjField, ctx.query.Res[startOfName:endOfName])
+			parentContext = ctx.context
+			fieldContext = ctx.GetContext()
+			if fieldContext == nil {
+				fieldContext = context.Background()
+			}
+			for _, tracer := range ctx.tracers {
+				fieldContext = tracer.FieldStart(fieldContext, fieldInfo)
 			}
+			// The resolver and sub fields of this field are children of the field span
+			ctx.context = &fieldContext
 		}
 
 		criticalErr = ctx.resolveFieldDataValue(typeObjField, dept, fieldHasSelection)
 		ctx.currentReflectValueIdx--
 
-		if ctx.tracingEnabled {
-			name := b2s(ctx.query.Res[startOfName:endOfName])
-
-			ctx.finishTrace(func(offset, duration int64) {
-				returnType := bytes.NewBuffer(nil)
-				ctx.schema.objToQlTypeName(typeObjField, returnType)
-
-				ctx.tracing.Execution.Resolvers = append(ctx.tracing.Execution.Resolvers, tracerResolver{
-					Path:        ctx.GetPath(),
-					ParentType:  typeObj.typeName,
-					FieldName:   name,
-					ReturnType:  returnType.String(),
-					StartOffset: offset,
-					Duration:    duration,
+		if len(ctx.tracers) > 0 || ctx.metrics != nil {
+			fieldInfo.Errors = ctx.fieldErrors(errsBefore)
+			if len(ctx.tracers) > 0 {
+				ctx.context = parentContext
+			}
+			for _, tracer := range ctx.tracers {
+				tracer.FieldEnd(fieldContext, fieldInfo)
+			}
+			if ctx.metrics != nil {
+				ctx.metrics.Resolver(ResolverMetrics{
+					TypeName:  typeObj.typeName,
+					FieldName: b2s(typeObjField.qlFieldName),
+					Duration:  time.Since(fieldStart),
+					Errors:    len(fieldInfo.Errors),
 				})
-			})
+			}
 		}
 	}
 
@@ -625,25 +848,44 @@ func (ctx *Ctx) resolveField(typeObj *obj, dept uint8, addCommaBefore bool) (ski
 	return false, criticalErr
 }
 
-func (ctx *Ctx) callQlMethod(method *objMethod, goValue *reflect.Value, parseArguments bool) ([]reflect.Value, bool) {
+func (ctx *Ctx) callQlMethod(method *objMethod, goValue *reflect.Value, parseArguments bool, timeout time.Duration) ([]reflect.Value, bool) {
 	ctx.funcInputs = ctx.funcInputs[:0]
 	for _, in := range method.ins {
 		if in.isCtx {
 			ctx.funcInputs = append(ctx.funcInputs, ctx.ctxReflection)
+		} else if in.isContext {
+			goContext := ctx.GetContext()
+			if goContext == nil {
+				goContext = context.Background()
+			}
+			ctx.funcInputs = append(ctx.funcInputs, reflect.ValueOf(goContext))
 		} else {
 			ctx.funcInputs = append(ctx.funcInputs, reflect.New(*in.goType).Elem())
 		}
 	}
 
+	if method.hasDefaults {
+		for _, inField := range method.inFields {
+			if inField.input.defaultJSON == nil {
+				continue
+			}
+			goField := inputField(ctx.funcInputs[inField.inputIdx], &inField.input)
+			_, criticalErr := ctx.bindJSONToValue(&goField, &inField.input, inField.input.defaultJSON)
+			if criticalErr {
+				return nil, criticalErr
+			}
+		}
+	}
+
 	if parseArguments {
 		criticalErr := ctx.walkInputObject(
 			func(key []byte) bool {
 				keyStr := b2s(key)
 				inField, ok := method.inFields[keyStr]
 				if !ok {
-					return ctx.err("undefined input: " + keyStr)
+					return ctx.validationErr("undefined input: " + keyStr)
 				}
-				goField := ctx.funcInputs[inField.inputIdx].Field(inField.input.goFieldIdx)
+				goField := inputField(ctx.funcInputs[inField.inputIdx], &inField.input)
 				_, criticalErr := ctx.bindInputToGoValue(&goField, &inField.input, true)
 				return criticalErr
 			},
@@ -653,8 +895,110 @@ func (ctx *Ctx) callQlMethod(method *objMethod, goValue *reflect.Value, parseArg
 		}
 	}
 
-	outs := goValue.Call(ctx.funcInputs)
-	return outs, false
+	if timeout <= 0 && !ctx.hasDeadline {
+		return goValue.Call(ctx.funcInputs), false
+	}
+	if ctx.hasDeadline && ctx.operationTimedOut() {
+		return nil, false
+	}
+	return ctx.callQlMethodWithDeadline(method, goValue, timeout)
+}
+
+// operationTimedOut reports a error and returns true if the operation deadline set via ResolveOptions.Timeout is reached
+func (ctx *Ctx) operationTimedOut() bool {
+	if ctx.GetContext().Err() != context.DeadlineExceeded {
+		return false
+	}
+	ctx.err("operation timed out")
+	return true
+}
+
+// callQlMethodWithDeadline calls the method in a separate goroutine so we can stop waiting for it once
+// the field timeout or operation deadline is reached, whichever comes first
+//
+// The method might still be running after we have moved on to the next field or request so it gets it's own
+// *Ctx and copies of everything reachable through it, values set by the method are copied back if it finishes in time
+func (ctx *Ctx) callQlMethodWithDeadline(method *objMethod, goValue *reflect.Value, timeout time.Duration) ([]reflect.Value, bool) {
+	parent := ctx.GetContext()
+	if parent == nil {
+		parent = context.Background()
+	}
+	var methodContext context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		methodContext, cancel = context.WithTimeout(parent, timeout)
+	} else {
+		// Only the remaining time of the operation deadline applies
+		methodContext, cancel = context.WithCancel(parent)
+	}
+	defer cancel()
+
+	methodCtx := &Ctx{
+		context:     &methodContext,
+		path:        append([]byte{}, ctx.path...),
+		getFormFile: ctx.getFormFile,
+		headers:     ctx.headers.Clone(),
+	}
+	if ctx.values != nil {
+		values := make(map[string]interface{}, len(*ctx.values))
+		for key, value := range *ctx.values {
+			values[key] = value
+		}
+		methodCtx.values = &values
+	}
+
+	inputs := make([]reflect.Value, len(method.ins))
+	for idx, in := range method.ins {
+		if in.isCtx {
+			inputs[idx] = reflect.ValueOf(methodCtx)
+		} else if in.isContext {
+			inputs[idx] = reflect.ValueOf(methodContext)
+		} else {
+			inputs[idx] = ctx.funcInputs[idx]
+		}
+	}
+
+	type methodResult struct {
+		outs      []reflect.Value
+		recovered interface{}
+	}
+	fn := *goValue
+	done := make(chan methodResult, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- methodResult{recovered: recovered}
+			}
+		}()
+		done <- methodResult{outs: fn.Call(inputs)}
+	}()
+
+	select {
+	case res := <-done:
+		if res.recovered != nil {
+			ctx.errf("resolver panicked: %v", res.recovered)
+			return nil, false
+		}
+		if methodCtx.values != nil {
+			if ctx.values == nil {
+				ctx.values = methodCtx.values
+			} else {
+				for key, value := range *methodCtx.values {
+					(*ctx.values)[key] = value
+				}
+			}
+		}
+		return res.outs, false
+	case <-methodContext.Done():
+		if parent.Err() == nil && timeout > 0 {
+			ctx.errf("field timed out after %s", timeout)
+		} else if ctx.hasDeadline && parent.Err() == context.DeadlineExceeded {
+			ctx.err("operation timed out")
+		} else {
+			ctx.err(parent.Err().Error())
+		}
+		return nil, false
+	}
 }
 
 func (ctx *Ctx) resolveDirective(location DirectiveLocation) (modifer DirectiveModifier, criticalErr bool) {
@@ -673,7 +1017,7 @@ func (ctx *Ctx) resolveDirective(location DirectiveLocation) (modifer DirectiveM
 	directiveName := b2s(ctx.query.Res[nameStart:nameEnd])
 	directives, ok := ctx.schema.definedDirectives[location]
 	if !ok {
-		return modifer, ctx.err("unknown directive " + directiveName)
+		return modifer, ctx.validationErr("unknown directive " + directiveName)
 	}
 
 	var foundDirective *Directive
@@ -685,13 +1029,13 @@ func (ctx *Ctx) resolveDirective(location DirectiveLocation) (modifer DirectiveM
 	}
 
 	if foundDirective == nil {
-		return modifer, ctx.err("unknown directive " + directiveName)
+		return modifer, ctx.validationErr("unknown directive " + directiveName)
 	}
 	method := foundDirective.parsedMethod
 
-	outs, criticalErr := ctx.callQlMethod(method, &foundDirective.methodReflection, hasArguments)
-	if criticalErr {
-		return modifer, criticalErr
+	outs, criticalErr := ctx.callQlMethod(method, &foundDirective.methodReflection, hasArguments, 0)
+	if criticalErr || outs == nil {
+		return modifer, true
 	}
 
 	modifer = outs[0].Interface().(DirectiveModifier)
@@ -710,7 +1054,7 @@ func (ctx *Ctx) resolveFieldDataValue(typeObj *obj, dept uint8, hasSubSelection
 		if resolvedTypeObj.valueType != valueTypeMethod {
 			// arguments are not allowed on any other value than methods
 			ctx.writeNull()
-			return ctx.err("field arguments not allowed")
+			return ctx.validationErr("field arguments not allowed")
 		}
 	}
 
@@ -723,6 +1067,20 @@ func (ctx *Ctx) resolveFieldDataValue(typeObj *obj, dept uint8, hasSubSelection
 			ctx.writeNull()
 			return false
 		}
+		if typeObj.mapEntryType != nil {
+			if goValue.IsNil() {
+				ctx.writeNull()
+				return false
+			}
+			goValue = mapEntries(goValue, typeObj.mapEntryType)
+		} else if typeObj.edgeType != nil {
+			var err error
+			goValue, err = connectionEdges(goValue, typeObj.edgeType)
+			if err != nil {
+				ctx.writeNull()
+				return ctx.err(err.Error())
+			}
+		}
 
 		typeObj = typeObj.innerContent
 
@@ -746,13 +1104,17 @@ func (ctx *Ctx) resolveFieldDataValue(typeObj *obj, dept uint8, hasSubSelection
 			}
 
 			ctx.path = ctx.path[:prefPathLen]
+
+			if ctx.flush(false) {
+				break
+			}
 		}
 		ctx.currentReflectValueIdx--
 		ctx.writeByte(']')
 	case valueTypeObj, valueTypeObjRef:
 		if !hasSubSelection {
 			ctx.writeNull()
-			return ctx.err("must have a selection")
+			return ctx.validationErr("must have a selection")
 		}
 
 		var ok bool
@@ -778,11 +1140,16 @@ func (ctx *Ctx) resolveFieldDataValue(typeObj *obj, dept uint8, hasSubSelection
 	case valueTypeData:
 		if hasSubSelection {
 			ctx.writeNull()
-			return ctx.err("cannot have a selection set on this field")
+			return ctx.validationErr("cannot have a selection set on this field")
 		}
 
-		if typeObj.isID && typeObj.dataValueType != reflect.String {
-			// Graphql ID fields are always strings
+		if ctx.schema.strictInt && !typeObj.isID && !typeObj.isLong && intOutOfRange(goValue, typeObj.dataValueType) {
+			ctx.writeNull()
+			return ctx.errf("Int cannot represent non 32-bit signed integer value: %v", goValue.Interface())
+		}
+
+		if (typeObj.isID || typeObj.isLong) && typeObj.dataValueType != reflect.String {
+			// Graphql ID and Long fields are always strings
 			ctx.writeByte('"')
 			ctx.valueToJSON(goValue, typeObj.dataValueType)
 			ctx.writeByte('"')
@@ -804,10 +1171,16 @@ func (ctx *Ctx) resolveFieldDataValue(typeObj *obj, dept uint8, hasSubSelection
 			return false
 		}
 
-		outs, criticalErr := ctx.callQlMethod(method, &goValue, ctx.seekInst() == 'v')
+		outs, criticalErr := ctx.callQlMethod(method, &goValue, ctx.seekInst() == 'v', typeObj.timeout)
 		if criticalErr {
+			ctx.writeNull()
 			return criticalErr
 		}
+		if outs == nil {
+			// The method did not finish in time or panicked, the error is already reported
+			ctx.writeNull()
+			return false
+		}
 
 		hasSubSelection = ctx.seekInst() != 'e'
 		if method.errorOutNr != nil {
@@ -837,47 +1210,42 @@ func (ctx *Ctx) resolveFieldDataValue(typeObj *obj, dept uint8, hasSubSelection
 		criticalErr = ctx.resolveFieldDataValue(&method.outType, dept, hasSubSelection)
 		return criticalErr
 	case valueTypeEnum:
-		enum := ctx.schema.definedEnums[typeObj.enumTypeIndex]
-		switch enum.contentKind {
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			underlayingValue := goValue.Int()
-			for _, entry := range enum.entries {
-				if entry.value.Int() == underlayingValue {
-					ctx.writeQuoted(entry.keyBytes)
-					return false
-				}
-			}
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			underlayingValue := goValue.Uint()
-			for _, entry := range enum.entries {
-				if entry.value.Uint() == underlayingValue {
-					ctx.writeQuoted(entry.keyBytes)
-					return false
-				}
-			}
-		case reflect.String:
-			underlayingValue := goValue.String()
-			for _, entry := range enum.entries {
-				if entry.value.String() == underlayingValue {
-					ctx.writeQuoted(entry.keyBytes)
-					return false
-				}
-			}
+		enum := &ctx.schema.definedEnums[typeObj.enumTypeIndex]
+		entry := enum.entryForValue(goValue)
+		if entry == nil {
+			ctx.writeNull()
+			return ctx.errf("%v is not a valid value for enum %s", goValue, enum.typeName)
 		}
-		ctx.writeNull()
+		ctx.writeQuoted(entry.keyBytes)
 	case valueTypeTime:
 		timeValue, ok := goValue.Interface().(time.Time)
 		if ok {
-			ctx.writeByte('"')
-			helpers.TimeToIso8601String(&ctx.schema.Result, timeValue)
-			ctx.writeByte('"')
+			ctx.writeTime(timeValue, typeObj.timeLayout)
 		} else {
 			ctx.writeNull()
 		}
+	case valueTypeJSON:
+		if hasSubSelection {
+			ctx.writeNull()
+			return ctx.validationErr("cannot have a selection set on this field")
+		}
+		return ctx.writeJSONValue(goValue)
+	case valueTypeText:
+		if hasSubSelection {
+			ctx.writeNull()
+			return ctx.validationErr("cannot have a selection set on this field")
+		}
+		return ctx.writeTextValue(goValue)
+	case valueTypeBase64:
+		if hasSubSelection {
+			ctx.writeNull()
+			return ctx.validationErr("cannot have a selection set on this field")
+		}
+		ctx.writeBase64Value(goValue)
 	case valueTypeInterface, valueTypeInterfaceRef:
 		if !hasSubSelection {
 			ctx.writeNull()
-			return ctx.err("must have a selection")
+			return ctx.validationErr("must have a selection")
 		}
 
 		var ok bool
@@ -896,24 +1264,45 @@ func (ctx *Ctx) resolveFieldDataValue(typeObj *obj, dept uint8, hasSubSelection
 
 		if goValue.Kind() == reflect.Interface {
 			goValue = goValue.Elem()
-			ctx.setNextGoValue(goValue)
 		}
-
-		goValueType := goValue.Type()
-		goValueName := goValueType.Name()
-		goValuePkgPath := goValueType.PkgPath()
+		if goValue.Kind() == reflect.Ptr {
+			// A pointer to a struct implementing the interface
+			if goValue.IsNil() {
+				ctx.writeNull()
+				return false
+			}
+			goValue = goValue.Elem()
+		}
 
 		// TODO improve performance of the below
-		for _, implementation := range typeObj.implementations {
-			if implementation.goTypeName == goValueName && implementation.goPkgPath == goValuePkgPath {
-				criticalErr := ctx.resolveFieldDataValue(implementation, dept+1, hasSubSelection)
-				ctx.currentReflectValueIdx--
-				return criticalErr
+		implementation := findImplementation(typeObj, goValue.Type())
+		if implementation == nil && ctx.schema.resolveType != nil {
+			resolved := reflect.ValueOf(ctx.schema.resolveType(typeObj.typeName, goValue.Interface()))
+			if resolved.Kind() == reflect.Ptr && !resolved.IsNil() {
+				resolved = resolved.Elem()
+			}
+			if !resolved.IsValid() || resolved.Kind() == reflect.Ptr {
+				ctx.writeNull()
+				return false
 			}
+
+			implementation = findImplementation(typeObj, resolved.Type())
+			if implementation == nil {
+				ctx.writeNull()
+				return ctx.errf("ResolveType returned %s which does not implement %s", resolved.Type().String(), typeObj.typeName)
+			}
+			goValue = resolved
+		}
+		if implementation == nil || !ctx.schema.typeVisible(implementation.typeName) {
+			// Hidden implementations are handled as a unknown implementation
+			ctx.writeNull()
+			return false
 		}
 
+		ctx.setNextGoValue(goValue)
+		criticalErr := ctx.resolveFieldDataValue(implementation, dept+1, hasSubSelection)
 		ctx.currentReflectValueIdx--
-		ctx.writeNull()
+		return criticalErr
 	}
 
 	return false
@@ -961,11 +1350,15 @@ func (ctx *Ctx) bindOperatorArgumentTo(goValue *reflect.Value, valueStructure *i
 	resolvedValueStructure := valueStructure
 	c := ctx.readInst()
 	for {
+		for resolvedValueStructure.isOptional {
+			// Optional values are bound to the Value field
+			resolvedValueStructure = resolvedValueStructure.elem
+		}
 		if c != 'L' && c != 'l' {
 			break
 		}
-		if resolvedValueStructure.kind != reflect.Slice {
-			return false, ctx.err("variable $" + argumentName + " cannot be bind to " + resolvedValueStructure.kind.String())
+		if resolvedValueStructure.isJSON || resolvedValueStructure.isText || resolvedValueStructure.isBase64 || (resolvedValueStructure.kind != reflect.Slice && resolvedValueStructure.kind != reflect.Map) {
+			return false, ctx.validationErr("variable $" + argumentName + " cannot be bind to " + resolvedValueStructure.kind.String())
 		}
 		resolvedValueStructure = resolvedValueStructure.elem
 		c = ctx.readInst()
@@ -987,45 +1380,66 @@ func (ctx *Ctx) bindOperatorArgumentTo(goValue *reflect.Value, valueStructure *i
 		if resolvedValueStructure.isEnum {
 			enum := ctx.schema.definedEnums[resolvedValueStructure.enumTypeIndex]
 			if typeName != enum.typeName && typeName != "String" {
-				return false, ctx.err("expected variable type " + enum.typeName + " but got " + typeName)
+				return false, ctx.validationErr("expected variable type " + enum.typeName + " but got " + typeName)
 			}
 		} else if resolvedValueStructure.isID {
 			if typeName != "ID" && typeName != "String" {
-				return false, ctx.err("expected variable type ID but got " + typeName)
+				return false, ctx.validationErr("expected variable type ID but got " + typeName)
 			}
 		} else if resolvedValueStructure.isFile {
-			if typeName != "File" && typeName != "String" {
-				return false, ctx.err("expected variable type File but got " + typeName)
+			if typeName != "File" && typeName != "Upload" && typeName != "String" {
+				return false, ctx.validationErr("expected variable type File but got " + typeName)
 			}
 		} else if resolvedValueStructure.isTime {
 			if typeName != "Time" && typeName != "String" {
-				return false, ctx.err("expected variable type Time but got " + typeName)
+				return false, ctx.validationErr("expected variable type Time but got " + typeName)
+			}
+		} else if resolvedValueStructure.isAny {
+			if typeName != "_Any" {
+				return false, ctx.validationErr("expected variable type _Any but got " + typeName)
+			}
+		} else if resolvedValueStructure.isJSON {
+			if typeName != "JSON" {
+				return false, ctx.validationErr("expected variable type JSON but got " + typeName)
+			}
+		} else if resolvedValueStructure.isText {
+			scalarName := textScalarName(resolvedValueStructure.scalarName)
+			if typeName != scalarName {
+				return false, ctx.validationErr("expected variable type " + scalarName + " but got " + typeName)
+			}
+		} else if resolvedValueStructure.isBase64 {
+			if typeName != "String" {
+				return false, ctx.validationErr("expected variable type String but got " + typeName)
+			}
+		} else if resolvedValueStructure.isLong {
+			if typeName != ctx.schema.longScalarName && typeName != "Int" {
+				return false, ctx.validationErr("expected variable type " + ctx.schema.longScalarName + " but got " + typeName)
 			}
 		} else {
 			switch resolvedValueStructure.kind {
 			case reflect.Bool:
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 				if typeName != "Int" {
-					return false, ctx.err("expected variable type Int but got " + typeName)
+					return false, ctx.validationErr("expected variable type Int but got " + typeName)
 				}
 			case reflect.Float32, reflect.Float64:
 				if typeName != "Float" {
-					return false, ctx.err("expected variable type Float but got " + typeName)
+					return false, ctx.validationErr("expected variable type Float but got " + typeName)
 				}
 			case reflect.Array, reflect.Slice:
 				if typeName != "List" {
-					return false, ctx.err("expected variable type List but got " + typeName)
+					return false, ctx.validationErr("expected variable type List but got " + typeName)
 				}
 			case reflect.String:
 				if typeName != "String" {
-					return false, ctx.err("expected variable type String but got " + typeName)
+					return false, ctx.validationErr("expected variable type String but got " + typeName)
 				}
 			case reflect.Struct:
 				if typeName != resolvedValueStructure.structName {
-					return false, ctx.err("expected variable type " + resolvedValueStructure.structName + " but got " + typeName)
+					return false, ctx.validationErr("expected variable type " + resolvedValueStructure.structName + " but got " + typeName)
 				}
 			default:
-				return false, ctx.err("cannot set field using variable")
+				return false, ctx.validationErr("cannot set field using variable")
 			}
 		}
 	}
@@ -1042,7 +1456,11 @@ func (ctx *Ctx) bindOperatorArgumentTo(goValue *reflect.Value, valueStructure *i
 	}
 
 	if !hasDefaultValue {
-		return false, ctx.err("variable has no value nor default")
+		if valueStructure.isOptional {
+			// The variable is omitted so the optional value stays unset
+			return false, false
+		}
+		return false, ctx.validationErr("variable has no value nor default")
 	}
 
 	return ctx.bindInputToGoValue(goValue, valueStructure, false)
@@ -1061,7 +1479,7 @@ func (ctx *Ctx) bindExternalVariableValue(goValue *reflect.Value, valueStructure
 			return false, false, ctx.err(err.Error())
 		}
 		if ctx.variables.Type() != fastjson.TypeObject {
-			return false, false, ctx.err("variables provided must be of type object")
+			return false, false, ctx.validationErr("variables provided must be of type object")
 		}
 	}
 
@@ -1075,57 +1493,101 @@ func (ctx *Ctx) bindExternalVariableValue(goValue *reflect.Value, valueStructure
 }
 
 func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, jsonData *fastjson.Value) (valueSet bool, criticalErr bool) {
+	if valueStructure.isOptional {
+		isNull := jsonData.Type() == fastjson.TypeNull
+		return true, bindOptionalValue(goValue, valueStructure, isNull, func(valueGoValue *reflect.Value, valueInput *input) bool {
+			if isNull {
+				return false
+			}
+			_, criticalErr := ctx.bindJSONToValue(valueGoValue, valueInput, jsonData)
+			return criticalErr
+		})
+	}
+
 	var isPtr bool
-	isPtr, valueSet, criticalErr = ctx.checkInputIsPtr(goValue, valueStructure, func(goValue *reflect.Value, input *input) (valueSet bool, criticalErr bool) {
-		return ctx.bindJSONToValue(goValue, input, jsonData)
+	isPtr, valueSet, criticalErr = ctx.checkInputIsPtr(goValue, valueStructure, func(elemValue *reflect.Value, input *input) (valueSet bool, criticalErr bool) {
+		if jsonData.Type() == fastjson.TypeNull {
+			// An explicit null also overwrites the default value
+			if !goValue.IsNil() {
+				goValue.Set(reflect.Zero(goValue.Type()))
+			}
+			return false, false
+		}
+		return ctx.bindJSONToValue(elemValue, input, jsonData)
 	})
 	if isPtr {
 		return
 	}
 
+	if valueStructure.isAny {
+		return ctx.assignAnyValue(goValue, jsonToInterface(jsonData))
+	}
+	if valueStructure.isJSON {
+		if goValue.Type() == jsonRawMessageType {
+			goValue.SetBytes(jsonData.MarshalTo(nil))
+			return true, false
+		}
+		return ctx.assignJSONValue(goValue, jsonToInterface(jsonData))
+	}
+	if valueStructure.isLong {
+		switch jsonData.Type() {
+		case fastjson.TypeNull:
+			// keep goValue at it's default
+			return false, false
+		case fastjson.TypeString:
+			return true, ctx.assignLongValue(goValue, b2s(jsonData.GetStringBytes()))
+		case fastjson.TypeNumber:
+			return true, ctx.assignLongValue(goValue, jsonData.String())
+		default:
+			return false, ctx.validationErr("cannot assign " + jsonData.Type().String() + " to " + ctx.schema.longScalarName + " value")
+		}
+	}
+	if valueStructure.isText || valueStructure.isBase64 {
+		switch jsonData.Type() {
+		case fastjson.TypeNull:
+			// keep goValue at it's default
+			return false, false
+		case fastjson.TypeString:
+			return true, ctx.assignTextValue(goValue, valueStructure, b2s(jsonData.GetStringBytes()))
+		default:
+			return false, ctx.validationErr("cannot assign " + jsonData.Type().String() + " to " + textScalarName(valueStructure.scalarName) + " value")
+		}
+	}
+
 	jsonDataType := jsonData.Type()
 	if valueStructure.isEnum || valueStructure.isID || valueStructure.isFile || valueStructure.isTime {
 		if jsonDataType != fastjson.TypeString {
 			if valueStructure.isEnum {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to Enum value")
+				return false, ctx.validationErr("cannot assign " + jsonDataType.String() + " to Enum value")
 			} else if valueStructure.isID {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to ID value")
+				return false, ctx.validationErr("cannot assign " + jsonDataType.String() + " to ID value")
 			} else if valueStructure.isFile {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to File value")
+				return false, ctx.validationErr("cannot assign " + jsonDataType.String() + " to File value")
 			} else if valueStructure.isTime {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to Time value")
+				return false, ctx.validationErr("cannot assign " + jsonDataType.String() + " to Time value")
 			} else {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to this field's value")
+				return false, ctx.validationErr("cannot assign " + jsonDataType.String() + " to this field's value")
 			}
 		}
 		stringValue := b2s(jsonData.GetStringBytes())
 
 		if valueStructure.isEnum {
 			if jsonDataType != fastjson.TypeString {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to ID value")
-			}
-
-			enum := ctx.schema.definedEnums[valueStructure.enumTypeIndex]
-			for _, entry := range enum.entries {
-				if entry.key == stringValue {
-					switch enum.contentKind {
-					case reflect.String:
-						goValue.SetString(entry.value.String())
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-						goValue.SetInt(entry.value.Int())
-					case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-						goValue.SetUint(entry.value.Uint())
-					default:
-						return false, ctx.err("internal error, type missmatch on enum")
-					}
-					return true, false
+				return false, ctx.validationErr("cannot assign " + jsonDataType.String() + " to ID value")
+			}
+
+			enum := &ctx.schema.definedEnums[valueStructure.enumTypeIndex]
+			if entry := enum.entryForKey(stringValue); entry != nil {
+				if !enum.setValue(goValue, entry) {
+					return false, ctx.err("internal error, type missmatch on enum")
 				}
+				return true, false
 			}
 
-			return false, ctx.errf("unknown enum value %s for enum %s", stringValue, enum.typeName)
+			return false, ctx.validationErrf("unknown enum value %s for enum %s", stringValue, enum.typeName)
 		} else if valueStructure.isID {
 			if jsonDataType != fastjson.TypeString {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to ID value")
+				return false, ctx.validationErr("cannot assign " + jsonDataType.String() + " to ID value")
 			}
 
 			switch goValue.Kind() {
@@ -1135,7 +1597,7 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 				intValue, err := strconv.Atoi(stringValue)
 				if err != nil {
-					return false, ctx.err("id argument must match a number type")
+					return false, ctx.validationErr("id argument must match a number type")
 				}
 				// TODO check if the int value can be assigned to int8 - int32
 				goValue.SetInt(int64(intValue))
@@ -1143,20 +1605,20 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 				intValue, err := strconv.Atoi(stringValue)
 				if err != nil {
-					return false, ctx.err("id argument must match a number type")
+					return false, ctx.validationErr("id argument must match a number type")
 				}
 				if intValue < 0 {
-					return false, ctx.err("id argument must match a number above 0")
+					return false, ctx.validationErr("id argument must match a number above 0")
 				}
 				// TODO check if the int value can be assigned to uint8 - uint32
 				goValue.SetUint(uint64(intValue))
 				valueSet = true
 			default:
-				return false, ctx.err("internal error: cannot assign to this ID field")
+				return false, ctx.validationErr("internal error: cannot assign to this ID field")
 			}
 		} else if valueStructure.isFile {
-			if jsonDataType != fastjson.TypeString {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to Time value")
+			if ctx.getFormFile == nil {
+				return false, ctx.err("form files are not supported")
 			}
 
 			file, err := ctx.getFormFile(stringValue)
@@ -1167,10 +1629,10 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 			valueSet = true
 		} else if valueStructure.isTime {
 			if jsonDataType != fastjson.TypeString {
-				return false, ctx.err("cannot assign " + jsonDataType.String() + " to Time value")
+				return false, ctx.validationErr("cannot assign " + jsonDataType.String() + " to Time value")
 			}
 
-			parsedTime, err := helpers.ParseIso8601String(stringValue)
+			parsedTime, err := ctx.parseTime(stringValue, valueStructure.timeLayout)
 			if err != nil {
 				return false, ctx.err(err.Error())
 			}
@@ -1185,7 +1647,7 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 		// keep goValue at it's default
 	case fastjson.TypeObject:
 		if goValue.Kind() != reflect.Struct {
-			return false, ctx.err("cannot assign object to non object value")
+			return false, ctx.validationErr("cannot assign object to non object value")
 		}
 
 		if valueStructure.isStructPointers {
@@ -1193,8 +1655,14 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 		}
 
 		valueSet = true
+		criticalErr := ctx.bindInputDefaults(goValue, valueStructure)
+		if criticalErr {
+			return valueSet, criticalErr
+		}
+
+		keys := 0
+		lastKey := ""
 		jsonObj := jsonData.GetObject()
-		criticalErr := false
 		jsonObj.Visit(func(key []byte, v *fastjson.Value) {
 			if criticalErr {
 				return
@@ -1202,24 +1670,35 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 
 			structItemMeta, ok := valueStructure.structContent[b2s(key)]
 			if !ok {
-				criticalErr = ctx.err("undefined property " + b2s(key))
+				criticalErr = ctx.validationErr("undefined property " + b2s(key))
 				return
 			}
+			keys++
+			lastKey = structItemMeta.gqFieldName
 
-			goValueField := goValue.Field(structItemMeta.goFieldIdx)
+			goValueField := inputField(*goValue, &structItemMeta)
 			_, criticalErr = ctx.bindJSONToValue(&goValueField, &structItemMeta, v)
 		})
 		if criticalErr {
 			return valueSet, criticalErr
 		}
+		if valueStructure.isOneOf && ctx.checkOneOfValue(goValue, valueStructure, keys, lastKey) {
+			return valueSet, true
+		}
 	case fastjson.TypeArray:
-		if goValue.Kind() != reflect.Slice {
-			return valueSet, ctx.err("cannot assign slice to " + goValue.String())
+		goValueKind := goValue.Kind()
+		if goValueKind != reflect.Slice && goValueKind != reflect.Map {
+			return valueSet, ctx.validationErr("cannot assign slice to " + goValue.Type().String())
 		}
 
 		variableArray := jsonData.GetArray()
 
-		arr := reflect.MakeSlice(goValue.Type(), len(variableArray), len(variableArray))
+		arrType := goValue.Type()
+		if goValueKind == reflect.Map {
+			// Maps are bound as a list of entries
+			arrType = reflect.SliceOf(valueStructure.goType)
+		}
+		arr := reflect.MakeSlice(arrType, len(variableArray), len(variableArray))
 
 		for i, variableArrayItem := range variableArray {
 			arrEntry := arr.Index(i)
@@ -1229,7 +1708,11 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 			}
 		}
 
-		goValue.Set(arr)
+		if goValueKind == reflect.Map {
+			setMapEntries(goValue, arr)
+		} else {
+			goValue.Set(arr)
+		}
 		valueSet = true
 	case fastjson.TypeString:
 		valueSet = true
@@ -1247,20 +1730,23 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 			if err != nil {
 				return false, ctx.err(err.Error())
 			}
+			if ctx.strictIntErr(valueStructure, intVal) {
+				return false, true
+			}
 			switch goValueKind {
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 				switch goValue.Kind() {
 				case reflect.Int8:
 					if int64(int8(intVal)) != intVal {
-						return false, ctx.errf("cannot assign %d to a 8bit integer", intVal)
+						return false, ctx.validationErrf("cannot assign %d to a 8bit integer", intVal)
 					}
 				case reflect.Int16:
 					if int64(int16(intVal)) != intVal {
-						return false, ctx.errf("cannot assign %d to a 16bit integer", intVal)
+						return false, ctx.validationErrf("cannot assign %d to a 16bit integer", intVal)
 					}
 				case reflect.Int32:
 					if int64(int32(intVal)) != intVal {
-						return false, ctx.errf("cannot assign %d to a 32bit integer", intVal)
+						return false, ctx.validationErrf("cannot assign %d to a 32bit integer", intVal)
 					}
 				}
 
@@ -1268,22 +1754,22 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 				goValue.SetInt(intVal)
 			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 				if intVal < 0 {
-					return false, ctx.errf("cannot assign %d to a unsigned integer", intVal)
+					return false, ctx.validationErrf("cannot assign %d to a unsigned integer", intVal)
 				}
 				uintVal := uint64(intVal)
 
 				switch goValue.Kind() {
-				case reflect.Int8:
+				case reflect.Uint8:
 					if uint64(uint8(uintVal)) != uintVal {
-						return false, ctx.errf("cannot assign %d to a 8bit unsigned integer", uintVal)
+						return false, ctx.validationErrf("cannot assign %d to a 8bit unsigned integer", uintVal)
 					}
-				case reflect.Int16:
+				case reflect.Uint16:
 					if uint64(uint16(uintVal)) != uintVal {
-						return false, ctx.errf("cannot assign %d to a 16bit unsigned integer", uintVal)
+						return false, ctx.validationErrf("cannot assign %d to a 16bit unsigned integer", uintVal)
 					}
-				case reflect.Int32:
+				case reflect.Uint32:
 					if uint64(uint32(uintVal)) != uintVal {
-						return false, ctx.errf("cannot assign %d to a 32bit unsigned integer", uintVal)
+						return false, ctx.validationErrf("cannot assign %d to a 32bit unsigned integer", uintVal)
 					}
 				}
 
@@ -1294,48 +1780,43 @@ func (ctx *Ctx) bindJSONToValue(goValue *reflect.Value, valueStructure *input, j
 				valueSet = true
 				goValue.SetBool(intVal > 0)
 			default:
-				return false, ctx.err("cannot assign number to " + goValue.String())
+				return false, ctx.validationErr("cannot assign number to " + goValue.Type().String())
 			}
 		}
 	case fastjson.TypeTrue:
 		if goValue.Kind() != reflect.Bool {
-			return false, ctx.err("cannot assign boolean to " + goValue.String())
+			return false, ctx.validationErr("cannot assign boolean to " + goValue.Type().String())
 		}
 		goValue.SetBool(true)
 		valueSet = true
 	case fastjson.TypeFalse:
 		if goValue.Kind() != reflect.Bool {
-			return false, ctx.err("cannot assign boolean to " + goValue.String())
+			return false, ctx.validationErr("cannot assign boolean to " + goValue.Type().String())
 		}
 		goValue.SetBool(false)
 		valueSet = true
 	default:
-		return false, ctx.err("variable value is of an unsupported type")
+		return false, ctx.validationErr("variable value is of an unsupported type")
 	}
 
 	return valueSet, false
 }
 
 func (ctx *Ctx) assignStringToValue(goValue *reflect.Value, valueStructure *input, stringValue string) bool {
-	if valueStructure.isEnum {
-		enum := ctx.schema.definedEnums[valueStructure.enumTypeIndex]
-		for _, entry := range enum.entries {
-			if entry.key == stringValue {
-				switch enum.contentKind {
-				case reflect.String:
-					goValue.SetString(entry.value.String())
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					goValue.SetInt(entry.value.Int())
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					goValue.SetUint(entry.value.Uint())
-				default:
-					return ctx.err("internal error, type missmatch on enum")
-				}
-				return false
+	if valueStructure.isText || valueStructure.isBase64 {
+		return ctx.assignTextValue(goValue, valueStructure, stringValue)
+	} else if valueStructure.isLong {
+		return ctx.assignLongValue(goValue, stringValue)
+	} else if valueStructure.isEnum {
+		enum := &ctx.schema.definedEnums[valueStructure.enumTypeIndex]
+		if entry := enum.entryForKey(stringValue); entry != nil {
+			if !enum.setValue(goValue, entry) {
+				return ctx.err("internal error, type missmatch on enum")
 			}
+			return false
 		}
 
-		return ctx.errf("unknown enum value %s for enum %s", stringValue, enum.typeName)
+		return ctx.validationErrf("unknown enum value %s for enum %s", stringValue, enum.typeName)
 	} else if valueStructure.isID {
 		switch goValue.Kind() {
 		case reflect.String:
@@ -1343,22 +1824,22 @@ func (ctx *Ctx) assignStringToValue(goValue *reflect.Value, valueStructure *inpu
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			intValue, err := strconv.Atoi(stringValue)
 			if err != nil {
-				return ctx.err("id argument must match a number type")
+				return ctx.validationErr("id argument must match a number type")
 			}
 			// TODO check if the int value can be assigned to int8 - int32
 			goValue.SetInt(int64(intValue))
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 			intValue, err := strconv.Atoi(stringValue)
 			if err != nil {
-				return ctx.err("id argument must match a number type")
+				return ctx.validationErr("id argument must match a number type")
 			}
 			if intValue < 0 {
-				return ctx.err("id argument must match a number above 0")
+				return ctx.validationErr("id argument must match a number above 0")
 			}
 			// TODO check if the int value can be assigned to uint8 - uint32
 			goValue.SetUint(uint64(intValue))
 		default:
-			return ctx.err("internal error: cannot assign to this ID field")
+			return ctx.validationErr("internal error: cannot assign to this ID field")
 		}
 	} else if valueStructure.isFile {
 		if ctx.getFormFile == nil {
@@ -1370,7 +1851,7 @@ func (ctx *Ctx) assignStringToValue(goValue *reflect.Value, valueStructure *inpu
 		}
 		goValue.Set(reflect.ValueOf(file))
 	} else if valueStructure.isTime {
-		parsedTime, err := helpers.ParseIso8601String(stringValue)
+		parsedTime, err := ctx.parseTime(stringValue, valueStructure.timeLayout)
 		if err != nil {
 			return ctx.err(err.Error())
 		}
@@ -1378,7 +1859,7 @@ func (ctx *Ctx) assignStringToValue(goValue *reflect.Value, valueStructure *inpu
 	} else if goValue.Kind() == reflect.String {
 		goValue.SetString(stringValue)
 	} else {
-		return ctx.err("cannot assign string to " + goValue.String())
+		return ctx.validationErr("cannot assign string to " + goValue.Type().String())
 	}
 	return false
 }
@@ -1404,14 +1885,26 @@ func (ctx *Ctx) checkInputIsPtr(goValue *reflect.Value, input *input, whenPtr fu
 func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input, variablesAllowed bool) (valueSet bool, criticalErr bool) {
 	// TODO convert to go value kind to graphql value kind in errors
 
+	if valueStructure.isOptional && ctx.query.Res[ctx.charNr+1] != bytecode.ValueVariable {
+		// Variables are bound below as a omitted variable keeps the optional value unset
+		isNull := ctx.query.Res[ctx.charNr+1] == bytecode.ValueNull
+		return true, bindOptionalValue(goValue, valueStructure, isNull, func(valueGoValue *reflect.Value, valueInput *input) bool {
+			_, criticalErr := ctx.bindInputToGoValue(valueGoValue, valueInput, variablesAllowed)
+			return criticalErr
+		})
+	}
+
 	var isPtr bool
-	isPtr, valueSet, criticalErr = ctx.checkInputIsPtr(goValue, valueStructure, func(goValue *reflect.Value, input *input) (valueSet bool, criticalErr bool) {
+	isPtr, valueSet, criticalErr = ctx.checkInputIsPtr(goValue, valueStructure, func(elemValue *reflect.Value, input *input) (valueSet bool, criticalErr bool) {
 		if ctx.query.Res[ctx.charNr+1] == bytecode.ValueNull {
-			// keep goValue at it's default
+			// An explicit null also overwrites the default value
+			if !goValue.IsNil() {
+				goValue.Set(reflect.Zero(goValue.Type()))
+			}
 			ctx.skipInst(6)
 			return false, false
 		}
-		return ctx.bindInputToGoValue(goValue, input, variablesAllowed)
+		return ctx.bindInputToGoValue(elemValue, input, variablesAllowed)
 	})
 	if isPtr {
 		return valueSet, criticalErr
@@ -1432,11 +1925,37 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 
 	// TODO if field is: isTime, isFile, is.. and the value provided is different than the expected we'll get wired errors
 
+	if valueStructure.isAny && valueKind != bytecode.ValueVariable {
+		// readAnyValue expects to start at ActionValue while we just read over it
+		ctx.skipInst(-6)
+
+		value, criticalErr := ctx.readAnyValue()
+		if criticalErr {
+			return false, criticalErr
+		}
+		return ctx.assignAnyValue(goValue, value)
+	}
+
+	if valueStructure.isJSON && valueKind != bytecode.ValueVariable {
+		// readAnyValue expects to start at ActionValue while we just read over it
+		ctx.skipInst(-6)
+
+		value, criticalErr := ctx.readAnyValue()
+		if criticalErr {
+			return false, criticalErr
+		}
+		return ctx.assignJSONValue(goValue, value)
+	}
+
+	if (valueStructure.isText || valueStructure.isBase64) && valueKind != bytecode.ValueVariable && valueKind != bytecode.ValueString && valueKind != bytecode.ValueNull {
+		return false, ctx.validationErr("expected a string for " + textScalarName(valueStructure.scalarName) + " value")
+	}
+
 	valueSet = true
 	switch valueKind {
 	case bytecode.ValueVariable:
 		if !variablesAllowed {
-			return false, ctx.err("variables are not allowed here")
+			return false, ctx.validationErr("variables are not allowed here")
 		}
 
 		varNameStart, varNameEnd := getValue()
@@ -1446,7 +1965,7 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 		foundArgument := ctx.findOperatorArgument(varName)
 		if !foundArgument {
 			ctx.charNr = restorePositionTo
-			return false, ctx.err("variable " + varName + " not defined")
+			return false, ctx.validationErr("variable " + varName + " not defined")
 		}
 		valueSet, criticalErr = ctx.bindOperatorArgumentTo(goValue, valueStructure, varName)
 		ctx.charNr = restorePositionTo
@@ -1457,25 +1976,36 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 		startInt, endInt := getValue()
 		intValue := b2s(ctx.query.Res[startInt:endInt])
 
+		if valueStructure.isLong {
+			criticalErr := ctx.assignLongValue(goValue, intValue)
+			if criticalErr {
+				return false, criticalErr
+			}
+			break
+		}
+
 		switch goValue.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			value, err := strconv.ParseInt(intValue, 10, 64)
 			if err != nil {
 				return false, ctx.err(err.Error())
 			}
+			if ctx.strictIntErr(valueStructure, value) {
+				return false, true
+			}
 
 			switch goValue.Kind() {
 			case reflect.Int8:
 				if int64(int8(value)) != value {
-					return false, ctx.err("cannot assign " + intValue + " to a 8bit integer")
+					return false, ctx.validationErr("cannot assign " + intValue + " to a 8bit integer")
 				}
 			case reflect.Int16:
 				if int64(int16(value)) != value {
-					return false, ctx.err("cannot assign " + intValue + " to a 16bit integer")
+					return false, ctx.validationErr("cannot assign " + intValue + " to a 16bit integer")
 				}
 			case reflect.Int32:
 				if int64(int32(value)) != value {
-					return false, ctx.err("cannot assign " + intValue + " to a 32bit integer")
+					return false, ctx.validationErr("cannot assign " + intValue + " to a 32bit integer")
 				}
 			}
 
@@ -1485,19 +2015,22 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 			if err != nil {
 				return false, ctx.err(err.Error())
 			}
+			if ctx.strictUintErr(valueStructure, value) {
+				return false, true
+			}
 
 			switch goValue.Kind() {
 			case reflect.Uint8:
 				if uint64(uint8(value)) != value {
-					return false, ctx.err("cannot assign " + intValue + " to a 8bit unsigned integer")
+					return false, ctx.validationErr("cannot assign " + intValue + " to a 8bit unsigned integer")
 				}
 			case reflect.Uint16:
 				if uint64(uint16(value)) != value {
-					return false, ctx.err("cannot assign " + intValue + " to a 16bit unsigned integer")
+					return false, ctx.validationErr("cannot assign " + intValue + " to a 16bit unsigned integer")
 				}
 			case reflect.Uint32:
 				if uint64(uint32(value)) != value {
-					return false, ctx.err("cannot assign " + intValue + " to a 32bit unsigned integer")
+					return false, ctx.validationErr("cannot assign " + intValue + " to a 32bit unsigned integer")
 				}
 			}
 
@@ -1517,7 +2050,7 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 
 			goValue.SetBool(value > 0)
 		default:
-			return false, ctx.err("cannot assign int to " + goValue.String())
+			return false, ctx.validationErr("cannot assign int to " + goValue.Type().String())
 		}
 
 	case bytecode.ValueFloat:
@@ -1531,7 +2064,7 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 
 			goValue.SetFloat(floatValue)
 		default:
-			return false, ctx.err("cannot assign float to " + goValue.String())
+			return false, ctx.validationErr("cannot assign float to " + goValue.Type().String())
 		}
 	case bytecode.ValueString:
 		startString, endString := getValue()
@@ -1542,7 +2075,7 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 		}
 	case bytecode.ValueBoolean:
 		if goValue.Kind() != reflect.Bool {
-			return false, ctx.err("cannot assign boolean to " + goValue.String())
+			return false, ctx.validationErr("cannot assign boolean to " + goValue.Type().String())
 		}
 		goValue.SetBool(ctx.readInst() == '1')
 		ctx.skipInst(1)
@@ -1552,41 +2085,37 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 		valueSet = false
 	case bytecode.ValueEnum:
 		if !valueStructure.isEnum {
-			return false, ctx.err("cannot assign enum to non enum value")
+			return false, ctx.validationErr("cannot assign enum to non enum value")
 		}
 
 		nameStart, nameEnd := getValue()
 		name := b2s(ctx.query.Res[nameStart:nameEnd])
 
-		enum := ctx.schema.definedEnums[valueStructure.enumTypeIndex]
-		for _, entry := range enum.entries {
-			if entry.key == name {
-				switch enum.contentKind {
-				case reflect.String:
-					goValue.SetString(entry.value.String())
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					goValue.SetInt(entry.value.Int())
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					goValue.SetUint(entry.value.Uint())
-				default:
-					return false, ctx.err("internal error, type missmatch on enum")
-				}
-				return true, false
+		enum := &ctx.schema.definedEnums[valueStructure.enumTypeIndex]
+		if entry := enum.entryForKey(name); entry != nil {
+			if !enum.setValue(goValue, entry) {
+				return false, ctx.err("internal error, type missmatch on enum")
 			}
+			return true, false
 		}
 
-		return false, ctx.errf("unknown enum value %s for enum %s", name, enum.typeName)
+		return false, ctx.validationErrf("unknown enum value %s for enum %s", name, enum.typeName)
 	case bytecode.ValueList:
 		goValueKind := goValue.Kind()
 		if goValueKind == reflect.Array {
 			// TODO support this
 			return false, ctx.err("fixed length arrays not supported")
 		}
-		if goValueKind != reflect.Slice {
-			return false, ctx.err("cannot assign list to " + goValue.String())
+		if goValueKind != reflect.Slice && goValueKind != reflect.Map {
+			return false, ctx.validationErr("cannot assign list to " + goValue.Type().String())
 		}
 
-		arr := reflect.MakeSlice(goValue.Type(), 0, 0)
+		arrType := goValue.Type()
+		if goValueKind == reflect.Map {
+			// Maps are bound as a list of entries
+			arrType = reflect.SliceOf(valueStructure.goType)
+		}
+		arr := reflect.MakeSlice(arrType, 0, 0)
 		arrItemType := arr.Type().Elem()
 
 		ctx.skipInst(1) // read NULL
@@ -1598,37 +2127,175 @@ func (ctx *Ctx) bindInputToGoValue(goValue *reflect.Value, valueStructure *input
 			}
 			arr = reflect.Append(arr, arrayEntry)
 		}
+		ctx.skipInst(2) // read ActionEnd and NULL
 
-		goValue.Set(arr)
+		if goValueKind == reflect.Map {
+			setMapEntries(goValue, arr)
+		} else {
+			goValue.Set(arr)
+		}
 	case bytecode.ValueObject:
 		if goValue.Kind() != reflect.Struct {
-			return false, ctx.err("cannot assign object to " + goValue.String())
+			return false, ctx.validationErr("cannot assign object to " + goValue.Type().String())
 		}
 
 		if valueStructure.isStructPointers {
 			valueStructure = ctx.schema.inTypes[valueStructure.structName]
 		}
 
+		criticalErr := ctx.bindInputDefaults(goValue, valueStructure)
+		if criticalErr {
+			return false, criticalErr
+		}
+
 		// walkInputObject expects to start at ActionValue while we just read over it
 		ctx.skipInst(-6)
 
-		criticalErr := ctx.walkInputObject(func(key []byte) bool {
+		keys := 0
+		lastKey := ""
+		criticalErr = ctx.walkInputObject(func(key []byte) bool {
 			structFieldValueStructure, ok := valueStructure.structContent[b2s(key)]
 			if !ok {
-				return ctx.err("undefined property " + b2s(key))
+				return ctx.validationErr("undefined property " + b2s(key))
 			}
+			keys++
+			lastKey = structFieldValueStructure.gqFieldName
 
-			field := goValue.Field(structFieldValueStructure.goFieldIdx)
+			field := inputField(*goValue, &structFieldValueStructure)
 			valueSet, criticalErr = ctx.bindInputToGoValue(&field, &structFieldValueStructure, variablesAllowed)
 			return criticalErr
 		})
 		if criticalErr {
 			return valueSet, criticalErr
 		}
+		if valueStructure.isOneOf && ctx.checkOneOfValue(goValue, valueStructure, keys, lastKey) {
+			return valueSet, true
+		}
 	}
 	return valueSet, false
 }
 
+// bindInputDefaults sets the default values of the fields of an input object
+// The default values are overwritten by the values provided in the query
+func (ctx *Ctx) bindInputDefaults(goValue *reflect.Value, valueStructure *input) bool {
+	if !valueStructure.hasDefaults {
+		return false
+	}
+
+	for _, field := range valueStructure.structContent {
+		if field.defaultJSON == nil {
+			continue
+		}
+		goField := inputField(*goValue, &field)
+		_, criticalErr := ctx.bindJSONToValue(&goField, &field, field.defaultJSON)
+		if criticalErr {
+			return criticalErr
+		}
+	}
+	return false
+}
+
+// readAnyValue reads a value like encoding/json does when decoding into a interface{}, used for _Any and JSON values
+func (ctx *Ctx) readAnyValue() (value interface{}, criticalErr bool) {
+	getValue := func() string {
+		start := ctx.charNr
+		for ctx.readInst() != 0 {
+		}
+		return string(ctx.query.Res[start : ctx.charNr-1])
+	}
+
+	ctx.skipInst(1)             // read ActionValue
+	valueKind := ctx.readInst() // read value kind
+	ctx.skipInst(4)             // read length of value
+
+	switch valueKind {
+	case bytecode.ValueInt, bytecode.ValueFloat:
+		number, err := strconv.ParseFloat(getValue(), 64)
+		if err != nil {
+			return nil, ctx.err(err.Error())
+		}
+		return number, false
+	case bytecode.ValueString, bytecode.ValueEnum:
+		return getValue(), false
+	case bytecode.ValueBoolean:
+		value = ctx.readInst() == '1'
+		ctx.skipInst(1)
+		return value, false
+	case bytecode.ValueNull:
+		ctx.skipInst(1)
+		return nil, false
+	case bytecode.ValueList:
+		list := []interface{}{}
+		ctx.skipInst(1) // read NULL
+		for ctx.seekInst() != 'e' {
+			item, criticalErr := ctx.readAnyValue()
+			if criticalErr {
+				return nil, criticalErr
+			}
+			list = append(list, item)
+		}
+		ctx.skipInst(2) // read ActionEnd and NULL
+		return list, false
+	case bytecode.ValueObject:
+		// walkInputObject expects to start at ActionValue while we just read over it
+		ctx.skipInst(-6)
+
+		object := map[string]interface{}{}
+		criticalErr = ctx.walkInputObject(func(key []byte) bool {
+			item, criticalErr := ctx.readAnyValue()
+			object[string(key)] = item
+			return criticalErr
+		})
+		return object, criticalErr
+	default:
+		return nil, ctx.validationErr("variables are not allowed inside _Any and JSON values")
+	}
+}
+
+// jsonToInterface converts a JSON value into a go value like encoding/json does when decoding into a interface{}
+func jsonToInterface(value *fastjson.Value) interface{} {
+	switch value.Type() {
+	case fastjson.TypeObject:
+		res := map[string]interface{}{}
+		value.GetObject().Visit(func(key []byte, v *fastjson.Value) {
+			res[string(key)] = jsonToInterface(v)
+		})
+		return res
+	case fastjson.TypeArray:
+		items := value.GetArray()
+		res := make([]interface{}, len(items))
+		for idx, item := range items {
+			res[idx] = jsonToInterface(item)
+		}
+		return res
+	case fastjson.TypeString:
+		return string(value.GetStringBytes())
+	case fastjson.TypeNumber:
+		return value.GetFloat64()
+	case fastjson.TypeTrue:
+		return true
+	case fastjson.TypeFalse:
+		return false
+	default:
+		return nil
+	}
+}
+
+// assignAnyValue sets goValue to the representation in value, value must be an object or null
+func (ctx *Ctx) assignAnyValue(goValue *reflect.Value, value interface{}) (valueSet bool, criticalErr bool) {
+	if value == nil {
+		// keep goValue at it's default
+		return false, false
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false, ctx.validationErrf("cannot assign %T to _Any value, expected an object", value)
+	}
+	goValue.Set(reflect.ValueOf(Representation(object)))
+	return true, false
+}
+
 // walkInputObject walks over an input object and triggers onValueOfKey after reading a key and reached it value
 // onValueOfKey is expected to parse the value before returning
 func (ctx *Ctx) walkInputObject(onValueOfKey func(key []byte) bool) bool {
@@ -1690,17 +2357,87 @@ func (ctx *Ctx) valueToJSON(in reflect.Value, kind reflect.Kind) {
 	}
 }
 
-func (ctx *Ctx) startTrace() {
-	if ctx.tracingEnabled {
-		ctx.prefRecordingStartTime = time.Now()
+// operationInfo returns the info of the resolved operation for the tracers
+func (ctx *Ctx) operationInfo() OperationInfo {
+	return OperationInfo{
+		Name:   string(ctx.operationName),
+		Type:   ctx.operationType(),
+		Errors: ctx.query.Errors,
+	}
+}
+
+func (ctx *Ctx) operationType() string {
+	switch ctx.operationKind {
+	case bytecode.OperatorQuery:
+		return "query"
+	case bytecode.OperatorMutation:
+		return "mutation"
+	case bytecode.OperatorSubscription:
+		return "subscription"
+	default:
+		return ""
+	}
+}
+
+// fieldInfo returns the info of the field that is being resolved for the tracers
+func (ctx *Ctx) fieldInfo(typeObj *obj, field *obj, name []byte) FieldInfo {
+	returnType := bytes.NewBuffer(nil)
+	ctx.schema.objToQlTypeName(field, returnType)
+
+	return FieldInfo{
+		Path:       string(ctx.GetPath()),
+		ParentType: typeObj.typeName,
+		FieldName:  string(name),
+		ReturnType: returnType.String(),
+	}
+}
+
+// fieldErrors returns the errors created since errsBefore with the path of the current field or one of its list items
+func (ctx *Ctx) fieldErrors(errsBefore int) []error {
+	var res []error
+	fieldPath := ctx.path[1:]
+	for _, err := range ctx.query.Errors[errsBefore:] {
+		errWPath, ok := err.(ErrorWPath)
+		if !ok || !bytes.HasPrefix(errWPath.path, fieldPath) {
+			continue
+		}
+		// Errors of sub fields have a field name in the remaining path
+		if bytes.IndexByte(errWPath.path[len(fieldPath):], '"') == -1 {
+			res = append(res, err)
+		}
+	}
+	return res
+}
+
+func (ctx *Ctx) hasTracerExtensions() bool {
+	for _, tracer := range ctx.tracers {
+		if _, ok := tracer.(TracerExtension); ok {
+			return true
+		}
 	}
+	return false
 }
 
-func (ctx *Ctx) finishTrace(report func(offset, duration int64)) {
-	f := ctx.prefRecordingStartTime
-	offset := f.Sub(ctx.tracing.GoStartTime).Nanoseconds()
-	duration := time.Since(f).Nanoseconds()
-	report(offset, duration)
+// writeTracerExtensions writes the extensions of the tracers as the contents of the extensions object
+func (ctx *Ctx) writeTracerExtensions() {
+	first := true
+	for _, tracer := range ctx.tracers {
+		extension, ok := tracer.(TracerExtension)
+		if !ok {
+			continue
+		}
+		key, value := extension.Extension()
+		if value == nil {
+			continue
+		}
+		if !first {
+			ctx.writeByte(',')
+		}
+		first = false
+		helpers.StringToJSON(key, &ctx.schema.Result)
+		ctx.writeByte(':')
+		ctx.write(value)
+	}
 }
 
 // b2s converts a byte array into a string without allocating new memory
@@ -5,9 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"mime/multipart"
+	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -340,6 +343,13 @@ func (TestBytecodeResolveMethodListInputData) ResolveBar(c *Ctx, args struct{ A
 	return args.A
 }
 
+func (TestBytecodeResolveMethodListInputData) ResolveBaz(c *Ctx, args struct {
+	A []string
+	B string
+}) []string {
+	return append(args.A, args.B)
+}
+
 func TestBytecodeResolveMethodListInput(t *testing.T) {
 	res := bytecodeParseAndExpectNoErrs(t, `{bar()}`, TestBytecodeResolveMethodListInputData{}, M{})
 	a.Equal(t, `{"bar":null}`, res)
@@ -349,6 +359,10 @@ func TestBytecodeResolveMethodListInput(t *testing.T) {
 
 	res = bytecodeParseAndExpectNoErrs(t, `{bar(a: ["foo", "baz"])}`, TestBytecodeResolveMethodListInputData{}, M{})
 	a.Equal(t, `{"bar":["foo","baz"]}`, res)
+
+	// Arguments after a list should also be bound
+	res = bytecodeParseAndExpectNoErrs(t, `{baz(a: ["foo"], b: "bar")}`, TestBytecodeResolveMethodListInputData{}, M{})
+	a.Equal(t, `{"baz":["foo","bar"]}`, res)
 }
 
 type TestResolveStructTypeMethodWithStructArgData struct{}
@@ -1869,6 +1883,265 @@ func TestBytecodeResolveContext(t *testing.T) {
 	a.Equal(t, `{"foo":null}`, out)
 }
 
+type TestBytecodeResolveFieldTimeoutData struct {
+	Slow func(ctx context.Context) bool `gq:",timeout=10ms"`
+	Fast string
+}
+
+func TestBytecodeResolveFieldTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	queries := TestBytecodeResolveFieldTimeoutData{
+		Slow: func(ctx context.Context) bool {
+			<-ctx.Done()
+			close(cancelled)
+			time.Sleep(time.Millisecond * 50)
+			return true
+		},
+		Fast: "fast",
+	}
+
+	out, errs := bytecodeParseAndExpectErrs(t, `{slow fast}`, queries, M{})
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "field timed out after 10ms", errs[0].Error())
+	a.Equal(t, `{"slow":null,"fast":"fast"}`, out)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the resolver context to be cancelled")
+	}
+}
+
+type TestBytecodeResolveFieldTimeoutOptionsData struct{}
+
+func (TestBytecodeResolveFieldTimeoutOptionsData) ResolveSlow(ctx *Ctx) bool {
+	<-ctx.GetContext().Done()
+	return true
+}
+
+func (TestBytecodeResolveFieldTimeoutOptionsData) ResolveFast() bool {
+	return true
+}
+
+func TestBytecodeResolveFieldTimeoutOptions(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestBytecodeResolveFieldTimeoutOptionsData{}, M{}, nil)
+	a.NoError(t, err)
+
+	err = s.SetFieldOptions("TestBytecodeResolveFieldTimeoutOptionsData", "slow", FieldOptions{Timeout: time.Millisecond * 10})
+	a.NoError(t, err)
+
+	s = s.Copy()
+	errs := s.Resolve([]byte(`{fast slow}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "field timed out after 10ms", errs[0].Error())
+	a.Equal(t, `{"fast":true,"slow":null}`, string(s.Result))
+
+	err = s.SetFieldOptions("TestBytecodeResolveFieldTimeoutOptionsData", "doesNotExist", FieldOptions{Timeout: time.Second})
+	a.Error(t, err)
+	err = s.SetFieldOptions("DoesNotExist", "slow", FieldOptions{Timeout: time.Second})
+	a.Error(t, err)
+}
+
+type TestBytecodeResolveOperationTimeoutData struct {
+	Slow  func(ctx context.Context) bool
+	Fast  string
+	Later func() bool
+}
+
+func TestBytecodeResolveOperationTimeout(t *testing.T) {
+	laterCalled := false
+	queries := TestBytecodeResolveOperationTimeoutData{
+		Slow: func(ctx context.Context) bool {
+			// Ignores the context, the response should not wait for it
+			time.Sleep(time.Second)
+			return true
+		},
+		Fast: "fast",
+		Later: func() bool {
+			laterCalled = true
+			return true
+		},
+	}
+
+	start := time.Now()
+	opts := ResolveOptions{NoMeta: true, Timeout: time.Millisecond * 10}
+	out, errs := bytecodeParseAndExpectErrs(t, `{fast slow later}`, queries, M{}, opts)
+	a.Less(t, int64(time.Since(start)), int64(time.Millisecond*500))
+	a.Equal(t, 2, len(errs))
+	a.Equal(t, "operation timed out", errs[0].Error())
+	a.Equal(t, "operation timed out", errs[1].Error())
+	a.Equal(t, `{"fast":"fast","slow":null,"later":null}`, out)
+	a.False(t, laterCalled, "fields after the deadline should not be resolved")
+}
+
+type TestBytecodeResolveTimedOutResolverData struct {
+	Slow  func(ctx *Ctx) string `gq:",timeout=10ms"`
+	Fast  func(ctx *Ctx) string
+	Panic func() string `gq:",timeout=1s"`
+	Quick func(ctx *Ctx) bool `gq:",timeout=1s"`
+}
+
+func TestBytecodeResolveTimedOutResolverKeepsRunning(t *testing.T) {
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	s := NewSchema()
+	err := s.Parse(TestBytecodeResolveTimedOutResolverData{
+		Slow: func(ctx *Ctx) string {
+			<-release
+			for i := 0; i < 100; i++ {
+				ctx.SetValue("slow", i)
+				ctx.GetValue("fast")
+				ctx.GetPath()
+				ctx.GetHeader("X-Request")
+			}
+			close(finished)
+			return "slow"
+		},
+		Fast: func(ctx *Ctx) string {
+			close(release)
+			for i := 0; i < 100; i++ {
+				ctx.SetValue("fast", i)
+				ctx.GetValue("slow")
+			}
+			<-finished
+			return "fast"
+		},
+		Panic: func() string {
+			panic("boom")
+		},
+		Quick: func(ctx *Ctx) bool {
+			ctx.SetValue("quick", true)
+			return true
+		},
+	}, M{}, nil)
+	a.NoError(t, err)
+
+	// The same values are used for both requests, the timed out resolver should not be able to modify them
+	values := map[string]interface{}{}
+	opts := ResolveOptions{
+		NoMeta:  true,
+		Values:  &values,
+		Timeout: time.Second,
+		Headers: http.Header{"X-Request": []string{"1"}},
+	}
+
+	errs := s.Resolve([]byte(`{slow}`), opts)
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "field timed out after 10ms", errs[0].Error())
+	a.Equal(t, `{"slow":null}`, string(s.Result))
+
+	opts.Headers.Set("X-Request", "2")
+	errs = s.Resolve([]byte(`{fast panic}`), opts)
+	a.Equal(t, 1, len(errs))
+	a.Equal(t, "resolver panicked: boom", errs[0].Error())
+	a.Equal(t, `{"fast":"fast","panic":null}`, string(s.Result))
+	_, ok := values["slow"]
+	a.False(t, ok, "values set by a timed out resolver should not be visible")
+	a.Equal(t, 99, values["fast"])
+
+	// Values set by a resolver that finished in time are kept
+	errs = s.Resolve([]byte(`{quick}`), opts)
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, true, values["quick"])
+}
+
+func TestBytecodeResolveContextArgument(t *testing.T) {
+	type ctxKey struct{}
+	queries := TestBytecodeResolveOperationTimeoutData{
+		Slow: func(ctx context.Context) bool {
+			return ctx.Value(ctxKey{}) == "value"
+		},
+	}
+
+	opts := ResolveOptions{NoMeta: true, Context: context.WithValue(context.Background(), ctxKey{}, "value")}
+	out := bytecodeParseAndExpectNoErrs(t, `{slow}`, queries, M{}, opts)
+	a.Equal(t, `{"slow":true}`, out)
+}
+
+type TestResolveToData struct {
+	Items []TestResolveToItem
+}
+
+type TestResolveToItem struct {
+	Index int
+	Name  string
+}
+
+type chunkWriter struct {
+	chunks  [][]byte
+	flushes int
+	err     error
+}
+
+func (w *chunkWriter) Write(b []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.chunks = append(w.chunks, append([]byte{}, b...))
+	return len(b), nil
+}
+
+func (w *chunkWriter) Flush() {
+	w.flushes++
+}
+
+func TestResolveTo(t *testing.T) {
+	queries := TestResolveToData{}
+	for i := 0; i < 1000; i++ {
+		queries.Items = append(queries.Items, TestResolveToItem{Index: i, Name: "item " + strconv.Itoa(i)})
+	}
+
+	s := NewSchema()
+	err := s.Parse(queries, M{}, nil)
+	a.NoError(t, err)
+
+	query := []byte(`{items {index name}}`)
+	errs := s.Resolve(query, ResolveOptions{})
+	a.Equal(t, 0, len(errs))
+	expected := string(s.Result)
+
+	w := &chunkWriter{}
+	errs, err = s.ResolveTo(w, query, ResolveOptions{})
+	a.NoError(t, err)
+	a.Equal(t, 0, len(errs))
+	a.Less(t, 1, len(w.chunks), "expected the response to be written in multiple chunks")
+	a.Equal(t, len(w.chunks), w.flushes)
+
+	for _, chunk := range w.chunks[:len(w.chunks)-1] {
+		a.Less(t, len(chunk), flushThreshold*2)
+	}
+	a.Equal(t, expected, string(bytes.Join(w.chunks, nil)))
+
+	// Small responses are written at once
+	w = &chunkWriter{}
+	_, err = s.ResolveTo(w, []byte(`{__typename}`), ResolveOptions{})
+	a.NoError(t, err)
+	a.Equal(t, 1, len(w.chunks))
+	a.Equal(t, `{"data":{"__typename":"TestResolveToData"}}`, string(w.chunks[0]))
+}
+
+func TestResolveToWriterError(t *testing.T) {
+	queries := TestResolveToData{}
+	for i := 0; i < 1000; i++ {
+		queries.Items = append(queries.Items, TestResolveToItem{Index: i, Name: "item " + strconv.Itoa(i)})
+	}
+
+	s := NewSchema()
+	err := s.Parse(queries, M{}, nil)
+	a.NoError(t, err)
+
+	writeErr := errors.New("connection closed")
+	_, err = s.ResolveTo(&chunkWriter{err: writeErr}, []byte(`{items {index name}}`), ResolveOptions{})
+	a.Equal(t, writeErr, err)
+
+	// The schema should still work normally afterwards
+	errs := s.Resolve([]byte(`{__typename}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"__typename":"TestResolveToData"}`, string(s.Result))
+}
+
 func TestBytecodeResolveQueryCache(t *testing.T) {
 	testCases := []struct {
 		query  string
@@ -1928,3 +2201,87 @@ func TestBytecodeResolveID(t *testing.T) {
 	out := bytecodeParseAndExpectNoErrs(t, query, schema, M{})
 	a.Equal(t, `{"directId":"2","methodId":"3"}`, out)
 }
+
+type TestBytecodeResolveDefaultValuesData struct{}
+
+type TestBytecodeResolveDefaultValuesArgs struct {
+	Limit int        `gqDefault:"10"`
+	Name  *string    `gqDefault:"\"foo\""`
+	Kind  __TypeKind `gqDefault:"OBJECT"`
+}
+
+func (TestBytecodeResolveDefaultValuesData) ResolveFoo(args TestBytecodeResolveDefaultValuesArgs) string {
+	name := "null"
+	if args.Name != nil {
+		name = *args.Name
+	}
+	return fmt.Sprintf("%d %s %s", args.Limit, name, args.Kind.String())
+}
+
+type TestBytecodeResolveDefaultValuesInput struct {
+	A string `gqDefault:"\"a\""`
+	B string
+}
+
+func (TestBytecodeResolveDefaultValuesData) ResolveBar(args struct {
+	In TestBytecodeResolveDefaultValuesInput
+}) string {
+	return args.In.A + args.In.B
+}
+
+func TestBytecodeResolveDefaultValues(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"defaults", `{foo}`, "", `{"foo":"10 foo OBJECT"}`},
+		{"overwrite defaults", `{foo(limit: 2, name: "bar", kind: ENUM)}`, "", `{"foo":"2 bar ENUM"}`},
+		{"explicit null", `{foo(name: null)}`, "", `{"foo":"10 null OBJECT"}`},
+		{"variable", `query ($name: String) {foo(name: $name)}`, `{"name": "baz"}`, `{"foo":"10 baz OBJECT"}`},
+		{"input object", `{bar(in: {b: "b"})}`, "", `{"bar":"ab"}`},
+		{"input object overwrite", `{bar(in: {a: "c", b: "b"})}`, "", `{"bar":"cb"}`},
+		{"input object variable", `query ($in: TestBytecodeResolveDefaultValuesInput) {bar(in: $in)}`, `{"in": {"b": "b"}}`, `{"bar":"ab"}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			res := bytecodeParseAndExpectNoErrs(t, testCase.query, TestBytecodeResolveDefaultValuesData{}, M{}, opts)
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+type TestBytecodeResolveDeprecatedData struct {
+	A string `gqDescription:"field a"`
+	B string `gqDeprecated:"use a"`
+}
+
+func (TestBytecodeResolveDeprecatedData) ResolveC(args struct {
+	D int `gqDefault:"4" gqDescription:"argument d"`
+}) int {
+	return args.D
+}
+
+func TestBytecodeResolveDeprecatedFields(t *testing.T) {
+	query := `{__type(name: "TestBytecodeResolveDeprecatedData") {
+		fields {name description isDeprecated deprecationReason args {name description defaultValue}}
+		all: fields(includeDeprecated: true) {name isDeprecated deprecationReason}
+	}}`
+	res := bytecodeParseAndExpectNoErrs(t, query, TestBytecodeResolveDeprecatedData{}, M{})
+	a.Equal(t, `{"__type":{`+
+		`"fields":[`+
+		`{"name":"a","description":"field a","isDeprecated":false,"deprecationReason":null,"args":[]},`+
+		`{"name":"c","description":"","isDeprecated":false,"deprecationReason":null,"args":[{"name":"d","description":"argument d","defaultValue":"4"}]}],`+
+		`"all":[`+
+		`{"name":"a","isDeprecated":false,"deprecationReason":null},`+
+		`{"name":"b","isDeprecated":true,"deprecationReason":"use a"},`+
+		`{"name":"c","isDeprecated":false,"deprecationReason":null}]`+
+		`}}`, res)
+
+	// The deprecated field can still be queried
+	res = bytecodeParseAndExpectNoErrs(t, `{b}`, TestBytecodeResolveDeprecatedData{B: "b"}, M{})
+	a.Equal(t, `{"b":"b"}`, res)
+}
@@ -0,0 +1,365 @@
+package yarql
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mjarkk/yarql/bytecode"
+)
+
+// builtinScalars are the scalars defined by the graphql spec, these are not written to the SDL
+var builtinScalars = map[string]bool{
+	"Boolean": true,
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"ID":      true,
+}
+
+// builtinDirectives are the directives defined by the graphql spec, these are not written to the SDL
+var builtinDirectives = map[string]bool{
+	"skip":        true,
+	"include":     true,
+	"deprecated":  true,
+	"specifiedBy": true,
+	"oneOf":       true,
+}
+
+// SDL returns the schema in the GraphQL schema definition language
+// The output is sorted by name so it can be compared between versions of the schema
+//
+// Panics if the schema has not been parsed yet
+func (s *Schema) SDL() string {
+	buf := bytes.NewBuffer(nil)
+	err := s.WriteSDL(buf)
+	if err != nil {
+		panic(err.Error())
+	}
+	return buf.String()
+}
+
+// WriteSDL writes the schema in the GraphQL schema definition language to w
+// See (*Schema).SDL
+func (s *Schema) WriteSDL(w io.Writer) error {
+	if !s.parsed {
+		return errors.New("schema has not been parsed yet, call Parse before writing the SDL")
+	}
+
+	sdl := &sdlWriter{schema: s}
+
+	// A subgraph leaves out a mutation type without fields as a gateway doesn't accept types without fields
+	skipMutation := s.federation != nil && len(s.rootMethod.objContents) == 0
+
+	queryName := s.rootQuery.typeName
+	mutationName := s.rootMethod.typeName
+	if skipMutation && queryName != "Query" {
+		sdl.startDefinition("")
+		sdl.write("schema {\n  query: " + queryName + "\n}\n")
+	} else if !skipMutation && (queryName != "Query" || mutationName != "Mutation") {
+		sdl.startDefinition("")
+		sdl.write("schema {\n  query: " + queryName + "\n  mutation: " + mutationName + "\n}\n")
+	}
+
+	for _, directive := range s.getDirectives() {
+		if builtinDirectives[directive.Name] {
+			continue
+		}
+		sdl.writeDirective(directive)
+	}
+
+	for _, qlType := range s.getAllQLTypes() {
+		name := *qlType.Name
+		if isIntrospectionTypeName(name) {
+			continue
+		}
+		if qlType.Kind == typeKindScalar && builtinScalars[name] {
+			continue
+		}
+		if s.federation != nil && federationTypeNames[name] {
+			continue
+		}
+		if skipMutation && name == mutationName {
+			continue
+		}
+		sdl.writeType(qlType)
+	}
+
+	_, err := w.Write(sdl.res.Bytes())
+	return err
+}
+
+// isIntrospectionTypeName returns true for the __Schema, __Type, etc types
+// Note that types generated for inline structs also start with __ but are part of the schema
+func isIntrospectionTypeName(name string) bool {
+	return strings.HasPrefix(name, "__") && !strings.HasPrefix(name, "__Unknown")
+}
+
+type sdlWriter struct {
+	schema *Schema
+	res    bytes.Buffer
+}
+
+func (w *sdlWriter) write(s string) {
+	w.res.WriteString(s)
+}
+
+// startDefinition adds an empty line between definitions and writes the description
+func (w *sdlWriter) startDefinition(description string) {
+	if w.res.Len() > 0 {
+		w.res.WriteByte('\n')
+	}
+	w.writeDescription(description, "")
+}
+
+func (w *sdlWriter) writeDescription(description string, indent string) {
+	if description == "" {
+		return
+	}
+
+	if !strings.Contains(description, "\n") || !canBeBlockString(description) {
+		w.write(indent + bytecode.QuoteString(description) + "\n")
+		return
+	}
+
+	w.write(indent + "\"\"\"\n")
+	for _, line := range strings.Split(description, "\n") {
+		if line == "" {
+			w.write("\n")
+		} else {
+			w.write(indent + line + "\n")
+		}
+	}
+	w.write(indent + "\"\"\"\n")
+}
+
+// canBeBlockString returns true if the description can be written as block string without losing information
+func canBeBlockString(description string) bool {
+	if strings.Contains(description, `\`) || strings.Contains(description, `"""`) || strings.Contains(description, "\r") {
+		return false
+	}
+	for _, line := range strings.Split(description, "\n") {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasSuffix(line, " ") {
+			// The indentation of block strings is removed
+			return false
+		}
+	}
+	return !strings.HasPrefix(description, "\n") && !strings.HasSuffix(description, "\n")
+}
+
+func (w *sdlWriter) writeType(qlType qlType) {
+	w.startDefinition(ptrToString(qlType.Description))
+
+	name := *qlType.Name
+	switch qlType.Kind {
+	case typeKindScalar:
+		w.write("scalar " + name)
+		if qlType.SpecifiedByURL != nil {
+			w.write(" @specifiedBy(url: " + bytecode.QuoteString(*qlType.SpecifiedByURL) + ")")
+		}
+		w.write("\n")
+	case typeKindObject, typeKindInterface:
+		if qlType.Kind == typeKindObject {
+			w.write("type " + name)
+		} else {
+			w.write("interface " + name)
+		}
+
+		interfaces := make([]string, len(qlType.Interfaces))
+		for idx, qlInterface := range qlType.Interfaces {
+			interfaces[idx] = *qlInterface.Name
+		}
+		sort.Strings(interfaces)
+		if len(interfaces) > 0 {
+			w.write(" implements " + strings.Join(interfaces, " & "))
+		}
+		w.writeAppliedDirectives(name, "")
+
+		fields := w.fieldsOf(qlType)
+		if len(fields) == 0 {
+			w.write("\n")
+			return
+		}
+
+		w.write(" {\n")
+		for _, field := range fields {
+			w.writeDescription(ptrToString(field.Description), "  ")
+			w.write("  " + field.Name)
+			w.writeArguments(field.Args, "  ")
+			w.write(": " + sdlTypeReference(field.Type))
+			if field.IsDeprecated {
+				w.writeDeprecated(field.DeprecationReason)
+			}
+			w.writeAppliedDirectives(name, field.Name)
+			w.write("\n")
+		}
+		w.write("}\n")
+	case typeKindUnion:
+		w.write("union " + name)
+		possibleTypes := qlType.PossibleTypes()
+		names := make([]string, len(possibleTypes))
+		for idx, possibleType := range possibleTypes {
+			names[idx] = *possibleType.Name
+		}
+		sort.Strings(names)
+		if len(names) > 0 {
+			w.write(" = " + strings.Join(names, " | "))
+		}
+		w.write("\n")
+	case typeKindEnum:
+		w.write("enum " + name + " {\n")
+		for _, value := range qlType.EnumValues(isDeprecatedArgs{IncludeDeprecated: true}) {
+			w.writeDescription(ptrToString(value.Description), "  ")
+			w.write("  " + value.Name)
+			if value.IsDeprecated {
+				w.writeDeprecated(value.DeprecationReason)
+			}
+			w.write("\n")
+		}
+		w.write("}\n")
+	case typeKindInputObject:
+		w.write("input " + name)
+		if qlType.IsOneOf != nil && *qlType.IsOneOf {
+			w.write(" @oneOf")
+		}
+		inputFields := qlType.InputFields()
+		if len(inputFields) == 0 {
+			w.write("\n")
+			return
+		}
+
+		w.write(" {\n")
+		for _, inputField := range inputFields {
+			w.writeInputValue(inputField, "  ")
+			w.write("\n")
+		}
+		w.write("}\n")
+	}
+}
+
+// fieldsOf returns the fields of a object or interface that are written to the SDL
+// The fields added by federation to the query root are left out
+func (w *sdlWriter) fieldsOf(qlType qlType) []qlField {
+	fields := qlType.Fields(isDeprecatedArgs{IncludeDeprecated: true})
+	if w.schema.federation == nil || *qlType.Name != w.schema.rootQuery.typeName {
+		return fields
+	}
+
+	res := make([]qlField, 0, len(fields))
+	for _, field := range fields {
+		if !federationFieldNames[field.Name] {
+			res = append(res, field)
+		}
+	}
+	return res
+}
+
+// writeAppliedDirectives writes the directives applied to a type or, if fieldName is set, to a field of the type
+func (w *sdlWriter) writeAppliedDirectives(typeName string, fieldName string) {
+	typeObj, ok := w.schema.types[typeName]
+	if !ok {
+		typeObj, ok = w.schema.interfaces[typeName]
+		if !ok {
+			return
+		}
+	}
+
+	directives := typeObj.appliedDirectives
+	if fieldName != "" {
+		field, ok := typeObj.objContents[getObjKey([]byte(fieldName))]
+		if !ok {
+			return
+		}
+		directives = field.appliedDirectives
+	}
+
+	for _, directive := range directives {
+		w.write(" " + directive)
+	}
+}
+
+// writeArguments writes the arguments of a field or directive
+// If one of the arguments has a description every argument is written on it's own line
+func (w *sdlWriter) writeArguments(args []qlInputValue, indent string) {
+	if len(args) == 0 {
+		return
+	}
+
+	multiline := false
+	for _, arg := range args {
+		if ptrToString(arg.Description) != "" {
+			multiline = true
+			break
+		}
+	}
+
+	w.write("(")
+	for idx, arg := range args {
+		if multiline {
+			w.write("\n")
+			w.writeInputValue(arg, indent+"  ")
+		} else {
+			if idx > 0 {
+				w.write(", ")
+			}
+			w.writeInputValue(arg, "")
+		}
+	}
+	if multiline {
+		w.write("\n" + indent)
+	}
+	w.write(")")
+}
+
+func (w *sdlWriter) writeInputValue(value qlInputValue, indent string) {
+	w.writeDescription(ptrToString(value.Description), indent)
+	w.write(indent + value.Name + ": " + sdlTypeReference(value.Type))
+	if value.DefaultValue != nil {
+		w.write(" = " + *value.DefaultValue)
+	}
+}
+
+func (w *sdlWriter) writeDeprecated(reason *string) {
+	if reason == nil || *reason == defaultDeprecationReason {
+		w.write(" @deprecated")
+		return
+	}
+	w.write(" @deprecated(reason: " + bytecode.QuoteString(*reason) + ")")
+}
+
+func (w *sdlWriter) writeDirective(directive qlDirective) {
+	w.startDefinition(ptrToString(directive.Description))
+
+	w.write("directive @" + directive.Name)
+	w.writeArguments(directive.Args, "")
+	if directive.IsRepeatable {
+		w.write(" repeatable")
+	}
+
+	locations := make([]string, len(directive.Locations))
+	for idx, location := range directive.Locations {
+		locations[idx] = location.String()
+	}
+	w.write(" on " + strings.Join(locations, " | ") + "\n")
+}
+
+// sdlTypeReference returns the type as written in the SDL, like [String!]!
+func sdlTypeReference(qlType qlType) string {
+	switch qlType.Kind {
+	case typeKindNonNull:
+		return sdlTypeReference(*qlType.OfType) + "!"
+	case typeKindList:
+		return "[" + sdlTypeReference(*qlType.OfType) + "]"
+	default:
+		return ptrToString(qlType.Name)
+	}
+}
+
+func ptrToString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
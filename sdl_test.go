@@ -0,0 +1,288 @@
+package yarql
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	a "github.com/mjarkk/yarql/assert"
+	"github.com/mjarkk/yarql/bytecode"
+)
+
+type SDLTestSortOrder uint8
+
+const (
+	SDLTestSortOrderAsc SDLTestSortOrder = iota
+	SDLTestSortOrderDesc
+)
+
+type SDLTestNode interface {
+	ResolveId() (string, AttrIsID)
+}
+
+type SDLTestPost struct {
+	Title     string `gqDescription:"The title of the post"`
+	Body      string `gqDescription:"The body of the post\nwritten in markdown"`
+	Likes     int    `gqDeprecated:""`
+	CreatedAt time.Time
+}
+
+func (SDLTestPost) ResolveId() (string, AttrIsID) {
+	return "1", 0
+}
+
+type SDLTestQuery struct {
+	Posts    []SDLTestPost `gqDescription:"All posts"`
+	OldPosts []SDLTestPost `gqDeprecated:"Use posts"`
+}
+
+type SDLTestSearchArgs struct {
+	Query string           `gqDescription:"The text to search for"`
+	Limit int              `gqDefault:"10"`
+	Order SDLTestSortOrder `gqDefault:"DESC"`
+}
+
+func (SDLTestQuery) ResolveSearch(args SDLTestSearchArgs) []SDLTestPost {
+	return []SDLTestPost{}
+}
+
+func (SDLTestQuery) ResolveNode(args struct{ ID string }) SDLTestNode {
+	return SDLTestPost{}
+}
+
+type SDLTestPostInput struct {
+	Title string   `gqDefault:"\"untitled\""`
+	Tags  []string `gqDefault:"[\"go\", \"graphql\"]"`
+}
+
+type SDLTestMutation struct{}
+
+func (SDLTestMutation) ResolveCreatePost(args struct{ Post SDLTestPostInput }) SDLTestPost {
+	return SDLTestPost{Title: args.Post.Title}
+}
+
+func newSDLTestSchema(t *testing.T) *Schema {
+	Implements((*SDLTestNode)(nil), SDLTestPost{})
+
+	s := NewSchema()
+	_, err := s.RegisterEnum(map[string]SDLTestSortOrder{
+		"ASC":  SDLTestSortOrderAsc,
+		"DESC": SDLTestSortOrderDesc,
+	})
+	a.NoError(t, err)
+
+	err = s.RegisterDirective(Directive{
+		Name:        "uppercase",
+		Where:       []DirectiveLocation{DirectiveLocationField},
+		Method:      func(args struct{ Enabled bool }) DirectiveModifier { return DirectiveModifier{} },
+		Description: "Makes the field uppercase",
+	})
+	a.NoError(t, err)
+
+	err = s.Parse(SDLTestQuery{}, SDLTestMutation{}, nil)
+	a.NoError(t, err)
+
+	err = s.SetTypeOptions("SDLTestPost", TypeOptions{Description: "A blog post"})
+	a.NoError(t, err)
+	err = s.SetTypeOptions("SDLTestSortOrder", TypeOptions{Description: "The order of the results"})
+	a.NoError(t, err)
+	err = s.SetFieldOptions("SDLTestQuery", "search", FieldOptions{Description: "Search for posts"})
+	a.NoError(t, err)
+
+	return s
+}
+
+func TestSDL(t *testing.T) {
+	s := newSDLTestSchema(t)
+
+	expected := `schema {
+  query: SDLTestQuery
+  mutation: SDLTestMutation
+}
+
+"Makes the field uppercase"
+directive @uppercase(enabled: Boolean!) on FIELD
+
+"The File scalar type references to a multipart file, often used to upload files to the server. Expects a string with the form file field name"
+scalar File @specifiedBy(url: "https://github.com/mjarkk/yarql#file-upload")
+
+type SDLTestMutation {
+  createPost(post: SDLTestPostInput!): SDLTestPost!
+}
+
+interface SDLTestNode {
+  id: ID!
+}
+
+"A blog post"
+type SDLTestPost implements SDLTestNode {
+  """
+  The body of the post
+  written in markdown
+  """
+  body: String!
+  createdAt: Time!
+  id: ID!
+  likes: Int! @deprecated
+  "The title of the post"
+  title: String!
+}
+
+input SDLTestPostInput {
+  tags: [String!] = ["go", "graphql"]
+  title: String! = "untitled"
+}
+
+type SDLTestQuery {
+  node(ID: String!): SDLTestNode
+  oldPosts: [SDLTestPost!] @deprecated(reason: "Use posts")
+  "All posts"
+  posts: [SDLTestPost!]
+  "Search for posts"
+  search(
+    limit: Int! = 10
+    order: SDLTestSortOrder! = DESC
+    "The text to search for"
+    query: String!
+  ): [SDLTestPost!]
+}
+
+"The order of the results"
+enum SDLTestSortOrder {
+  ASC
+  DESC
+}
+
+"The Time scalar type references to a ISO 8601 date+time, often used to insert and/or view dates. Expects a string with the ISO 8601 format"
+scalar Time @specifiedBy(url: "https://en.wikipedia.org/wiki/ISO_8601")
+`
+	a.Equal(t, expected, s.SDL())
+
+	// The output should be stable
+	a.Equal(t, expected, s.SDL())
+	a.Equal(t, expected, s.Copy().SDL())
+
+	buf := bytes.NewBuffer(nil)
+	err := s.WriteSDL(buf)
+	a.NoError(t, err)
+	a.Equal(t, expected, buf.String())
+}
+
+func TestSDLRoundTrip(t *testing.T) {
+	s := newSDLTestSchema(t)
+
+	doc, errs := bytecode.ParseSDL([]byte(s.SDL()))
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+
+	a.NotNil(t, doc.Schema)
+	a.Equal(t, "SDLTestQuery", doc.Schema.Query)
+	a.Equal(t, "SDLTestMutation", doc.Schema.Mutation)
+
+	a.Equal(t, 1, len(doc.Directives))
+	a.Equal(t, "uppercase", doc.Directives[0].Name)
+	a.Equal(t, "Makes the field uppercase", doc.Directives[0].Description)
+	a.Equal(t, []string{"FIELD"}, doc.Directives[0].Locations)
+
+	// Every type in the SDL should match the introspection of the schema
+	definitions := map[string]bytecode.TypeDefinition{}
+	for _, definition := range doc.Types {
+		definitions[definition.Name] = definition
+	}
+	for _, qlType := range s.getAllQLTypes() {
+		name := *qlType.Name
+		if isIntrospectionTypeName(name) || builtinScalars[name] {
+			continue
+		}
+
+		definition, ok := definitions[name]
+		a.True(t, ok, "type %s is missing from the SDL", name)
+		a.Equal(t, ptrToString(qlType.Description), definition.Description, name)
+
+		switch qlType.Kind {
+		case typeKindObject, typeKindInterface:
+			fields := qlType.Fields(isDeprecatedArgs{IncludeDeprecated: true})
+			a.Equal(t, len(fields), len(definition.Fields), name)
+			for idx, field := range fields {
+				fieldDefinition := definition.Fields[idx]
+				a.Equal(t, field.Name, fieldDefinition.Name, name)
+				a.Equal(t, ptrToString(field.Description), fieldDefinition.Description, name+"."+field.Name)
+				a.Equal(t, sdlTypeReference(field.Type), fieldDefinition.Type.String(), name+"."+field.Name)
+
+				deprecated := bytecode.FindDirective(fieldDefinition.Directives, "deprecated")
+				a.Equal(t, field.IsDeprecated, deprecated != nil, name+"."+field.Name)
+				if deprecated != nil {
+					reason := defaultDeprecationReason
+					if value := deprecated.Argument("reason"); value != nil {
+						reason = value.Value
+					}
+					a.Equal(t, *field.DeprecationReason, reason)
+				}
+
+				a.Equal(t, len(field.Args), len(fieldDefinition.Arguments), name+"."+field.Name)
+				for argIdx, arg := range field.Args {
+					argDefinition := fieldDefinition.Arguments[argIdx]
+					a.Equal(t, arg.Name, argDefinition.Name)
+					a.Equal(t, ptrToString(arg.Description), argDefinition.Description)
+					a.Equal(t, sdlTypeReference(arg.Type), argDefinition.Type.String())
+					a.Equal(t, arg.DefaultValue != nil, argDefinition.DefaultValue != nil)
+					if arg.DefaultValue != nil {
+						a.Equal(t, *arg.DefaultValue, argDefinition.DefaultValue.String())
+					}
+				}
+			}
+		case typeKindInputObject:
+			fields := qlType.InputFields()
+			a.Equal(t, len(fields), len(definition.InputFields), name)
+			for idx, field := range fields {
+				a.Equal(t, field.Name, definition.InputFields[idx].Name)
+				a.Equal(t, *field.DefaultValue, definition.InputFields[idx].DefaultValue.String())
+			}
+		case typeKindEnum:
+			a.Equal(t, len(qlType.EnumValues(isDeprecatedArgs{})), len(definition.EnumValues), name)
+		}
+	}
+}
+
+func TestSDLDescriptionEscaping(t *testing.T) {
+	options := []string{
+		`with "quotes"`,
+		`with a \ backslash`,
+		"with\nmultiple\n\nlines",
+		"  indented\n  lines",
+		`ends with """`,
+	}
+
+	for _, description := range options {
+		w := &sdlWriter{}
+		w.writeDescription(description, "")
+		w.write("scalar Foo\n")
+
+		doc, errs := bytecode.ParseSDL(w.res.Bytes())
+		for _, err := range errs {
+			t.Fatal(err)
+		}
+		a.Equal(t, description, doc.Types[0].Description)
+	}
+}
+
+func TestSDLNotParsed(t *testing.T) {
+	err := NewSchema().WriteSDL(bytes.NewBuffer(nil))
+	a.Error(t, err)
+}
+
+type SDLTestWriterErr struct{}
+
+func (SDLTestWriterErr) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriteSDLWriterError(t *testing.T) {
+	s := newSDLTestSchema(t)
+	err := s.WriteSDL(SDLTestWriterErr{})
+	a.Error(t, err)
+	a.True(t, strings.Contains(err.Error(), "write failed"))
+}
@@ -0,0 +1,237 @@
+package yarql
+
+import (
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	h "github.com/mjarkk/yarql/helpers"
+)
+
+// Types implementing encoding.TextMarshaler (outputs) or encoding.TextUnmarshaler (inputs) are exposed as a String scalar
+// Using the gq:",scalar=Name" struct tag a named scalar is used instead, the gq:",id" tag exposes the value as an ID
+//
+// []byte and [N]byte values are by default a list of Int values, with the gq:",base64" struct tag they become a base64 encoded String
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var timeType = reflect.TypeOf(time.Time{})
+
+// isTextMarshaler returns true if t or a pointer to t implements encoding.TextMarshaler
+// time.Time is excluded as it has its own scalar
+func isTextMarshaler(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface || t.Kind() == reflect.Ptr || t == timeType {
+		return false
+	}
+	return t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+// isTextUnmarshaler returns true if a pointer to t implements encoding.TextUnmarshaler
+// time.Time is excluded as it has its own scalar
+func isTextUnmarshaler(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface || t.Kind() == reflect.Ptr || t == timeType {
+		return false
+	}
+	return reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// textScalarName returns the name of the scalar used for a text value, an empty name means String
+func textScalarName(name string) string {
+	if name == "" {
+		return "String"
+	}
+	return name
+}
+
+// textScalarQLType returns the scalar type of a text value
+func (s *Schema) textScalarQLType(name string) *qlType {
+	var res qlType
+	switch name {
+	case "", "String":
+		res = scalarString
+	case "ID":
+		res = scalarID
+	default:
+		res = s.definedScalars[name]
+	}
+	return &res
+}
+
+// defineScalar adds a scalar with name to the schema, the scalar is used for text values tagged with gq:",scalar=Name"
+func (c *parseCtx) defineScalar(name string) error {
+	if name == "String" || name == "ID" {
+		// These are the built in scalars text values can be assigned to
+		return nil
+	}
+	if err := validGraphQlName([]byte(name)); err != nil || strings.HasPrefix(name, "__") {
+		return fmt.Errorf("invalid scalar name %s", name)
+	}
+	if builtinScalars[name] || sdlKnownScalar(name) {
+		return fmt.Errorf("cannot use the %s scalar for text values, only String, ID and custom scalars are allowed", name)
+	}
+	if _, ok := c.schema.definedScalars[name]; ok {
+		return nil
+	}
+
+	if c.schema.definedScalars == nil {
+		c.schema.definedScalars = map[string]qlType{}
+	}
+	if scalar, ok := builtinTextScalars[name]; ok {
+		c.schema.definedScalars[name] = scalar
+		return nil
+	}
+	c.schema.definedScalars[name] = qlType{
+		Kind:        typeKindScalar,
+		Name:        h.StrPtr(name),
+		Description: h.PtrToEmptyStr,
+	}
+	return nil
+}
+
+// checkScalarNames returns an error if a scalar defined by a text value has the same name as another type
+// This is checked after parsing all types as the other type might be found after the scalar
+func (s *Schema) checkScalarNames() error {
+	for name := range s.definedScalars {
+		_, isType := s.types[name]
+		_, isInput := s.inTypes[name]
+		_, isInterface := s.interfaces[name]
+		isEnum := false
+		for _, enum := range s.definedEnums {
+			isEnum = isEnum || enum.typeName == name
+		}
+		if isType || isInput || isInterface || isEnum {
+			return fmt.Errorf("cannot define scalar %s, a type with the same name already exists", name)
+		}
+	}
+	return nil
+}
+
+// applyScalarTag applies the scalar and base64 options of a struct field tag to the output value of the field
+func (c *parseCtx) applyScalarTag(item *obj, tag fieldTag) error {
+	if tag.scalar == "" && !tag.base64 {
+		return nil
+	}
+
+	for {
+		if item.valueType == valueTypePtr || (item.valueType == valueTypeArray && item.mapEntryType == nil && !(tag.base64 && item.innerContent.valueType == valueTypeData && item.innerContent.dataValueType == reflect.Uint8)) {
+			item = item.innerContent
+			continue
+		}
+		break
+	}
+
+	if tag.base64 {
+		if item.valueType != valueTypeArray || item.mapEntryType != nil {
+			return errors.New("base64 can only be set on []byte and [N]byte values")
+		}
+		*item = obj{
+			valueType:     valueTypeBase64,
+			typeName:      item.typeName,
+			typeNameBytes: item.typeNameBytes,
+			goTypeName:    item.goTypeName,
+			goPkgPath:     item.goPkgPath,
+			dataValueType: item.dataValueType,
+		}
+		return nil
+	}
+
+	if item.valueType != valueTypeText {
+		return fmt.Errorf("scalar can only be set on values implementing encoding.TextMarshaler")
+	}
+	if item.scalarName == "ID" && tag.scalar != "ID" {
+		return errors.New("scalar and id cannot be combined")
+	}
+	item.scalarName = tag.scalar
+	return c.defineScalar(tag.scalar)
+}
+
+// applyInputScalarTag applies the scalar and base64 options of a struct field tag to a input value
+func (c *parseCtx) applyInputScalarTag(in *input, tag fieldTag) error {
+	if tag.scalar == "" && !tag.base64 {
+		return nil
+	}
+
+	for !in.isText && !in.isBase64 && in.elem != nil && (in.kind == reflect.Ptr || in.kind == reflect.Slice || in.kind == reflect.Array || in.isOptional) {
+		if tag.base64 && in.kind != reflect.Ptr && !in.isOptional && in.elem.kind == reflect.Uint8 && !in.elem.isEnum && !in.elem.isID {
+			in.isBase64 = true
+			in.elem = nil
+			return nil
+		}
+		in = in.elem
+	}
+
+	if tag.base64 {
+		return errors.New("base64 can only be set on []byte and [N]byte values")
+	}
+	if !in.isText {
+		return fmt.Errorf("scalar can only be set on values implementing encoding.TextUnmarshaler")
+	}
+	if in.scalarName == "ID" && tag.scalar != "ID" {
+		return errors.New("scalar and id cannot be combined")
+	}
+	in.scalarName = tag.scalar
+	return c.defineScalar(tag.scalar)
+}
+
+// writeTextValue writes the result of MarshalText as JSON string
+func (ctx *Ctx) writeTextValue(goValue reflect.Value) bool {
+	marshaler, ok := goValue.Interface().(encoding.TextMarshaler)
+	if !ok {
+		// MarshalText has a pointer receiver
+		marshaler = pointerTo(goValue).Interface().(encoding.TextMarshaler)
+	}
+
+	text, err := marshaler.MarshalText()
+	if err != nil {
+		ctx.writeNull()
+		return ctx.err(err.Error())
+	}
+	h.StringToJSON(b2s(text), &ctx.schema.Result)
+	return false
+}
+
+// writeBase64Value writes a []byte or [N]byte value as base64 encoded JSON string
+func (ctx *Ctx) writeBase64Value(goValue reflect.Value) {
+	if goValue.Kind() == reflect.Slice && goValue.IsNil() {
+		ctx.writeNull()
+		return
+	}
+
+	value := make([]byte, goValue.Len())
+	reflect.Copy(reflect.ValueOf(value), goValue)
+
+	ctx.writeByte('"')
+	start := len(ctx.schema.Result)
+	ctx.schema.Result = append(ctx.schema.Result, make([]byte, base64.StdEncoding.EncodedLen(len(value)))...)
+	base64.StdEncoding.Encode(ctx.schema.Result[start:], value)
+	ctx.writeByte('"')
+}
+
+// assignTextValue binds a string to a text or base64 input value
+func (ctx *Ctx) assignTextValue(goValue *reflect.Value, valueStructure *input, stringValue string) bool {
+	if valueStructure.isBase64 {
+		value, err := base64.StdEncoding.DecodeString(stringValue)
+		if err != nil {
+			return ctx.err("invalid base64 value: " + err.Error())
+		}
+		if goValue.Kind() == reflect.Array {
+			if len(value) != goValue.Len() {
+				return ctx.errf("expected %d bytes but got %d", goValue.Len(), len(value))
+			}
+			reflect.Copy(*goValue, reflect.ValueOf(value))
+		} else {
+			goValue.SetBytes(value)
+		}
+		return false
+	}
+
+	err := goValue.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(stringValue))
+	if err != nil {
+		return ctx.err(err.Error())
+	}
+	return false
+}
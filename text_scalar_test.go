@@ -0,0 +1,202 @@
+package yarql
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+// TestTextMoney is stored as cents and has value receivers
+type TestTextMoney int64
+
+func (m TestTextMoney) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(m)/100, 10) + "." + strconv.FormatInt(int64(m)%100, 10)), nil
+}
+
+func (m *TestTextMoney) UnmarshalText(text []byte) error {
+	parts := strings.Split(string(text), ".")
+	if len(parts) != 2 {
+		return errors.New("invalid money value")
+	}
+	whole, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	cents, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return err
+	}
+	*m = TestTextMoney(whole*100 + cents)
+	return nil
+}
+
+// TestTextVersion has pointer receivers
+type TestTextVersion struct {
+	major int
+	minor int
+}
+
+func (v *TestTextVersion) MarshalText() ([]byte, error) {
+	return []byte("v" + strconv.Itoa(v.major) + "." + strconv.Itoa(v.minor)), nil
+}
+
+func (v *TestTextVersion) UnmarshalText(text []byte) error {
+	parts := strings.Split(strings.TrimPrefix(string(text), "v"), ".")
+	if len(parts) != 2 {
+		return errors.New("invalid version")
+	}
+	v.major, _ = strconv.Atoi(parts[0])
+	v.minor, _ = strconv.Atoi(parts[1])
+	return nil
+}
+
+type TestTextQuery struct {
+	IP       net.IP
+	Version  TestTextVersion
+	Versions []*TestTextVersion
+	Price    TestTextMoney `gq:",scalar=Money"`
+	UserID   TestTextMoney `gq:",id"`
+	Data     []byte        `gq:",base64"`
+	Hash     [4]byte       `gq:",base64"`
+	NoData   []byte        `gq:",base64"`
+	Raw      []byte
+}
+
+func (TestTextQuery) ResolveAddPrice(args struct {
+	A TestTextMoney  `gq:",scalar=Money"`
+	B *TestTextMoney `gq:",scalar=Money"`
+}) TestTextMoney {
+	if args.B == nil {
+		return args.A
+	}
+	return args.A + *args.B
+}
+
+func (TestTextQuery) ResolveNextVersion(args struct{ Version TestTextVersion }) TestTextVersion {
+	return TestTextVersion{major: args.Version.major, minor: args.Version.minor + 1}
+}
+
+func (TestTextQuery) ResolveDataLen(args struct {
+	Data []byte  `gq:",base64"`
+	Hash [4]byte `gq:",base64"`
+}) int {
+	sum := 0
+	for _, b := range args.Hash {
+		sum += int(b)
+	}
+	return len(args.Data)*1000 + sum
+}
+
+func TestTextOutput(t *testing.T) {
+	query := TestTextQuery{
+		IP:       net.IPv4(127, 0, 0, 1),
+		Version:  TestTextVersion{1, 2},
+		Versions: []*TestTextVersion{{2, 0}, nil},
+		Price:    1250,
+		UserID:   4200,
+		Data:     []byte("hello"),
+		Hash:     [4]byte{1, 2, 3, 4},
+		Raw:      []byte{1, 2},
+	}
+	res := bytecodeParseAndExpectNoErrs(t, `{IP version versions price userID data hash noData raw}`, query, M{})
+	a.Equal(t, `{"IP":"127.0.0.1","version":"v1.2","versions":["v2.0",null],"price":"12.50","userID":"42.0","data":"aGVsbG8=","hash":"AQIDBA==","noData":null,"raw":[1,2]}`, res)
+}
+
+func TestTextInput(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"custom scalar", `{addPrice(a: "1.50", b: "2.25")}`, ``, `{"addPrice":"3.75"}`},
+		{"null", `{addPrice(a: "1.50", b: null)}`, ``, `{"addPrice":"1.50"}`},
+		{"pointer receivers", `{nextVersion(version: "v1.9")}`, ``, `{"nextVersion":"v1.10"}`},
+		{"base64", `{dataLen(data: "aGVsbG8=", hash: "AQIDBA==")}`, ``, `{"dataLen":5010}`},
+		{"custom scalar variables", `query ($a: Money, $b: Money) {addPrice(a: $a, b: $b)}`, `{"a": "1.1", "b": "0.2"}`, `{"addPrice":"1.3"}`},
+		{"string variable", `query ($version: String) {nextVersion(version: $version)}`, `{"version": "v3.0"}`, `{"nextVersion":"v3.1"}`},
+		{"base64 variable", `query ($data: String) {dataLen(data: $data)}`, `{"data": "aGk="}`, `{"dataLen":2000}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			res := bytecodeParseAndExpectNoErrs(t, testCase.query, TestTextQuery{}, M{}, opts)
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestTextInputInvalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+	}{
+		{"unmarshal error", `{addPrice(a: "foo")}`, ``},
+		{"not a string", `{addPrice(a: 10)}`, ``},
+		{"base64 list", `{dataLen(data: [1, 2])}`, ``},
+		{"base64 wrong length", `{dataLen(hash: "aGk=")}`, ``},
+		{"invalid base64", `{dataLen(data: "not base64")}`, ``},
+		{"wrong variable type", `query ($a: String) {addPrice(a: $a)}`, `{"a": "1.0"}`},
+		{"wrong variable value", `query ($a: Money) {addPrice(a: $a)}`, `{"a": 10}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			_, errs := bytecodeParseAndExpectErrs(t, testCase.query, TestTextQuery{}, M{}, opts)
+			a.Equal(t, 1, len(errs))
+		})
+	}
+}
+
+func TestTextSDL(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestTextQuery{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	expected := []string{
+		"scalar Money\n",
+		"  addPrice(a: Money!, b: Money): String!\n",
+		"  dataLen(data: String, hash: String!): Int!\n",
+		"  data: String\n",
+		"  hash: String!\n",
+		"  IP: String!\n",
+		"  nextVersion(version: String!): String!\n",
+		"  price: Money!\n",
+		"  raw: [Int!]\n",
+		"  userID: ID!\n",
+		"  versions: [String]\n",
+	}
+	for _, part := range expected {
+		a.True(t, strings.Contains(sdl, part), part+"\n"+sdl)
+	}
+}
+
+func TestTextInvalid(t *testing.T) {
+	options := []interface{}{
+		struct {
+			A int `gq:",scalar=Money"`
+		}{},
+		struct {
+			A []int `gq:",base64"`
+		}{},
+		struct {
+			A TestTextMoney `gq:",scalar=Int"`
+		}{},
+		struct {
+			A      TestTextMoney `gq:",scalar=TestTextQuery"`
+			Others TestTextQuery
+		}{},
+	}
+	for _, option := range options {
+		err := NewSchema().Parse(option, M{}, nil)
+		a.Error(t, err)
+	}
+}
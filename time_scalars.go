@@ -0,0 +1,208 @@
+package yarql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mjarkk/yarql/helpers"
+)
+
+// Date is a civil date without a time or timezone, exposed as the Date scalar formatted like 2006-01-02
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of t in the location of t
+func DateOf(t time.Time) Date {
+	var d Date
+	d.Year, d.Month, d.Day = t.Date()
+	return d
+}
+
+// In returns the start of the date in loc
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// String returns the date formatted like 2006-01-02
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := time.Parse("2006-01-02", string(text))
+	if err != nil {
+		return errors.New("date value doesn't match the layout 2006-01-02")
+	}
+	*d = DateOf(t)
+	return nil
+}
+
+// LocalTime is a time of day without a date or timezone, exposed as the LocalTime scalar formatted like 15:04:05 with optional fractional seconds
+type LocalTime struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// LocalTimeOf returns the time of day of t in the location of t
+func LocalTimeOf(t time.Time) LocalTime {
+	var lt LocalTime
+	lt.Hour, lt.Minute, lt.Second = t.Clock()
+	lt.Nanosecond = t.Nanosecond()
+	return lt
+}
+
+// String returns the time formatted like 15:04:05, fractional seconds are only added if set
+func (t LocalTime) String() string {
+	res := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond != 0 {
+		res += "." + strings.TrimRight(fmt.Sprintf("%09d", t.Nanosecond), "0")
+	}
+	return res
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (t LocalTime) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (t *LocalTime) UnmarshalText(text []byte) error {
+	// time.Parse accepts fractional seconds after the seconds even if the layout doesn't contain them
+	parsed, err := time.Parse("15:04:05", string(text))
+	if err != nil {
+		return errors.New("local time value doesn't match the layout 15:04:05")
+	}
+	*t = LocalTimeOf(parsed)
+	return nil
+}
+
+// Duration is a time.Duration exposed as the Duration scalar formatted like time.Duration.String, for example 1h30m0s
+type Duration time.Duration
+
+// String returns the duration formatted like time.Duration.String
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return errors.New("invalid duration " + strconv.Quote(string(text)) + ", expected a value like 1h30m")
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// builtinTextScalarTypes are the go types with a dedicated scalar instead of String
+var builtinTextScalarTypes = map[reflect.Type]string{
+	reflect.TypeOf(Date{}):      "Date",
+	reflect.TypeOf(LocalTime{}): "LocalTime",
+	reflect.TypeOf(Duration(0)): "Duration",
+}
+
+// timeLayouts are the layout constants of the time package that can be used by name as time layout
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+}
+
+// resolveTimeLayout returns the layout of a layout constant name like RFC3339, other values are returned as is
+func resolveTimeLayout(layout string) string {
+	if value, ok := timeLayouts[layout]; ok {
+		return value
+	}
+	return layout
+}
+
+// applyTimeLayoutTag sets the time layout of a struct field tagged with gqTimeLayout
+func applyTimeLayoutTag(item *obj, tag fieldTag) error {
+	if tag.timeLayout == "" {
+		return nil
+	}
+	for item.valueType == valueTypePtr || (item.valueType == valueTypeArray && item.mapEntryType == nil) {
+		item = item.innerContent
+	}
+	if item.valueType != valueTypeTime {
+		return errors.New("gqTimeLayout can only be set on time.Time values")
+	}
+	item.timeLayout = tag.timeLayout
+	return nil
+}
+
+// applyInputTimeLayoutTag sets the time layout of a input struct field tagged with gqTimeLayout
+func applyInputTimeLayoutTag(in *input, tag fieldTag) error {
+	if tag.timeLayout == "" {
+		return nil
+	}
+	for !in.isTime && in.elem != nil && (in.kind == reflect.Ptr || in.kind == reflect.Slice || in.kind == reflect.Array || in.isOptional) {
+		in = in.elem
+	}
+	if !in.isTime {
+		return errors.New("gqTimeLayout can only be set on time.Time values")
+	}
+	in.timeLayout = tag.timeLayout
+	return nil
+}
+
+// writeTime writes a time as JSON string using the layout of the field or schema
+func (ctx *Ctx) writeTime(t time.Time, layout string) {
+	if layout == "" {
+		layout = ctx.schema.timeLayout
+	}
+	if layout == "" {
+		ctx.writeByte('"')
+		helpers.TimeToIso8601String(&ctx.schema.Result, t)
+		ctx.writeByte('"')
+		return
+	}
+	helpers.StringToJSON(t.Format(layout), &ctx.schema.Result)
+}
+
+// parseTime parses a time using the layout of the field or schema
+func (ctx *Ctx) parseTime(value string, layout string) (time.Time, error) {
+	if layout == "" {
+		layout = ctx.schema.timeLayout
+	}
+	if layout == "" {
+		return helpers.ParseIso8601String(value)
+	}
+	parsedTime, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, errors.New("time value doesn't match the layout " + layout)
+	}
+	return parsedTime, nil
+}
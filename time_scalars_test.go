@@ -0,0 +1,177 @@
+package yarql
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestTimeScalarsQuery struct {
+	At        time.Time
+	AtLayout  time.Time    `gqTimeLayout:"2006-01-02 15:04 MST"`
+	AtList    []*time.Time `gqTimeLayout:"Kitchen"`
+	Birthday  Date
+	OpensAt   LocalTime
+	Precise   LocalTime
+	Timeout   Duration
+	Durations []Duration
+}
+
+func (TestTimeScalarsQuery) ResolveEchoTime(args struct{ Value time.Time }) time.Time {
+	return args.Value
+}
+
+func (TestTimeScalarsQuery) ResolveEchoLayout(args struct {
+	Value time.Time `gqTimeLayout:"02/01/2006"`
+}) string {
+	return args.Value.Format("2006-01-02")
+}
+
+func (TestTimeScalarsQuery) ResolveNextDay(args struct{ Date Date }) Date {
+	return DateOf(args.Date.In(time.UTC).AddDate(0, 0, 1))
+}
+
+func (TestTimeScalarsQuery) ResolveAddMinute(args struct{ Time LocalTime }) LocalTime {
+	return LocalTimeOf(time.Date(0, 1, 1, args.Time.Hour, args.Time.Minute, args.Time.Second, args.Time.Nanosecond, time.UTC).Add(time.Minute))
+}
+
+func (TestTimeScalarsQuery) ResolveDouble(args struct{ Duration *Duration }) *Duration {
+	if args.Duration == nil {
+		return nil
+	}
+	res := *args.Duration * 2
+	return &res
+}
+
+var testTimeScalarsZone = time.FixedZone("CEST", 2*60*60)
+
+var testTimeScalarsAt = time.Date(2021, 5, 4, 12, 30, 15, 500000000, testTimeScalarsZone)
+
+func TestTimeScalarsOutput(t *testing.T) {
+	query := TestTimeScalarsQuery{
+		At:        testTimeScalarsAt,
+		AtLayout:  testTimeScalarsAt,
+		AtList:    []*time.Time{&testTimeScalarsAt, nil},
+		Birthday:  Date{Year: 1999, Month: time.December, Day: 31},
+		OpensAt:   LocalTime{Hour: 9, Minute: 5},
+		Precise:   LocalTime{Hour: 23, Minute: 59, Second: 59, Nanosecond: 120000000},
+		Timeout:   Duration(90 * time.Minute),
+		Durations: []Duration{Duration(time.Millisecond), 0},
+	}
+	res := bytecodeParseAndExpectNoErrs(t, `{at atLayout atList birthday opensAt precise timeout durations}`, query, M{})
+	a.Equal(t, `{"at":"2021-05-04T10:30:15.500Z","atLayout":"2021-05-04 12:30 CEST","atList":["12:30PM",null],"birthday":"1999-12-31","opensAt":"09:05:00","precise":"23:59:59.12","timeout":"1h30m0s","durations":["1ms","0s"]}`, res)
+}
+
+func TestTimeScalarsInput(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		variables string
+		expected  string
+	}{
+		{"time", `{echoTime(value: "2021-05-04T10:30:15.500Z")}`, ``, `{"echoTime":"2021-05-04T10:30:15.500Z"}`},
+		// RFC 3339 values with an offset and without milliseconds are converted to UTC
+		{"time with offset", `{echoTime(value: "2021-05-04T12:30:15+02:00")}`, ``, `{"echoTime":"2021-05-04T10:30:15.000Z"}`},
+		{"time layout", `{echoLayout(value: "31/12/1999")}`, ``, `{"echoLayout":"1999-12-31"}`},
+		{"date", `{nextDay(date: "1999-12-31")}`, ``, `{"nextDay":"2000-01-01"}`},
+		{"local time", `{addMinute(time: "23:59:30.5")}`, ``, `{"addMinute":"00:00:30.5"}`},
+		{"duration", `{double(duration: "1h2m")}`, ``, `{"double":"2h4m0s"}`},
+		{"null", `{double(duration: null)}`, ``, `{"double":null}`},
+		{"date variable", `query ($date: Date!) {nextDay(date: $date)}`, `{"date": "2020-02-28"}`, `{"nextDay":"2020-02-29"}`},
+		{"duration variable", `query ($duration: Duration) {double(duration: $duration)}`, `{"duration": "250ms"}`, `{"double":"500ms"}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: testCase.variables}
+			res := bytecodeParseAndExpectNoErrs(t, testCase.query, TestTimeScalarsQuery{}, M{}, opts)
+			a.Equal(t, testCase.expected, res)
+		})
+	}
+}
+
+func TestTimeScalarsInputInvalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"time", `{echoTime(value: "04-05-2021")}`},
+		{"time layout", `{echoLayout(value: "1999-12-31")}`},
+		{"date", `{nextDay(date: "1999-13-01")}`},
+		{"local time", `{addMinute(time: "25:00:00")}`},
+		{"duration", `{double(duration: "10 minutes")}`},
+		{"wrong variable type", `query ($date: String) {nextDay(date: $date)}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ResolveOptions{NoMeta: true, Variables: `{"date": "2020-02-28"}`}
+			_, errs := bytecodeParseAndExpectErrs(t, testCase.query, TestTimeScalarsQuery{}, M{}, opts)
+			a.Equal(t, 1, len(errs))
+		})
+	}
+}
+
+func TestTimeScalarsSchemaLayout(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestTimeScalarsQuery{At: testTimeScalarsAt, AtLayout: testTimeScalarsAt}, M{}, &SchemaOptions{TimeLayout: "RFC3339"})
+	a.NoError(t, err)
+
+	// The timezone offset is kept
+	errs := s.Resolve([]byte(`{at atLayout echoTime(value: "2021-05-04T12:30:15+02:00")}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"at":"2021-05-04T12:30:15+02:00","atLayout":"2021-05-04 12:30 CEST","echoTime":"2021-05-04T12:30:15+02:00"}`, string(s.Result))
+
+	errs = s.Resolve([]byte(`{echoTime(value: "2021-05-04T10:30:15Z")}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"echoTime":"2021-05-04T10:30:15Z"}`, string(s.Result))
+
+	// The copy keeps the layout
+	copied := s.Copy()
+	errs = copied.Resolve([]byte(`{at}`), ResolveOptions{NoMeta: true})
+	for _, err := range errs {
+		t.Fatal(err)
+	}
+	a.Equal(t, `{"at":"2021-05-04T12:30:15+02:00"}`, string(copied.Result))
+}
+
+func TestTimeScalarsSchema(t *testing.T) {
+	s := NewSchema()
+	err := s.Parse(TestTimeScalarsQuery{}, M{}, nil)
+	a.NoError(t, err)
+
+	sdl := s.SDL()
+	expected := []string{
+		"scalar Date @specifiedBy(",
+		"scalar Duration @specifiedBy(",
+		"scalar LocalTime @specifiedBy(",
+		"  birthday: Date!\n",
+		"  double(duration: Duration): Duration\n",
+		"  durations: [Duration!]\n",
+		"  nextDay(date: Date!): Date!\n",
+		"  opensAt: LocalTime!\n",
+	}
+	for _, part := range expected {
+		a.True(t, strings.Contains(sdl, part), part+"\n"+sdl)
+	}
+
+	res := bytecodeParseAndExpectNoErrs(t, `{__type(name: "LocalTime") {kind name}}`, TestTimeScalarsQuery{}, M{})
+	a.Equal(t, `{"__type":{"kind":"SCALAR","name":"LocalTime"}}`, res)
+
+	// The scalars are only part of schemas that use them
+	s = NewSchema()
+	err = s.Parse(struct{ At time.Time }{}, M{}, nil)
+	a.NoError(t, err)
+	a.False(t, strings.Contains(s.SDL(), "scalar Date"))
+
+	err = NewSchema().Parse(struct {
+		A string `gqTimeLayout:"RFC3339"`
+	}{}, M{}, nil)
+	a.Error(t, err)
+}
@@ -0,0 +1,290 @@
+package yarql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Tracer receives events while an operation is resolved, it can be used to export spans to tracing backends
+// Set a tracer using ResolveOptions.Tracer
+//
+// The hooks are called in this order:
+//   OperationStart
+//   ParseStart, ParseEnd
+//   ValidationStart, ValidationEnd
+//   FieldStart, FieldEnd (for every resolved field, nested fields are started and ended within their parent)
+//   OperationEnd
+//
+// OperationStart and FieldStart receive the context of the parent and return the context of the new span,
+// this context is passed to the matching end hook, the hooks of nested fields and the resolvers (see (*Ctx).GetContext)
+// so a tracer can store its span in the context and child spans nest under it.
+// The operation context is based on ResolveOptions.Context or context.Background() if it's not set
+//
+// The hooks are called from the goroutine that resolves the operation,
+// if the same tracer is used for concurrent operations it must be safe for concurrent use
+type Tracer interface {
+	// OperationStart is called before the query is parsed and returns the context of the operation
+	OperationStart(ctx context.Context) context.Context
+	// OperationEnd is called after the operation is resolved but before the errors and extensions are written
+	OperationEnd(ctx context.Context, operation OperationInfo)
+
+	// ParseStart and ParseEnd are called around parsing the query, errs contains the syntax errors
+	ParseStart(ctx context.Context)
+	ParseEnd(ctx context.Context, errs []error)
+
+	// ValidationStart and ValidationEnd are called around selecting the operation to execute
+	// Note that most validation is done while resolving, those errors are reported to the fields and operation
+	ValidationStart(ctx context.Context)
+	ValidationEnd(ctx context.Context, errs []error)
+
+	// FieldStart is called before resolving a field, ctx is the context of the operation or parent field
+	// The returned context is used for the resolver and sub fields of this field
+	FieldStart(ctx context.Context, field FieldInfo) context.Context
+	// FieldEnd is called after resolving a field with the context returned by FieldStart
+	// It receives the same field info with the errors set
+	FieldEnd(ctx context.Context, field FieldInfo)
+}
+
+// TracerExtension can be implemented by a Tracer to add a value to the extensions of the response
+type TracerExtension interface {
+	Tracer
+	// Extension is called after OperationEnd and returns the key and json value to add to the extensions
+	// If value is nil nothing is added
+	Extension() (key string, value json.RawMessage)
+}
+
+// OperationInfo describes the resolved operation
+type OperationInfo struct {
+	// Name is the name of the operation, empty for anonymous operations
+	Name string
+	// Type is query, mutation or subscription, empty if no operation was executed
+	Type string
+	// Errors are all errors of the operation
+	Errors []error
+}
+
+// FieldInfo describes a resolved field
+type FieldInfo struct {
+	// Path is the json encoded path to the field, for example ["users",0,"name"]
+	Path string
+	// ParentType is the name of the type the field is defined on
+	ParentType string
+	// FieldName is the name of the field in the schema, the alias is part of the path
+	FieldName string
+	// ReturnType is the graphql type of the field, for example [User!]!
+	ReturnType string
+	// Errors are the errors of this field, only set in FieldEnd
+	// Errors of list items are included but errors of sub fields are only reported to the sub fields
+	Errors []error
+}
+
+// MultiTracer sends all events to every tracer in the list
+// The context returned by a start hook is passed on to the next tracer so every tracer can add its span to it
+type MultiTracer []Tracer
+
+// OperationStart implements Tracer
+func (m MultiTracer) OperationStart(ctx context.Context) context.Context {
+	for _, t := range m {
+		ctx = t.OperationStart(ctx)
+	}
+	return ctx
+}
+
+// OperationEnd implements Tracer
+func (m MultiTracer) OperationEnd(ctx context.Context, operation OperationInfo) {
+	for _, t := range m {
+		t.OperationEnd(ctx, operation)
+	}
+}
+
+// ParseStart implements Tracer
+func (m MultiTracer) ParseStart(ctx context.Context) {
+	for _, t := range m {
+		t.ParseStart(ctx)
+	}
+}
+
+// ParseEnd implements Tracer
+func (m MultiTracer) ParseEnd(ctx context.Context, errs []error) {
+	for _, t := range m {
+		t.ParseEnd(ctx, errs)
+	}
+}
+
+// ValidationStart implements Tracer
+func (m MultiTracer) ValidationStart(ctx context.Context) {
+	for _, t := range m {
+		t.ValidationStart(ctx)
+	}
+}
+
+// ValidationEnd implements Tracer
+func (m MultiTracer) ValidationEnd(ctx context.Context, errs []error) {
+	for _, t := range m {
+		t.ValidationEnd(ctx, errs)
+	}
+}
+
+// FieldStart implements Tracer
+func (m MultiTracer) FieldStart(ctx context.Context, field FieldInfo) context.Context {
+	for _, t := range m {
+		ctx = t.FieldStart(ctx, field)
+	}
+	return ctx
+}
+
+// FieldEnd implements Tracer
+func (m MultiTracer) FieldEnd(ctx context.Context, field FieldInfo) {
+	for _, t := range m {
+		t.FieldEnd(ctx, field)
+	}
+}
+
+// TraceRecorder is a Tracer that records all events in memory, it's meant to be used in tests
+// The recorded operation and fields are stored in the context so concurrent operations can be recorded
+type TraceRecorder struct {
+	lock       sync.Mutex
+	operations []*RecordedOperation
+}
+
+// traceRecorderKey is the context key of the operation or field recorded by a TraceRecorder
+type traceRecorderKey struct {
+	recorder *TraceRecorder
+	field    bool
+}
+
+// RecordedOperation is an operation recorded by TraceRecorder
+type RecordedOperation struct {
+	OperationInfo
+	Start            time.Time
+	End              time.Time
+	ParseStart       time.Time
+	ParseEnd         time.Time
+	ParseErrors      []error
+	ValidationStart  time.Time
+	ValidationEnd    time.Time
+	ValidationErrors []error
+	// Fields are the resolved fields in the order they were started
+	Fields []*RecordedField
+}
+
+// RecordedField is a field recorded by TraceRecorder
+type RecordedField struct {
+	FieldInfo
+	Start time.Time
+	End   time.Time
+	// Parent is the field this field is nested in, nil for fields of the operation
+	Parent *RecordedField
+}
+
+// NewTraceRecorder returns a new TraceRecorder
+func NewTraceRecorder() *TraceRecorder {
+	return &TraceRecorder{}
+}
+
+// Operations returns the recorded operations
+func (r *TraceRecorder) Operations() []*RecordedOperation {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*RecordedOperation{}, r.operations...)
+}
+
+// Reset removes all recorded operations
+func (r *TraceRecorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.operations = nil
+}
+
+// operation returns the operation stored in ctx by OperationStart
+func (r *TraceRecorder) operation(ctx context.Context) *RecordedOperation {
+	operation, _ := ctx.Value(traceRecorderKey{recorder: r}).(*RecordedOperation)
+	return operation
+}
+
+// field returns the field stored in ctx by FieldStart
+func (r *TraceRecorder) field(ctx context.Context) *RecordedField {
+	field, _ := ctx.Value(traceRecorderKey{recorder: r, field: true}).(*RecordedField)
+	return field
+}
+
+// OperationStart implements Tracer
+func (r *TraceRecorder) OperationStart(ctx context.Context) context.Context {
+	return context.WithValue(ctx, traceRecorderKey{recorder: r}, &RecordedOperation{Start: time.Now()})
+}
+
+// OperationEnd implements Tracer
+func (r *TraceRecorder) OperationEnd(ctx context.Context, operation OperationInfo) {
+	recorded := r.operation(ctx)
+	if recorded == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	recorded.OperationInfo = operation
+	recorded.Errors = append([]error{}, operation.Errors...)
+	recorded.End = time.Now()
+	r.operations = append(r.operations, recorded)
+}
+
+// ParseStart implements Tracer
+func (r *TraceRecorder) ParseStart(ctx context.Context) {
+	if recorded := r.operation(ctx); recorded != nil {
+		recorded.ParseStart = time.Now()
+	}
+}
+
+// ParseEnd implements Tracer
+func (r *TraceRecorder) ParseEnd(ctx context.Context, errs []error) {
+	if recorded := r.operation(ctx); recorded != nil {
+		recorded.ParseEnd = time.Now()
+		recorded.ParseErrors = append([]error{}, errs...)
+	}
+}
+
+// ValidationStart implements Tracer
+func (r *TraceRecorder) ValidationStart(ctx context.Context) {
+	if recorded := r.operation(ctx); recorded != nil {
+		recorded.ValidationStart = time.Now()
+	}
+}
+
+// ValidationEnd implements Tracer
+func (r *TraceRecorder) ValidationEnd(ctx context.Context, errs []error) {
+	if recorded := r.operation(ctx); recorded != nil {
+		recorded.ValidationEnd = time.Now()
+		recorded.ValidationErrors = append([]error{}, errs...)
+	}
+}
+
+// FieldStart implements Tracer
+func (r *TraceRecorder) FieldStart(ctx context.Context, field FieldInfo) context.Context {
+	operation := r.operation(ctx)
+	if operation == nil {
+		return ctx
+	}
+	recorded := &RecordedField{FieldInfo: field, Start: time.Now(), Parent: r.field(ctx)}
+	operation.Fields = append(operation.Fields, recorded)
+	return context.WithValue(ctx, traceRecorderKey{recorder: r, field: true}, recorded)
+}
+
+// FieldEnd implements Tracer
+func (r *TraceRecorder) FieldEnd(ctx context.Context, field FieldInfo) {
+	if recorded := r.field(ctx); recorded != nil {
+		recorded.End = time.Now()
+		recorded.Errors = append([]error{}, field.Errors...)
+	}
+}
+
+// Field returns the first recorded field with the given path or nil if there is none
+func (o *RecordedOperation) Field(path string) *RecordedField {
+	for _, field := range o.Fields {
+		if field.Path == path {
+			return field
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package yarql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestTracingDataUser struct {
+	Name string
+}
+
+func (TestTracingDataUser) ResolveFriends() ([]TestTracingDataUser, error) {
+	return nil, errors.New("friends are private")
+}
+
+type TestTracingData struct {
+	Users []TestTracingDataUser
+}
+
+func TestTracerRecorder(t *testing.T) {
+	recorder := NewTraceRecorder()
+	schema := TestTracingData{Users: []TestTracingDataUser{{Name: "a"}, {Name: "b"}}}
+
+	_, errs := bytecodeParse(t, NewSchema(), `query GetUsers {users {name friends {name}}}`, schema, M{}, ResolveOptions{NoMeta: true, Tracer: recorder})
+	a.Equal(t, 2, len(errs))
+
+	operations := recorder.Operations()
+	a.Equal(t, 1, len(operations))
+	operation := operations[0]
+	a.Equal(t, "GetUsers", operation.Name)
+	a.Equal(t, "query", operation.Type)
+	a.Equal(t, 2, len(operation.Errors))
+	a.Equal(t, 0, len(operation.ParseErrors))
+	a.False(t, operation.ParseEnd.Before(operation.ParseStart))
+	a.False(t, operation.End.Before(operation.Start))
+
+	// users + 2 * (name + friends)
+	a.Equal(t, 5, len(operation.Fields))
+
+	users := operation.Field(`["users"]`)
+	a.NotNil(t, users)
+	a.Equal(t, "TestTracingData", users.ParentType)
+	a.Equal(t, "users", users.FieldName)
+	a.Equal(t, "[TestTracingDataUser]!", users.ReturnType)
+	a.Equal(t, 0, len(users.Errors))
+	a.False(t, users.End.Before(users.Start))
+
+	friends := operation.Field(`["users",1,"friends"]`)
+	a.NotNil(t, friends)
+	a.Equal(t, "TestTracingDataUser", friends.ParentType)
+	a.Equal(t, 1, len(friends.Errors))
+	a.Equal(t, "friends are private", friends.Errors[0].Error())
+
+	// Nested fields are resolved within their parent
+	name := operation.Field(`["users",0,"name"]`)
+	a.NotNil(t, name)
+	a.False(t, name.Start.Before(users.Start))
+	a.False(t, users.End.Before(name.End))
+
+	// The context returned by FieldStart is used for the sub fields
+	a.Nil(t, users.Parent)
+	a.Equal(t, users, name.Parent)
+	a.Equal(t, users, friends.Parent)
+}
+
+type TestTracingContextData struct {
+	Recorder *TraceRecorder `gq:"-"`
+}
+
+type TestTracingContextSpan struct {
+	Recorder *TraceRecorder `gq:"-"`
+}
+
+func (d TestTracingContextData) ResolveSpan() TestTracingContextSpan {
+	return TestTracingContextSpan{Recorder: d.Recorder}
+}
+
+func (s TestTracingContextSpan) ResolvePath(ctx context.Context) string {
+	return s.Recorder.field(ctx).Path
+}
+
+type testTracingContextKey struct{}
+
+func TestTracerContext(t *testing.T) {
+	recorder := NewTraceRecorder()
+	requestContext := context.WithValue(context.Background(), testTracingContextKey{}, "request")
+
+	var parentContext context.Context
+	tracer := MultiTracer{testContextTracer{onField: func(ctx context.Context) { parentContext = ctx }}, recorder}
+	res, errs := bytecodeParse(t, NewSchema(), `{span {path}}`, TestTracingContextData{Recorder: recorder}, M{}, ResolveOptions{
+		NoMeta:  true,
+		Context: requestContext,
+		Tracer:  tracer,
+	})
+	for _, err := range errs {
+		panic(err)
+	}
+
+	// The resolver receives the context of its own field span
+	a.Equal(t, `{"span":{"path":"[\"span\",\"path\"]"}}`, res)
+
+	// The parent context of a field span is the span of the parent field, derived from the request context
+	a.NotNil(t, parentContext)
+	a.Equal(t, "request", parentContext.Value(testTracingContextKey{}))
+	a.Equal(t, `["span"]`, recorder.field(parentContext).Path)
+	a.NotNil(t, recorder.operation(parentContext))
+}
+
+// testContextTracer calls onField with the parent context of the field span
+type testContextTracer struct {
+	onField func(ctx context.Context)
+}
+
+func (testContextTracer) OperationStart(ctx context.Context) context.Context        { return ctx }
+func (testContextTracer) OperationEnd(ctx context.Context, operation OperationInfo) {}
+func (testContextTracer) ParseStart(ctx context.Context)                            {}
+func (testContextTracer) ParseEnd(ctx context.Context, errs []error)                {}
+func (testContextTracer) ValidationStart(ctx context.Context)                       {}
+func (testContextTracer) ValidationEnd(ctx context.Context, errs []error)           {}
+func (t testContextTracer) FieldStart(ctx context.Context, field FieldInfo) context.Context {
+	if field.FieldName == "path" {
+		t.onField(ctx)
+	}
+	return ctx
+}
+func (testContextTracer) FieldEnd(ctx context.Context, field FieldInfo) {}
+
+func TestTracerParseAndValidationErrors(t *testing.T) {
+	recorder := NewTraceRecorder()
+
+	_, errs := bytecodeParse(t, NewSchema(), `{users`, TestTracingData{}, M{}, ResolveOptions{NoMeta: true, Tracer: recorder})
+	a.Equal(t, 1, len(errs))
+	operation := recorder.Operations()[0]
+	a.Equal(t, 1, len(operation.ParseErrors))
+	a.Equal(t, "", operation.Type)
+	a.Equal(t, 0, len(operation.Fields))
+
+	recorder.Reset()
+	_, errs = bytecodeParse(t, NewSchema(), `query A {users {name}}`, TestTracingData{}, M{}, ResolveOptions{NoMeta: true, Tracer: recorder, OperatorTarget: "B"})
+	a.Equal(t, 1, len(errs))
+	operation = recorder.Operations()[0]
+	a.Equal(t, 0, len(operation.ParseErrors))
+	a.Equal(t, 1, len(operation.ValidationErrors))
+}
+
+func TestTracerWithApolloTracing(t *testing.T) {
+	recorder := NewTraceRecorder()
+	apolloTracer := NewApolloTracer()
+
+	res, errs := bytecodeParse(t, NewSchema(), `{users {name}}`, TestTracingData{Users: []TestTracingDataUser{{Name: "a"}}}, M{}, ResolveOptions{
+		Tracer: MultiTracer{recorder, apolloTracer},
+	})
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, 1, len(recorder.Operations()))
+
+	parsedRes := struct {
+		Extensions map[string]tracer `json:"extensions"`
+	}{}
+	err := json.Unmarshal([]byte(res), &parsedRes)
+	a.NoError(t, err)
+
+	// The apollo tracer inside the multi tracer still adds its extension
+	trace := parsedRes.Extensions["tracing"]
+	a.Equal(t, uint8(1), trace.Version)
+	a.Equal(t, 2, len(trace.Execution.Resolvers))
+	a.Equal(t, `["users",0,"name"]`, string(trace.Execution.Resolvers[0].Path))
+	a.Equal(t, `["users"]`, string(trace.Execution.Resolvers[1].Path))
+}
@@ -0,0 +1,123 @@
+package yarql
+
+import (
+	"errors"
+	"reflect"
+)
+
+// typeRegistry contains the interface implementations and renamed types registered using Implements and TypeRename
+// All keys are the package path and name of the go type, see typeKey
+type typeRegistry struct {
+	// implementations contains the struct types implementing an interface
+	implementations map[string][]reflect.Type
+
+	// structInterfaces contains the interfaces implemented by a struct
+	structInterfaces map[string][]reflect.Type
+
+	// interfaces contains all registered interfaces
+	interfaces map[string]reflect.Type
+
+	// interfaceParents contains the interfaces a interface is registered to implement
+	interfaceParents map[string][]reflect.Type
+
+	// renamedTypes contains the graphql names of renamed types
+	renamedTypes map[string]string
+}
+
+// globalTypeRegistry is used by the package level Implements and TypeRename functions and shared by all schemas
+var globalTypeRegistry = newTypeRegistry()
+
+func newTypeRegistry() *typeRegistry {
+	return &typeRegistry{
+		implementations:  map[string][]reflect.Type{},
+		structInterfaces: map[string][]reflect.Type{},
+		interfaces:       map[string]reflect.Type{},
+		interfaceParents: map[string][]reflect.Type{},
+		renamedTypes:     map[string]string{},
+	}
+}
+
+// typeKey returns the package path and name of the go type
+func typeKey(t reflect.Type) string {
+	return t.PkgPath() + "." + t.Name()
+}
+
+// merge returns a new registry containing the types of both r and other
+// The type renames of other take precedence over the ones of r
+func (r *typeRegistry) merge(other *typeRegistry) *typeRegistry {
+	res := newTypeRegistry()
+	for _, registry := range []*typeRegistry{r, other} {
+		if registry == nil {
+			continue
+		}
+		for key, types := range registry.implementations {
+			for _, t := range types {
+				if !containsType(res.implementations[key], t) {
+					res.implementations[key] = append(res.implementations[key], t)
+				}
+			}
+		}
+		for key, types := range registry.structInterfaces {
+			for _, t := range types {
+				if !containsType(res.structInterfaces[key], t) {
+					res.structInterfaces[key] = append(res.structInterfaces[key], t)
+				}
+			}
+		}
+		for key, types := range registry.interfaceParents {
+			for _, t := range types {
+				if !containsType(res.interfaceParents[key], t) {
+					res.interfaceParents[key] = append(res.interfaceParents[key], t)
+				}
+			}
+		}
+		for key, t := range registry.interfaces {
+			res.interfaces[key] = t
+		}
+		for key, name := range registry.renamedTypes {
+			res.renamedTypes[key] = name
+		}
+	}
+	return res
+}
+
+// typeName returns the graphql name of the go type t
+func (r *typeRegistry) typeName(t reflect.Type) string {
+	newName, ok := r.renamedTypes[typeKey(t)]
+	if ok {
+		return newName
+	}
+	return t.Name()
+}
+
+// typeRegistry returns the types registered globally combined with the types registered on the schema
+func (c *parseCtx) typeRegistry() *typeRegistry {
+	if c.registry == nil {
+		c.registry = globalTypeRegistry.merge(c.schema.registry)
+	}
+	return c.registry
+}
+
+// Implements registers a new type that implements an interface only for this schema
+// See the package level Implements function for the arguments
+// Types registered using the package level Implements function are also used by this schema
+//
+// Must be called before (*Schema).Parse
+func (s *Schema) Implements(interfaceValue interface{}, typeValue interface{}) error {
+	if s.parsed {
+		return errors.New("(*yarql.Schema).Implements() cannot be ran after (*yarql.Schema).Parse()")
+	}
+	return s.registry.implements(interfaceValue, typeValue)
+}
+
+// TypeRename renames the graphql type of the go type only for this schema
+// See the package level TypeRename function for the arguments, a rename set on the schema takes precedence over a rename set using TypeRename
+//
+// Must be called before (*Schema).Parse
+func (s *Schema) TypeRename(goType interface{}, newName string, force ...bool) error {
+	if s.parsed {
+		return errors.New("(*yarql.Schema).TypeRename() cannot be ran after (*yarql.Schema).Parse()")
+	}
+	_, err := s.registry.rename(goType, newName, len(force) > 0 && force[0])
+	return err
+}
@@ -0,0 +1,109 @@
+package yarql
+
+import (
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type TestRegistryAnimal interface {
+	ResolveSound() string
+}
+
+type TestRegistryDog struct{}
+
+func (TestRegistryDog) ResolveSound() string { return "woof" }
+
+type TestRegistryCat struct{}
+
+func (TestRegistryCat) ResolveSound() string { return "meow" }
+
+type TestRegistryQuery struct {
+	Animals []TestRegistryAnimal
+	Dog     TestRegistryDog
+}
+
+func TestSchemaImplements(t *testing.T) {
+	query := TestRegistryQuery{Animals: []TestRegistryAnimal{TestRegistryDog{}, TestRegistryCat{}}}
+
+	dogs := NewSchema()
+	err := dogs.Implements((*TestRegistryAnimal)(nil), TestRegistryDog{})
+	a.NoError(t, err)
+	err = dogs.Parse(query, M{}, nil)
+	a.NoError(t, err)
+
+	all := NewSchema()
+	err = all.Implements((*TestRegistryAnimal)(nil), TestRegistryDog{})
+	a.NoError(t, err)
+	err = all.Implements((*TestRegistryAnimal)(nil), (*TestRegistryCat)(nil))
+	a.NoError(t, err)
+	err = all.Parse(query, M{}, nil)
+	a.NoError(t, err)
+
+	// The implementations are not registered for this schema
+	err = NewSchema().Parse(query, M{}, nil)
+	a.Error(t, err)
+
+	errs := dogs.Resolve([]byte(`{animals {__typename sound}}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"animals":[{"__typename":"TestRegistryDog","sound":"woof"},null]}`, string(dogs.Result))
+	errs = all.Resolve([]byte(`{animals {__typename sound}}`), ResolveOptions{NoMeta: true})
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"animals":[{"__typename":"TestRegistryDog","sound":"woof"},{"__typename":"TestRegistryCat","sound":"meow"}]}`, string(all.Result))
+
+	a.True(t, strings.Contains(all.SDL(), "type TestRegistryCat implements TestRegistryAnimal {\n"), all.SDL())
+	a.False(t, strings.Contains(dogs.SDL(), "TestRegistryCat"), dogs.SDL())
+
+	err = all.Implements((*TestRegistryAnimal)(nil), TestRegistryCat{})
+	a.Error(t, err, "cannot register implementations after parsing")
+	err = NewSchema().Implements((*TestRegistryAnimal)(nil), TestTypeRenameData{})
+	a.Error(t, err, "the struct doesn't implement the interface")
+}
+
+func TestSchemaTypeRename(t *testing.T) {
+	newSchema := func(name string) *Schema {
+		s := NewSchema()
+		if name != "" {
+			err := s.TypeRename(TestRegistryDog{}, name)
+			a.NoError(t, err)
+		}
+		err := s.Implements((*TestRegistryAnimal)(nil), TestRegistryDog{})
+		a.NoError(t, err)
+		err = s.Parse(TestRegistryQuery{Dog: TestRegistryDog{}}, M{}, nil)
+		a.NoError(t, err)
+		return s
+	}
+
+	schemas := map[string]*Schema{
+		"Hound":           newSchema("Hound"),
+		"Puppy":           newSchema("Puppy"),
+		"TestRegistryDog": newSchema(""),
+	}
+	for name, s := range schemas {
+		errs := s.Resolve([]byte(`{dog {__typename} animals {__typename}}`), ResolveOptions{NoMeta: true})
+		for _, err := range errs {
+			t.Fatal(err)
+		}
+		a.Equal(t, `{"dog":{"__typename":"`+name+`"},"animals":null}`, string(s.Result))
+		a.True(t, strings.Contains(s.SDL(), "type "+name+" implements TestRegistryAnimal {\n"), s.SDL())
+	}
+
+	// A rename on the schema takes precedence over the global rename
+	s := NewSchema()
+	err := s.TypeRename(TestTypeRenameData{}, "Bar")
+	a.NoError(t, err)
+	err = s.Parse(struct{ Data TestTypeRenameData }{}, M{}, nil)
+	a.NoError(t, err)
+	_, ok := s.types["Bar"]
+	a.True(t, ok)
+	_, ok = s.types["Foo"]
+	a.False(t, ok)
+
+	err = s.TypeRename(TestRegistryDog{}, "Hound")
+	a.Error(t, err, "cannot rename types after parsing")
+	err = NewSchema().TypeRename(TestRegistryDog{}, "1Dog")
+	a.Error(t, err, "invalid graphql name")
+	err = NewSchema().TypeRename(TestRegistryDog{}, "1Dog", true)
+	a.NoError(t, err)
+}
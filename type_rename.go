@@ -1,39 +1,48 @@
 package yarql
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"reflect"
 	"strings"
 )
 
-var renamedTypes = map[string]string{}
-
 // TypeRename renames the graphql type of the input type
 // By default the typename of the struct is used but you might want to change this form time to time and with this you can
+// The type is renamed for all schemas, use (*Schema).TypeRename to rename it for a single schema
 func TypeRename(goType interface{}, newName string, force ...bool) string {
+	newName, err := globalTypeRegistry.rename(goType, newName, len(force) > 0 && force[0])
+	if err != nil {
+		log.Panicf("GraphQl %s\n", err.Error())
+	}
+	return newName
+}
+
+func (r *typeRegistry) rename(goType interface{}, newName string, force bool) (string, error) {
 	t := reflect.TypeOf(goType)
+	if t == nil || t.Name() == "" {
+		return "", errors.New("can only rename struct type with type name")
+	}
 	originalName := t.Name()
 
-	if originalName == "" {
-		log.Panicf("GraphQl Can only rename struct type with type name\n")
-	}
 	if t.Kind() != reflect.Struct {
-		log.Panicf("GraphQl Cannot rename type of %s with name: %s and package: %s, can only rename Structs\n", t.Kind().String(), originalName, t.PkgPath())
+		return "", fmt.Errorf("cannot rename type of %s with name: %s and package: %s, can only rename Structs", t.Kind().String(), originalName, t.PkgPath())
 	}
 
 	newName = strings.TrimSpace(newName)
 	if len(newName) == 0 {
-		log.Panicf("GraphQl cannot rename to empty string on type: %s %s\n", t.PkgPath(), originalName)
+		return "", fmt.Errorf("cannot rename to empty string on type: %s %s", t.PkgPath(), originalName)
 	}
 
-	if len(force) == 0 || !force[0] {
+	if !force {
 		err := validGraphQlName([]byte(newName))
 		if err != nil {
-			log.Panicf("GraphQl cannot rename typeof of %s with name %s to %s, err: %s", t.Kind().String(), originalName, newName, err.Error())
+			return "", fmt.Errorf("cannot rename typeof of %s with name %s to %s, err: %s", t.Kind().String(), originalName, newName, err.Error())
 		}
 	}
 
-	renamedTypes[originalName] = newName
+	r.renamedTypes[typeKey(t)] = newName
 
-	return newName
+	return newName, nil
 }
@@ -0,0 +1,163 @@
+package yarql
+
+// VisibilityFunc decides if a type or field is visible for a request
+// fieldName is empty when the visibility of the type itself is checked
+//
+// Hidden types and fields are removed from the introspection and are handled as if they don't exist when executing a query.
+// A field is also hidden when it returns a hidden type or has an argument of a hidden type
+//
+// Example:
+//   func(typeName, fieldName string) bool {
+//     return typeName != "AdminStats" && fieldName != "internalNotes"
+//   }
+type VisibilityFunc func(typeName string, fieldName string) bool
+
+// typeVisible returns true if the type with name is visible for the current request
+func (s *Schema) typeVisible(name string) bool {
+	return s.ctx.visibility == nil || s.ctx.visibility(name, "")
+}
+
+// objFieldVisible returns true if field of parent can be queried in the current request
+// Only call this if the request has a visibility func
+func (s *Schema) objFieldVisible(parent *obj, field *obj) bool {
+	visible := s.ctx.visibility
+	if !visible(parent.typeName, b2s(field.qlFieldName)) || !visible(s.objNamedType(field), "") {
+		return false
+	}
+	if field.valueType == valueTypeMethod {
+		for _, arg := range field.method.inFields {
+			if !visible(s.inputNamedType(&arg.input), "") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// objNamedType returns the name of the inner most graphql type of item, for [User!]! this is User
+func (s *Schema) objNamedType(item *obj) string {
+	for {
+		switch item.valueType {
+		case valueTypeArray, valueTypePtr:
+			item = item.innerContent
+		case valueTypeMethod:
+			item = &item.method.outType
+		case valueTypeObj, valueTypeObjRef, valueTypeInterface, valueTypeInterfaceRef:
+			return item.typeName
+		case valueTypeEnum:
+			return s.definedEnums[item.enumTypeIndex].typeName
+		default:
+			return qlTypeName(resolveObjToScalar(item))
+		}
+	}
+}
+
+// inputNamedType returns the name of the inner most graphql type of in
+func (s *Schema) inputNamedType(in *input) string {
+	res, _ := s.inputToQLType(in)
+	return qlTypeName(res)
+}
+
+// qlTypeName returns the name of the inner most type of t
+func qlTypeName(t *qlType) string {
+	if t == nil {
+		return ""
+	}
+	for t.OfType != nil {
+		t = t.OfType
+	}
+	if t.Name == nil {
+		return ""
+	}
+	return *t.Name
+}
+
+// getVisibleQLTypes returns all types that are visible for the current request
+func (s *Schema) getVisibleQLTypes() []qlType {
+	all := s.getAllQLTypes()
+	if s.ctx.visibility == nil {
+		return all
+	}
+
+	res := make([]qlType, 0, len(all))
+	for _, qlType := range all {
+		if s.typeVisible(*qlType.Name) {
+			res = append(res, s.withVisibleRelations(qlType))
+		}
+	}
+	return res
+}
+
+// getVisibleTypeByName returns the type with name if it's visible for the current request
+func (s *Schema) getVisibleTypeByName(name string) *qlType {
+	if !s.typeVisible(name) {
+		return nil
+	}
+
+	res := s.getTypeByName(name)
+	if res != nil && s.ctx.visibility != nil {
+		*res = s.withVisibleRelations(*res)
+	}
+	return res
+}
+
+// withVisibleRelations removes the hidden interfaces, possible types and input fields from t
+// The fields of objects and interfaces are filtered by getObjFields
+func (s *Schema) withVisibleRelations(t qlType) qlType {
+	if len(t.Interfaces) > 0 {
+		t.Interfaces = s.filterVisibleQLTypes(t.Interfaces)
+	}
+
+	if t.PossibleTypes != nil {
+		possibleTypes := t.PossibleTypes
+		t.PossibleTypes = func() []qlType {
+			return s.filterVisibleQLTypes(possibleTypes())
+		}
+	}
+
+	if t.InputFields != nil {
+		inputFields := t.InputFields
+		t.InputFields = func() []qlInputValue {
+			fields := inputFields()
+			res := make([]qlInputValue, 0, len(fields))
+			for _, field := range fields {
+				if s.typeVisible(qlTypeName(&field.Type)) {
+					res = append(res, field)
+				}
+			}
+			return res
+		}
+	}
+
+	return t
+}
+
+func (s *Schema) filterVisibleQLTypes(types []qlType) []qlType {
+	res := make([]qlType, 0, len(types))
+	for _, qlType := range types {
+		if s.typeVisible(*qlType.Name) {
+			res = append(res, qlType)
+		}
+	}
+	return res
+}
+
+// visibleQLFields removes the fields of typeName that are hidden for the current request
+func (s *Schema) visibleQLFields(typeName string, fields []qlField) []qlField {
+	visible := s.ctx.visibility
+	res := make([]qlField, 0, len(fields))
+
+fieldsLoop:
+	for _, field := range fields {
+		if !visible(typeName, field.Name) || !visible(qlTypeName(&field.Type), "") {
+			continue
+		}
+		for _, arg := range field.Args {
+			if !visible(qlTypeName(&arg.Type), "") {
+				continue fieldsLoop
+			}
+		}
+		res = append(res, field)
+	}
+	return res
+}
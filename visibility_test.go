@@ -0,0 +1,157 @@
+package yarql
+
+import (
+	"strings"
+	"testing"
+
+	a "github.com/mjarkk/yarql/assert"
+)
+
+type VisibilityTestNode interface {
+	ResolveName() string
+}
+
+type VisibilityTestUser struct {
+	Email string
+	Notes string
+}
+
+func (VisibilityTestUser) ResolveName() string {
+	return "user"
+}
+
+type VisibilityTestAdmin struct {
+	Permissions []string
+}
+
+func (VisibilityTestAdmin) ResolveName() string {
+	return "admin"
+}
+
+type VisibilityTestAdminFilter struct {
+	Permission string
+}
+
+type VisibilityTestQuery struct {
+	Users []VisibilityTestUser
+	Admin VisibilityTestAdmin
+}
+
+func (VisibilityTestQuery) ResolveNodes() []VisibilityTestNode {
+	return []VisibilityTestNode{VisibilityTestUser{}, VisibilityTestAdmin{}}
+}
+
+func (VisibilityTestQuery) ResolveAdmins(args struct{ Filter *VisibilityTestAdminFilter }) []string {
+	return []string{"root"}
+}
+
+// visibilityTestPublic hides the admin types and the notes of a user
+func visibilityTestPublic(typeName string, fieldName string) bool {
+	if strings.HasPrefix(typeName, "VisibilityTestAdmin") {
+		return false
+	}
+	return typeName != "VisibilityTestUser" || fieldName != "notes"
+}
+
+func newVisibilityTestSchema(t *testing.T) *Schema {
+	Implements((*VisibilityTestNode)(nil), VisibilityTestUser{})
+	Implements((*VisibilityTestNode)(nil), VisibilityTestAdmin{})
+
+	s := NewSchema()
+	err := s.Parse(VisibilityTestQuery{
+		Users: []VisibilityTestUser{{Email: "a@example.com", Notes: "secret"}},
+		Admin: VisibilityTestAdmin{Permissions: []string{"all"}},
+	}, M{}, nil)
+	a.NoError(t, err)
+	return s.Copy()
+}
+
+func resolveVisibilityTest(t *testing.T, s *Schema, query string, opts ResolveOptions) (string, []error) {
+	opts.NoMeta = true
+	errs := s.Resolve([]byte(query), opts)
+	return string(s.Result), errs
+}
+
+func TestDisableIntrospection(t *testing.T) {
+	s := newVisibilityTestSchema(t)
+
+	for _, query := range []string{`{__schema {queryType {name}}}`, `{__type(name: "VisibilityTestUser") {name}}`} {
+		res, errs := resolveVisibilityTest(t, s, query, ResolveOptions{DisableIntrospection: true})
+		a.Equal(t, 1, len(errs), query)
+		a.True(t, strings.Contains(errs[0].Error(), "introspection is disabled"), errs[0].Error())
+		a.True(t, strings.HasSuffix(res, `:null}`), res)
+
+		// Introspection should work again for the next request
+		_, errs = resolveVisibilityTest(t, s, query, ResolveOptions{})
+		a.Equal(t, 0, len(errs), query)
+	}
+
+	res, errs := resolveVisibilityTest(t, s, `{__typename users {email}}`, ResolveOptions{DisableIntrospection: true})
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"__typename":"VisibilityTestQuery","users":[{"email":"a@example.com"}]}`, res)
+}
+
+func TestVisibilityExecution(t *testing.T) {
+	s := newVisibilityTestSchema(t)
+	opts := ResolveOptions{Visibility: visibilityTestPublic}
+
+	res, errs := resolveVisibilityTest(t, s, `{users {email}}`, opts)
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"users":[{"email":"a@example.com"}]}`, res)
+
+	options := []struct {
+		query string
+		err   string
+	}{
+		{`{users {notes}}`, "notes does not exists on VisibilityTestUser"},
+		{`{admin {permissions}}`, "admin does not exists on VisibilityTestQuery"},
+		{`{admins}`, "admins does not exists on VisibilityTestQuery"},
+	}
+	for _, option := range options {
+		_, errs := resolveVisibilityTest(t, s, option.query, opts)
+		a.Equal(t, 1, len(errs), option.query)
+		a.Equal(t, option.err, errs[0].Error(), option.query)
+
+		// Without a visibility func the field is visible
+		_, errs = resolveVisibilityTest(t, s, option.query, ResolveOptions{})
+		a.Equal(t, 0, len(errs), option.query)
+	}
+
+	// Values of a hidden implementation are returned as null
+	res, errs = resolveVisibilityTest(t, s, `{nodes {name ... on VisibilityTestAdmin {permissions}}}`, opts)
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"nodes":[{"name":"user"},null]}`, res)
+}
+
+func TestVisibilityIntrospection(t *testing.T) {
+	s := newVisibilityTestSchema(t)
+	opts := ResolveOptions{Visibility: visibilityTestPublic}
+
+	res, errs := resolveVisibilityTest(t, s, `{__type(name: "VisibilityTestUser") {fields {name}}}`, opts)
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"__type":{"fields":[{"name":"email"},{"name":"name"}]}}`, res)
+
+	res, errs = resolveVisibilityTest(t, s, `{__type(name: "VisibilityTestAdmin") {name}}`, opts)
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"__type":null}`, res)
+
+	res, errs = resolveVisibilityTest(t, s, `{__type(name: "VisibilityTestNode") {possibleTypes {name}}}`, opts)
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"__type":{"possibleTypes":[{"name":"VisibilityTestUser"}]}}`, res)
+
+	res, errs = resolveVisibilityTest(t, s, `{__schema {queryType {fields {name}}}}`, opts)
+	a.Equal(t, 0, len(errs))
+	a.Equal(t, `{"__schema":{"queryType":{"fields":[{"name":"nodes"},{"name":"users"}]}}}`, res)
+
+	res, errs = resolveVisibilityTest(t, s, `{__schema {types {name}}}`, opts)
+	a.Equal(t, 0, len(errs))
+	a.False(t, strings.Contains(res, "VisibilityTestAdmin"), res)
+	a.True(t, strings.Contains(res, `"VisibilityTestUser"`), res)
+
+	// The introspection caches should not be affected by the visibility func
+	res, errs = resolveVisibilityTest(t, s, `{__schema {types {name}}}`, ResolveOptions{})
+	a.Equal(t, 0, len(errs))
+	a.True(t, strings.Contains(res, `"VisibilityTestAdmin"`), res)
+	a.True(t, strings.Contains(res, `"VisibilityTestAdminFilter"`), res)
+	a.True(t, strings.Contains(s.SDL(), "notes: String!"))
+}